@@ -9,22 +9,36 @@ import (
 
 	"github.com/JetBrainer/sso/internal/adapters/database"
 	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	"github.com/JetBrainer/sso/internal/adapters/notifier"
+	"github.com/JetBrainer/sso/internal/domain/daemons"
+	"github.com/JetBrainer/sso/internal/domain/manager/audit"
 	"github.com/JetBrainer/sso/internal/domain/manager/auth"
+	"github.com/JetBrainer/sso/internal/domain/manager/events"
 	monitoring2 "github.com/JetBrainer/sso/internal/domain/manager/monitoring"
+	"github.com/JetBrainer/sso/internal/domain/manager/outbox"
+	"github.com/JetBrainer/sso/internal/domain/manager/ratelimit"
+	"github.com/JetBrainer/sso/internal/domain/manager/webhooks"
 	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/JetBrainer/sso/internal/domain/templates"
 	"github.com/JetBrainer/sso/internal/domain/validation"
 	"github.com/JetBrainer/sso/internal/ports/configs"
 	"github.com/JetBrainer/sso/internal/ports/grpc"
+	"github.com/JetBrainer/sso/internal/ports/grpc/interceptors"
 	"github.com/JetBrainer/sso/internal/ports/grpc/resources"
 	"github.com/JetBrainer/sso/internal/ports/http"
+	gatewayv2 "github.com/JetBrainer/sso/internal/ports/http/resources/gateway/v2"
 	"github.com/JetBrainer/sso/internal/ports/monitoring"
+	"github.com/JetBrainer/sso/internal/ports/tracing"
 	"github.com/JetBrainer/sso/pkg/logger"
 	"github.com/JetBrainer/sso/pkg/signal"
+	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/sync/errgroup"
 )
 
 var version = "unknown"
 
+const tracingShutdownTimeout = 5 * time.Second
+
 func main() {
 	fmt.Printf("sso %s\n", version)
 
@@ -32,22 +46,65 @@ func main() {
 	opts.EnsureDefaults()
 	opts.Parse()
 
-	logger.Setup("DEBUG")
+	if err := logger.Setup(opts.LogLevel, opts.LogFormat); err != nil {
+		log.Printf("[ERROR] cannot set up logging %s/%s: %v", opts.LogLevel, opts.LogFormat, err)
+		return
+	}
 
 	appCtx, appCtxCancel := context.WithCancel(context.Background())
 	defer appCtxCancel()
 
 	go signal.CatchTermination(appCtxCancel)
 
-	ds, err := setupDatabase(opts)
+	shutdownTracing, err := tracing.Setup(tracing.Config{
+		Exporter:    opts.TracingExporter,
+		Endpoint:    opts.TracingEndpoint,
+		SampleRatio: opts.TracingSampleRatio,
+		Version:     version,
+	})
 	if err != nil {
-		log.Println(err)
+		log.Printf("[ERROR] cannot set up tracing %s: %v", opts.TracingExporter, err)
+		return
+	}
+	defer func() {
+		// отправляем спаны, накопленные до остановки
+		ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			log.Printf("[WARN] cannot flush tracing spans: %v", err)
+		}
+	}()
+
+	dbMetrics := drivers.NewMetrics()
+	ds, err := setupDatabase(opts, dbMetrics)
+	if err != nil {
+		log.Printf("[ERROR] cannot set up datastore %s: %v", opts.DSName, err)
 		return
 	}
 	defer ds.Close()
 
-	metrics := setupMonitoring(appCtx, opts)
-	monitoringManager := monitoring2.New(ds, metrics)
+	notifyMetrics := notifier.NewMetrics()
+	notify, err := setupNotifier(opts, notifyMetrics)
+	if err != nil {
+		log.Printf("[ERROR] cannot set up notifier: %v", err)
+		return
+	}
+
+	metrics := models.NewMetrics()
+	httpMetrics := http.NewMetrics()
+	grpcMetrics := interceptors.NewMetrics()
+
+	var collectors []prometheus.Collector
+	collectors = append(collectors, metrics.Collectors()...)
+	collectors = append(collectors, httpMetrics.Collectors()...)
+	collectors = append(collectors, grpcMetrics.Collectors()...)
+	collectors = append(collectors, dbMetrics.Collectors()...)
+	collectors = append(collectors, notifyMetrics.Collectors()...)
+	setupMonitoring(appCtx, opts, collectors...)
+	monitoringManager := monitoring2.New(ds, metrics).
+		WithNotifier(notify).
+		WithJWTKey([]byte(opts.JWTKey)).
+		WithNode(opts.NodeName)
 	verifyMan := resources.NewVerify(ds)
 
 	authManager := auth.New(
@@ -57,6 +114,28 @@ func main() {
 		time.Duration(opts.RefreshTokenTTLInDays)*time.Hour*24,
 		time.Duration(opts.DelegateTokenTTLInMin)*time.Minute,
 	)
+	webhooksManager := webhooks.New(ds)
+	authManager.WithWebhooks(webhooksManager)
+
+	lockout := auth.DefaultLockoutPolicy()
+	if opts.LockoutFreeAttempts > 0 {
+		lockout.FreeAttempts = opts.LockoutFreeAttempts
+	}
+	if opts.LockoutThreshold > 0 {
+		lockout.Threshold = opts.LockoutThreshold
+	}
+	if opts.LockoutDuration > 0 {
+		lockout.Duration = time.Duration(opts.LockoutDuration) * time.Second
+	}
+	authManager.WithLockout(lockout)
+
+	passwordPolicy, err := setupPasswordPolicy(opts)
+	if err != nil {
+		log.Printf("[ERROR] cannot set up password policy: %v", err)
+		return
+	}
+	authManager.WithPasswordPolicy(passwordPolicy)
+
 	verificationManager := authManager.VerificationManager()
 	if opts.VerifySpamPenalty > 0 {
 		verificationManager.WithSpamPenalty(time.Duration(opts.VerifySpamPenalty) * time.Second)
@@ -67,19 +146,98 @@ func main() {
 		recoveryManager.WithSpamPenalty(time.Duration(opts.RecoverySpamPenalty) * time.Second)
 	}
 
+	if err := authManager.RolesManager().EnsureBuiltIn(appCtx); err != nil {
+		log.Printf("[ERROR] cannot create built-in roles: %v", err)
+		return
+	}
+
+	if opts.PermissionsCacheTTL > 0 {
+		authManager.PermissionsManager().WithCacheTTL(time.Duration(opts.PermissionsCacheTTL) * time.Second)
+	}
+
+	eventsManager := events.New(ds).WithWebhooks(webhooksManager)
+	if opts.VerifySpamPenalty > 0 {
+		eventsManager.WithSpamPenalty(time.Duration(opts.VerifySpamPenalty) * time.Second)
+	}
+
+	tpl, err := templates.New()
+	if err != nil {
+		log.Printf("[ERROR] cannot parse notification templates: %v", err)
+		return
+	}
+
+	eventsd := daemons.NewEventsd(ds, notify, tpl)
+	if opts.EventsdPollInterval > 0 {
+		eventsd.WithPollInterval(time.Duration(opts.EventsdPollInterval) * time.Second)
+	}
+	if opts.EventsdStuckTimeout > 0 {
+		eventsd.WithStuckTimeout(time.Duration(opts.EventsdStuckTimeout) * time.Second)
+	}
+	if opts.OutboxMaxAttempts > 0 {
+		eventsd.WithMaxAttempts(opts.OutboxMaxAttempts)
+	}
+	if opts.WebhookTimeout > 0 {
+		eventsd.WithWebhookTimeout(time.Duration(opts.WebhookTimeout) * time.Second)
+	}
+
+	monitoringManager.WithDaemon("eventsd", eventsd)
+
+	rateLimiter, err := setupRateLimiter(opts, ds)
+	if err != nil {
+		log.Printf("[ERROR] cannot set up rate limiting: %v", err)
+		return
+	}
+
+	trustedProxies, err := http.ParseTrustedProxies(opts.TrustedProxies)
+	if err != nil {
+		log.Printf("[ERROR] cannot parse trusted proxies: %v", err)
+		return
+	}
+
 	servers, serversCtx := errgroup.WithContext(appCtx)
 
 	if opts.IsTesting {
 		log.Printf("[INFO] the service is running in test mode")
 		authManager.Testing()
+		eventsManager.Testing()
+	}
+
+	grpcSrv := grpc.NewAPIServer(
+		serversCtx,
+		opts,
+		grpc.WithAuthManager(authManager),
+		grpc.WithMetrics(grpcMetrics),
+		grpc.WithMonitoringManager(monitoringManager),
+		grpc.WithVersion(version),
+		grpc.WithVerifyManager(verifyMan),
+		grpc.WithDatastore(ds),
+	)
+	ssoServer, ssoInterceptor, err := grpcSrv.InProcess()
+	if err != nil {
+		log.Printf("[ERROR] cannot set up gRPC services: %v", err)
+		return
+	}
+
+	gateway, err := gatewayv2.NewGateway("sso.pkg.go.SSO", ssoServer, ssoInterceptor)
+	if err != nil {
+		log.Printf("[ERROR] cannot set up REST gateway: %v", err)
+		return
 	}
 
 	httpSrv := http.NewAPIServer(
 		serversCtx,
 		opts,
 		http.WithAuthManager(authManager),
+		http.WithAuditManager(audit.New(ds)),
+		http.WithEventsManager(eventsManager),
+		http.WithOutboxManager(outbox.New(ds)),
+		http.WithWebhooksManager(webhooksManager),
+		http.WithRateLimiter(rateLimiter),
+		http.WithTrustedProxies(trustedProxies),
 		http.WithMonitoringManager(monitoringManager),
 		http.WithValidator(validation.New(authManager.Users())),
+		http.WithGateway(gateway),
+		http.WithMetrics(httpMetrics),
 		http.WithVersion(version),
 	)
 	servers.Go(func() error {
@@ -91,14 +249,6 @@ func main() {
 		return nil
 	})
 
-	grpcSrv := grpc.NewAPIServer(
-		serversCtx,
-		opts,
-		grpc.WithAuthManager(authManager),
-		grpc.WithVersion(version),
-		grpc.WithVerifyManager(verifyMan),
-		grpc.WithDatastore(ds),
-	)
 	servers.Go(func() error {
 		if err := grpcSrv.Run(); err != nil {
 			return errors.New(fmt.Sprintf("GRPC server: %v", err))
@@ -108,17 +258,23 @@ func main() {
 		return nil
 	})
 
+	servers.Go(func() error {
+		return eventsd.Run(serversCtx)
+	})
+
 	if err := servers.Wait(); err != nil {
 		log.Printf("[INFO] process terminated, %s", err)
 		return
 	}
 }
 
-func setupDatabase(opts *configs.APIServer) (drivers.DataStore, error) {
+func setupDatabase(opts *configs.APIServer, metrics *drivers.Metrics) (drivers.DataStore, error) {
 	ds, err := database.New(drivers.DataStoreConfig{
-		URL:           opts.DSURL,
-		DataBaseName:  opts.DSDB,
-		DataStoreName: opts.DSName,
+		URL:            opts.DSURL,
+		DataBaseName:   opts.DSDB,
+		DataStoreName:  opts.DSName,
+		Metrics:        metrics,
+		AuditRetention: time.Duration(opts.AuditRetentionDays) * time.Hour * 24,
 	})
 	if err != nil {
 		return nil, err
@@ -134,8 +290,73 @@ func setupDatabase(opts *configs.APIServer) (drivers.DataStore, error) {
 	return ds, nil
 }
 
-func setupMonitoring(ctx context.Context, opts *configs.APIServer) *models.Metrics {
-	promSrv := monitoring.NewPrometheusSrv(ctx, opts.PromListenAddr)
+func setupNotifier(opts *configs.APIServer, metrics *notifier.Metrics) (notifier.Notifier, error) {
+	breakerCooldown := time.Duration(opts.NotifyBreakerCooldown) * time.Second
+
+	sms, err := notifier.New(notifier.Config{
+		Provider:         opts.NotifyProvider,
+		URLs:             opts.NotifyURL,
+		HealthURLs:       opts.NotifyHealthURL,
+		Prefix:           opts.NotifyPrefix,
+		Sender:           opts.NotifySender,
+		File:             opts.NotifyFile,
+		BreakerThreshold: opts.NotifyBreakerThreshold,
+		BreakerCooldown:  breakerCooldown,
+		Metrics:          metrics,
+	})
+	if err != nil {
+		errText := fmt.Sprintf("[ERROR] cannot create notifier %s: %v", opts.NotifyProvider, err)
+		return nil, errors.New(errText)
+	}
+
+	var n notifier.Notifier = sms
+	if opts.SMTPHost != "" {
+		smtp := notifier.NewSMTP(notifier.SMTPConfig{
+			Host:     opts.SMTPHost,
+			Port:     opts.SMTPPort,
+			Username: opts.SMTPUser,
+			Password: opts.SMTPPassword,
+			From:     opts.SMTPFrom,
+			StartTLS: opts.SMTPStartTLS,
+		})
+		email := notifier.NewFailover(opts.NotifyBreakerThreshold, breakerCooldown, smtp).WithMetrics(metrics)
+		n = notifier.NewRouter(n, email)
+	}
+
+	log.Printf("[INFO] notifications are sent by %s", n.Name())
+	if !notifier.SupportsEmail(n) {
+		log.Printf("[WARN] notifier %s cannot deliver email, email notifications will be dead-lettered until SMTP_HOST is set", n.Name())
+	}
+
+	return n, nil
+}
+
+func setupRateLimiter(opts *configs.APIServer, ds drivers.DataStore) (*ratelimit.Limiter, error) {
+	store, err := ratelimit.NewStore(opts.RateLimitBackend, ds)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", opts.RateLimitBackend, err)
+	}
+
+	var policy ratelimit.Policy
+	limits := []struct {
+		limit *models.RateLimit
+		value string
+	}{
+		{&policy.Route, opts.RateLimitRoute},
+		{&policy.IP, opts.RateLimitIP},
+		{&policy.Login, opts.RateLimitLogin},
+	}
+	for _, l := range limits {
+		if *l.limit, err = ratelimit.ParseLimit(l.value); err != nil {
+			return nil, fmt.Errorf("%q: %w", l.value, err)
+		}
+	}
+
+	return ratelimit.New(store, policy), nil
+}
+
+func setupMonitoring(ctx context.Context, opts *configs.APIServer, collectors ...prometheus.Collector) {
+	promSrv := monitoring.NewPrometheusSrv(ctx, opts.PromListenAddr).WithCollectors(collectors...)
 
 	if opts.Prometheus {
 		go func() {
@@ -144,6 +365,23 @@ func setupMonitoring(ctx context.Context, opts *configs.APIServer) *models.Metri
 			}
 		}()
 	}
+}
+
+func setupPasswordPolicy(opts *configs.APIServer) (auth.PasswordPolicy, error) {
+	policy := auth.DefaultPasswordPolicy()
+	if opts.PasswordMinLength > 0 {
+		policy.MinLength = opts.PasswordMinLength
+	}
+	if policy.MinLength > auth.MaxPasswordBytes {
+		return policy, fmt.Errorf("minimal length %d exceeds %d bytes", policy.MinLength, auth.MaxPasswordBytes)
+	}
+
+	require, err := auth.ParsePasswordClasses(opts.PasswordRequire)
+	if err != nil {
+		return policy, err
+	}
+	policy.Require = require
+	policy.DisallowPersonal = !opts.PasswordAllowPersonal
 
-	return promSrv.Metrics()
+	return policy, nil
 }
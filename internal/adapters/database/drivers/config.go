@@ -1,7 +1,15 @@
 package drivers
 
+import "time"
+
 type DataStoreConfig struct {
 	URL           string
 	DataStoreName string
 	DataBaseName  string
+
+	// AuditRetention - время хранения журнала аудита.
+	AuditRetention time.Duration
+
+	// Metrics - метрики времени выполнения операций, может быть nil.
+	Metrics *Metrics
 }
@@ -18,6 +18,7 @@ type DataStore interface {
 	UserCreate(ctx context.Context, user *models.User) (primitive.ObjectID, error)
 	UserByLogin(ctx context.Context, login string) (*models.User, error)
 	UserByTDID(ctx context.Context, tdid primitive.ObjectID) (*models.User, error)
+	UsersByTDID(ctx context.Context, tdidList []primitive.ObjectID) ([]models.User, error)
 	UserFullNamesByTDID(ctx context.Context, tdidList []primitive.ObjectID) (map[string]string, error)
 	UserDevicesByTDID(ctx context.Context, tdid primitive.ObjectID) ([]models.Device, error)
 	UserByEmail(ctx context.Context, email string) (*models.User, error)
@@ -31,14 +32,34 @@ type DataStore interface {
 	RestoreByEmailNew(ctx context.Context, tdid primitive.ObjectID, email, token string, expiredAt time.Time) error
 	RestoreClean(ctx context.Context, tdid primitive.ObjectID) error
 	RestoreIncrementTries(ctx context.Context, tdid primitive.ObjectID) error
+	RestoreAttempt(ctx context.Context, tdid primitive.ObjectID, limit uint8) (*models.Restore, error)
 	RestoreSendNotificationSuccessfully(ctx context.Context, tdid primitive.ObjectID) error
 	RestoreFindNew(ctx context.Context, c chan<- models.User)
 	RestoreFindExpiredAndUpdate(ctx context.Context, c chan<- models.User)
 	RestoreUpdate(ctx context.Context, user *models.User) error
 
+	// учет неудачных попыток входа
+	LockoutFailure(ctx context.Context, tdid primitive.ObjectID, now, resetBefore time.Time) (*models.Lockout, error)
+	LockoutSet(ctx context.Context, tdid primitive.ObjectID, until time.Time, lock bool) error
+	LockoutReset(ctx context.Context, tdid primitive.ObjectID) error
+
+	// верификация
 	VerifyToken(ctx context.Context, token string) error
+	VerifySendNotificationSuccessfully(ctx context.Context, tdid primitive.ObjectID) error
 
 	Roles() RolesRepository
+	Actions() ActionsRepository
+	Events() EventsRepository
+	Outbox() OutboxRepository
+	Webhooks() WebhooksRepository
+	WebhookDeliveries() WebhookDeliveriesRepository
+	Audit() AuditRepository
+	RateLimits() RateLimitsRepository
+	LoginFailures() LoginFailuresRepository
+
+	// Transaction выполняет fn атомарно. Все операции внутри fn должны
+	// использовать переданный ей контекст.
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
 
 	// Проверка на работоспоособность
 	Ping() error
@@ -11,6 +11,7 @@ var ErrUserEmailNotSpec = errors.New("email not specified")
 
 var ErrTokenNotSpec = errors.New("token not specified")
 var ErrTokenNotFound = errors.New("token not found")
+var ErrRestoreTriesExceeded = errors.New("recovery attempts exceeded")
 
 var ErrEmptyRoleStruct = errors.New("empty role structure")
 var ErrRoleDoesNotExist = errors.New("role does not exist")
@@ -18,3 +19,6 @@ var ErrRoleDoesNotExist = errors.New("role does not exist")
 var ErrEmptyStruct = errors.New("empty structure")
 
 var ErrReceiverDoesNotExist = errors.New("the receiver does not exist")
+
+var ErrWatchNotSupported = errors.New("change streams are not supported by the datastore")
+var ErrInvalidResumeToken = errors.New("resume token is invalid or expired")
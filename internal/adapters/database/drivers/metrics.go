@@ -0,0 +1,43 @@
+package drivers
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Результаты операций хранилища в метриках.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// Metrics содержит метрики Prometheus по операциям хранилища.
+// Нулевой указатель допустим: метрики в этом случае не собираются.
+type Metrics struct {
+	duration *prometheus.HistogramVec
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "sso_db_operation_duration_seconds",
+			Help:    "Datastore operation latency by command, collection and outcome.",
+			Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"command", "collection", "outcome"}),
+	}
+}
+
+// Collectors возвращает метрики для регистрации в Prometheus.
+func (m *Metrics) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{m.duration}
+}
+
+// Observe учитывает выполнение команды хранилища над collection.
+func (m *Metrics) Observe(command, collection, outcome string, elapsed time.Duration) {
+	if m == nil {
+		return
+	}
+
+	m.duration.WithLabelValues(command, collection, outcome).Observe(elapsed.Seconds())
+}
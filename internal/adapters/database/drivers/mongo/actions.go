@@ -9,6 +9,7 @@ import (
 
 	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -25,10 +26,15 @@ func (a ActionsRepository) Create(ctx context.Context, action *models.Action) er
 		{Key: "title", Value: action.Title},
 		{Key: "type", Value: action.Type},
 	}
-	if _, err := a.collection.InsertOne(ctx, actionDocument); err != nil {
+	result, err := a.collection.InsertOne(ctx, actionDocument)
+	if err != nil {
 		return errors.Wrap(err, "attempted to create action, got")
 	}
 
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		action.ID = models.PolymorphicID(oid.Hex())
+	}
+
 	return nil
 }
 
@@ -39,7 +45,7 @@ func (a ActionsRepository) ByID(ctx context.Context, id models.PolymorphicID) (*
 	}
 
 	action := new(models.Action)
-	filter := bson.D{{"_id", objID}}
+	filter := bson.D{{Key: "_id", Value: objID}}
 	err = a.collection.FindOne(ctx, filter).Decode(action)
 
 	switch err {
@@ -54,7 +60,7 @@ func (a ActionsRepository) ByID(ctx context.Context, id models.PolymorphicID) (*
 
 func (a ActionsRepository) ByType(ctx context.Context, actionType string) (*models.Action, error) {
 	action := new(models.Action)
-	filter := bson.D{{"type", actionType}}
+	filter := bson.D{{Key: "type", Value: actionType}}
 	err := a.collection.FindOne(ctx, filter).Decode(action)
 
 	switch err {
@@ -98,7 +104,7 @@ func (a ActionsRepository) Update(ctx context.Context, action *models.Action) er
 		return errors.WithMessage(errors2.ErrInvalidID, "cannot continue action search")
 	}
 
-	filter := bson.D{{"_id", objID}}
+	filter := bson.D{{Key: "_id", Value: objID}}
 	update := bson.D{{
 		Key: "$set",
 		Value: bson.D{
@@ -117,7 +123,7 @@ func (a ActionsRepository) Update(ctx context.Context, action *models.Action) er
 		}
 	}
 
-	if result.ModifiedCount == 0 {
+	if result.MatchedCount == 0 {
 		return errors.WithMessage(errors2.ErrDoesNotExist, "cannot find action")
 	}
 
@@ -130,7 +136,7 @@ func (a ActionsRepository) DeleteByID(ctx context.Context, id models.Polymorphic
 		return errors.WithMessage(errors2.ErrInvalidID, "cannot continue action search")
 	}
 
-	filter := bson.D{{"_id", objID}}
+	filter := bson.D{{Key: "_id", Value: objID}}
 
 	result, err := a.collection.DeleteOne(ctx, filter)
 	if err != nil {
@@ -150,7 +156,7 @@ func (a ActionsRepository) DeleteByID(ctx context.Context, id models.Polymorphic
 }
 
 func (a ActionsRepository) DeleteByType(ctx context.Context, actionType string) error {
-	filter := bson.D{{"type", actionType}}
+	filter := bson.D{{Key: "type", Value: actionType}}
 
 	result, err := a.collection.DeleteOne(ctx, filter)
 	if err != nil {
@@ -0,0 +1,88 @@
+package mongo
+
+import (
+	"context"
+
+	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	"github.com/JetBrainer/sso/internal/domain/models"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AuditRepository struct {
+	collection *mongo.Collection
+}
+
+// Append дописывает запись в журнал аудита.
+func (a AuditRepository) Append(ctx context.Context, entry *models.AuditEntry) error {
+	if entry == nil {
+		return errors.WithMessage(drivers.ErrEmptyStruct, "cannot append audit entry")
+	}
+
+	result, err := a.collection.InsertOne(ctx, entry)
+	if err != nil {
+		return errors.Wrap(err, "attempted to append audit entry, got")
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		entry.ID = models.PolymorphicID(oid.Hex())
+	}
+
+	return nil
+}
+
+// List возвращает записи журнала, начиная с самых новых.
+func (a AuditRepository) List(ctx context.Context, filters *models.AuditFilters) ([]models.AuditEntry, error) {
+	filter := bson.D{}
+	opts := options.Find().SetSort(bson.D{{Key: "created", Value: -1}})
+
+	if filters != nil {
+		if filters.TDID != nil {
+			filter = append(filter, bson.E{Key: "tdid", Value: *filters.TDID})
+		}
+		if filters.Actor != nil {
+			filter = append(filter, bson.E{Key: "actor", Value: *filters.Actor})
+		}
+		if filters.Type != nil {
+			filter = append(filter, bson.E{Key: "type", Value: *filters.Type})
+		}
+		if filters.Outcome != nil {
+			filter = append(filter, bson.E{Key: "outcome", Value: *filters.Outcome})
+		}
+
+		created := bson.D{}
+		if filters.From != nil {
+			created = append(created, bson.E{Key: "$gte", Value: *filters.From})
+		}
+		if filters.To != nil {
+			created = append(created, bson.E{Key: "$lt", Value: *filters.To})
+		}
+		if len(created) > 0 {
+			filter = append(filter, bson.E{Key: "created", Value: created})
+		}
+
+		if filters.Limit > 0 {
+			opts.SetLimit(filters.Limit)
+		}
+		if filters.Offset > 0 {
+			opts.SetSkip(filters.Offset)
+		}
+	}
+
+	entries := make([]models.AuditEntry, 0)
+	cur, err := a.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "attempted to find audit entries, got")
+	}
+	defer cur.Close(ctx)
+
+	if err := cur.All(ctx, &entries); err != nil {
+		return nil, errors.Wrap(err, "could not map audit entries from datastore")
+	}
+
+	return entries, nil
+}
@@ -3,6 +3,7 @@ package mongo
 import (
 	"context"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
@@ -17,6 +18,21 @@ const (
 	ensureIdxTimeout  = 20 * time.Second
 	retries           = 1
 	CollectionRoles   = "roles"
+	CollectionActions = "actions"
+	CollectionEvents  = "events"
+	CollectionOutbox  = "outbox"
+
+	CollectionWebhooks          = "webhooks"
+	CollectionWebhookDeliveries = "webhook_deliveries"
+	CollectionAudit             = "audit"
+	CollectionRateLimits        = "rate_limits"
+	CollectionLoginFailures     = "login_failures"
+
+	outboxSentRetention       = 7 * 24 * time.Hour  // время хранения доставленных сообщений outbox
+	webhookDeliveredRetention = 30 * 24 * time.Hour // время хранения журнала доставленных webhook
+
+	DefaultAuditRetention = 365 * 24 * time.Hour // время хранения журнала аудита по умолчанию
+	auditRetentionIndex   = "created_ttl"
 )
 
 type Mongo struct {
@@ -27,8 +43,26 @@ type Mongo struct {
 	DB      *mongo.Database
 	Context context.Context
 
-	rolesRepository *RolesRepository
-	retries         int
+	rolesRepository             *RolesRepository
+	actionsRepository           *ActionsRepository
+	eventsRepository            *EventsRepository
+	outboxRepository            *OutboxRepository
+	webhooksRepository          *WebhooksRepository
+	webhookDeliveriesRepository *WebhookDeliveriesRepository
+	auditRepository             *AuditRepository
+	rateLimitsRepository        *RateLimitsRepository
+	loginFailuresRepository     *LoginFailuresRepository
+	retries                     int
+
+	// auditRetention время хранения журнала аудита
+	auditRetention time.Duration
+
+	// metrics метрики времени выполнения команд, может быть nil; спаны
+	// команд создаются независимо от метрик
+	metrics *drivers.Metrics
+
+	// transactions признак поддержки транзакций (replica set или sharded cluster)
+	transactions bool
 
 	connectionTimeout time.Duration
 	ensureIdxTimeout  time.Duration
@@ -41,9 +75,16 @@ func (m *Mongo) Users(ctx context.Context, paging *interface{}, filters *interfa
 func (m *Mongo) Name() string { return "Mongo" }
 
 func New(conf drivers.DataStoreConfig) (drivers.DataStore, error) {
+	auditRetention := conf.AuditRetention
+	if auditRetention <= 0 {
+		auditRetention = DefaultAuditRetention
+	}
+
 	return &Mongo{
 		MongoURL:          conf.URL,
 		dbname:            conf.DataBaseName,
+		metrics:           conf.Metrics,
+		auditRetention:    auditRetention,
 		retries:           retries,
 		connectionTimeout: connectionTimeout,
 		ensureIdxTimeout:  ensureIdxTimeout,
@@ -57,7 +98,8 @@ func (m *Mongo) Connect() error {
 	defer cancel()
 
 	fmt.Printf("Connecting to %s\n", m.dbname)
-	m.client, err = mongo.Connect(ctx, options.Client().ApplyURI(m.MongoURL))
+	opts := options.Client().ApplyURI(m.MongoURL).SetMonitor(newCommandMonitor(m.metrics))
+	m.client, err = mongo.Connect(ctx, opts)
 	if err != nil {
 		return err
 	}
@@ -68,9 +110,55 @@ func (m *Mongo) Connect() error {
 
 	m.DB = m.client.Database(m.dbname)
 
+	if err := m.detectTransactions(); err != nil {
+		return err
+	}
+
+	return m.ensureIndexes()
+}
+
+// detectTransactions определяет, поддерживает ли развертывание MongoDB транзакции.
+// Транзакции доступны только в replica set и sharded cluster.
+func (m *Mongo) detectTransactions() error {
+	ctx, cancel := context.WithTimeout(context.Background(), m.connectionTimeout)
+	defer cancel()
+
+	var reply struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := m.DB.RunCommand(ctx, bson.D{{Key: "isMaster", Value: 1}}).Decode(&reply); err != nil {
+		return err
+	}
+
+	m.transactions = reply.SetName != "" || reply.Msg == "isdbgrid"
+	if !m.transactions {
+		log.Printf("[WARN] MongoDB is running standalone, state changes and outbox are written without transactions")
+	}
+
 	return nil
 }
 
+// Transaction выполняет fn в транзакции MongoDB. Если развертывание не
+// поддерживает транзакции, fn выполняется без нее.
+func (m *Mongo) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if !m.transactions {
+		return fn(ctx)
+	}
+
+	sess, err := m.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer sess.EndSession(ctx)
+
+	_, err = sess.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+
+	return err
+}
+
 func (m *Mongo) Ping() error {
 	ctx, cancel := context.WithTimeout(context.Background(), m.connectionTimeout)
 	defer cancel()
@@ -92,15 +180,127 @@ func (m *Mongo) Roles() drivers.RolesRepository {
 	return m.rolesRepository
 }
 
+func (m *Mongo) Actions() drivers.ActionsRepository {
+	if m.actionsRepository == nil {
+		m.actionsRepository = &ActionsRepository{
+			collection: m.DB.Collection(CollectionActions),
+		}
+	}
+
+	return m.actionsRepository
+}
+
+func (m *Mongo) Events() drivers.EventsRepository {
+	if m.eventsRepository == nil {
+		m.eventsRepository = &EventsRepository{
+			collection: m.DB.Collection(CollectionEvents),
+		}
+	}
+
+	return m.eventsRepository
+}
+
+func (m *Mongo) Outbox() drivers.OutboxRepository {
+	if m.outboxRepository == nil {
+		m.outboxRepository = &OutboxRepository{
+			collection: m.DB.Collection(CollectionOutbox),
+		}
+	}
+
+	return m.outboxRepository
+}
+
+func (m *Mongo) Webhooks() drivers.WebhooksRepository {
+	if m.webhooksRepository == nil {
+		m.webhooksRepository = &WebhooksRepository{
+			collection: m.DB.Collection(CollectionWebhooks),
+		}
+	}
+
+	return m.webhooksRepository
+}
+
+func (m *Mongo) WebhookDeliveries() drivers.WebhookDeliveriesRepository {
+	if m.webhookDeliveriesRepository == nil {
+		m.webhookDeliveriesRepository = &WebhookDeliveriesRepository{
+			collection: m.DB.Collection(CollectionWebhookDeliveries),
+		}
+	}
+
+	return m.webhookDeliveriesRepository
+}
+
+func (m *Mongo) Audit() drivers.AuditRepository {
+	if m.auditRepository == nil {
+		m.auditRepository = &AuditRepository{
+			collection: m.DB.Collection(CollectionAudit),
+		}
+	}
+
+	return m.auditRepository
+}
+
+func (m *Mongo) RateLimits() drivers.RateLimitsRepository {
+	if m.rateLimitsRepository == nil {
+		m.rateLimitsRepository = &RateLimitsRepository{
+			collection: m.DB.Collection(CollectionRateLimits),
+		}
+	}
+
+	return m.rateLimitsRepository
+}
+
+func (m *Mongo) LoginFailures() drivers.LoginFailuresRepository {
+	if m.loginFailuresRepository == nil {
+		m.loginFailuresRepository = &LoginFailuresRepository{
+			collection: m.DB.Collection(CollectionLoginFailures),
+		}
+	}
+
+	return m.loginFailuresRepository
+}
+
 // убеждается что все индексы построены
 func (m *Mongo) ensureIndexes() error {
-	ctx, cancel := context.WithTimeout(context.Background(), m.connectionTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), m.ensureIdxTimeout)
 	defer cancel()
 
 	if err := m.ensureUsersIndexes(ctx); err != nil {
 		return err
 	}
 
+	if err := m.ensureRolesIndexes(ctx); err != nil {
+		return err
+	}
+
+	if err := m.ensureActionsIndexes(ctx); err != nil {
+		return err
+	}
+
+	if err := m.ensureEventsIndexes(ctx); err != nil {
+		return err
+	}
+
+	if err := m.ensureOutboxIndexes(ctx); err != nil {
+		return err
+	}
+
+	if err := m.ensureWebhooksIndexes(ctx); err != nil {
+		return err
+	}
+
+	if err := m.ensureAuditIndexes(ctx); err != nil {
+		return err
+	}
+
+	if err := m.ensureRateLimitsIndexes(ctx); err != nil {
+		return err
+	}
+
+	if err := m.ensureLoginFailuresIndexes(ctx); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -160,6 +360,158 @@ func (m *Mongo) ensureRolesIndexes(ctx context.Context) error {
 	return err
 }
 
+// ensureActionsIndexes строит индексы для коллекции actions
+func (m *Mongo) ensureActionsIndexes(ctx context.Context) error {
+	col := m.DB.Collection(CollectionActions)
+
+	models := []mongo.IndexModel{
+		{Keys: bson.M{"type": 1}, Options: options.Index().SetUnique(true)},
+	}
+
+	opts := options.CreateIndexes().SetMaxTime(m.ensureIdxTimeout)
+	_, err := col.Indexes().CreateMany(ctx, models, opts)
+
+	return err
+}
+
+// ensureEventsIndexes строит индексы для коллекции events.
+// События удаляются MongoDB автоматически по наступлении expiresAt.
+func (m *Mongo) ensureEventsIndexes(ctx context.Context) error {
+	col := m.DB.Collection(CollectionEvents)
+
+	models := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "tdid", Value: 1}, {Key: "actionType", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.M{"expiresAt": 1}, Options: options.Index().SetExpireAfterSeconds(0)},
+	}
+
+	var err error
+	var exists bool
+	if exists, err = m.indexExistsByName(ctx, col, "verify_token_send_status"); err != nil {
+		return err
+	}
+	if !exists {
+		idx := mongo.IndexModel{Keys: bson.D{{Key: "verify.token", Value: 1}, {Key: "verify.send", Value: 1}, {Key: "verify.status", Value: 1}}, Options: options.Index().SetName("verify_token_send_status")}
+		models = append(models, idx)
+	}
+
+	opts := options.CreateIndexes().SetMaxTime(m.ensureIdxTimeout)
+	_, err = col.Indexes().CreateMany(ctx, models, opts)
+
+	return err
+}
+
+// ensureOutboxIndexes строит индексы для коллекции outbox.
+// Доставленные сообщения удаляются MongoDB через outboxSentRetention.
+func (m *Mongo) ensureOutboxIndexes(ctx context.Context) error {
+	col := m.DB.Collection(CollectionOutbox)
+
+	models := []mongo.IndexModel{
+		{Keys: bson.M{"idempotencyKey": 1}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "nextAttemptAt", Value: 1}}},
+		{Keys: bson.M{"created": -1}},
+		{Keys: bson.M{"sentAt": 1}, Options: options.Index().SetExpireAfterSeconds(int32(outboxSentRetention.Seconds()))},
+	}
+
+	opts := options.CreateIndexes().SetMaxTime(m.ensureIdxTimeout)
+	_, err := col.Indexes().CreateMany(ctx, models, opts)
+
+	return err
+}
+
+// ensureWebhooksIndexes строит индексы для коллекций webhooks и webhook_deliveries.
+// Журнал доставленных событий удаляется MongoDB через webhookDeliveredRetention.
+func (m *Mongo) ensureWebhooksIndexes(ctx context.Context) error {
+	opts := options.CreateIndexes().SetMaxTime(m.ensureIdxTimeout)
+
+	webhooks := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "enabled", Value: 1}, {Key: "events", Value: 1}}},
+	}
+	if _, err := m.DB.Collection(CollectionWebhooks).Indexes().CreateMany(ctx, webhooks, opts); err != nil {
+		return err
+	}
+
+	deliveries := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "eventId", Value: 1}, {Key: "webhookId", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "nextAttemptAt", Value: 1}}},
+		{Keys: bson.D{{Key: "webhookId", Value: 1}, {Key: "created", Value: -1}}},
+		{Keys: bson.M{"created": -1}},
+		{Keys: bson.M{"deliveredAt": 1}, Options: options.Index().SetExpireAfterSeconds(int32(webhookDeliveredRetention.Seconds()))},
+	}
+	_, err := m.DB.Collection(CollectionWebhookDeliveries).Indexes().CreateMany(ctx, deliveries, opts)
+
+	return err
+}
+
+// ensureAuditIndexes строит индексы для коллекции audit. Записи удаляются
+// MongoDB через auditRetention; если срок хранения изменился, он
+// обновляется у существующего индекса.
+func (m *Mongo) ensureAuditIndexes(ctx context.Context) error {
+	col := m.DB.Collection(CollectionAudit)
+	expireAfter := int32(m.auditRetention.Seconds())
+
+	models := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "tdid", Value: 1}, {Key: "created", Value: -1}}},
+		{Keys: bson.D{{Key: "actor", Value: 1}, {Key: "created", Value: -1}}},
+		{Keys: bson.D{{Key: "type", Value: 1}, {Key: "created", Value: -1}}},
+	}
+
+	exists, err := m.indexExistsByName(ctx, col, auditRetentionIndex)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		idx := mongo.IndexModel{Keys: bson.M{"created": 1}, Options: options.Index().SetName(auditRetentionIndex).SetExpireAfterSeconds(expireAfter)}
+		models = append(models, idx)
+	}
+
+	opts := options.CreateIndexes().SetMaxTime(m.ensureIdxTimeout)
+	if _, err := col.Indexes().CreateMany(ctx, models, opts); err != nil {
+		return err
+	}
+
+	if !exists {
+		return nil
+	}
+
+	return m.DB.RunCommand(ctx, bson.D{
+		{Key: "collMod", Value: CollectionAudit},
+		{Key: "index", Value: bson.D{
+			{Key: "name", Value: auditRetentionIndex},
+			{Key: "expireAfterSeconds", Value: expireAfter},
+		}},
+	}).Err()
+}
+
+// ensureRateLimitsIndexes строит индекс, по которому MongoDB удаляет
+// заполнившиеся корзины ограничения частоты запросов.
+func (m *Mongo) ensureRateLimitsIndexes(ctx context.Context) error {
+	col := m.DB.Collection(CollectionRateLimits)
+
+	models := []mongo.IndexModel{
+		{Keys: bson.M{"expires": 1}, Options: options.Index().SetExpireAfterSeconds(0)},
+	}
+
+	opts := options.CreateIndexes().SetMaxTime(m.ensureIdxTimeout)
+	_, err := col.Indexes().CreateMany(ctx, models, opts)
+
+	return err
+}
+
+// ensureLoginFailuresIndexes строит индекс, по которому MongoDB удаляет
+// учет попыток входа с несуществующими логинами, когда он устаревает.
+func (m *Mongo) ensureLoginFailuresIndexes(ctx context.Context) error {
+	col := m.DB.Collection(CollectionLoginFailures)
+
+	models := []mongo.IndexModel{
+		{Keys: bson.M{"expires": 1}, Options: options.Index().SetExpireAfterSeconds(0)},
+	}
+
+	opts := options.CreateIndexes().SetMaxTime(m.ensureIdxTimeout)
+	_, err := col.Indexes().CreateMany(ctx, models, opts)
+
+	return err
+}
+
 // indexExistsByName проверяет существование индекса с именем name.
 func (m *Mongo) indexExistsByName(ctx context.Context, collection *mongo.Collection, name string) (bool, error) {
 	cur, err := collection.Indexes().List(ctx)
@@ -11,6 +11,7 @@ import (
 
 	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -40,10 +41,15 @@ func (e EventsRepository) Create(ctx context.Context, event *models.Event) error
 		eventDocument = append(eventDocument, bson.E{Key: "expiresAt", Value: event.ExpiresAt})
 	}
 
-	if _, err := e.collection.InsertOne(ctx, eventDocument); err != nil {
+	result, err := e.collection.InsertOne(ctx, eventDocument)
+	if err != nil {
 		return errors.Wrap(err, "attempted to create event, got")
 	}
 
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		event.ID = models.PolymorphicID(oid.Hex())
+	}
+
 	return nil
 }
 
@@ -54,7 +60,7 @@ func (e EventsRepository) ByID(ctx context.Context, id models.PolymorphicID) (*m
 	}
 
 	event := new(models.Event)
-	filter := bson.D{{"_id", objID}}
+	filter := bson.D{{Key: "_id", Value: objID}}
 	err = e.collection.FindOne(ctx, filter).Decode(event)
 
 	switch err {
@@ -74,7 +80,7 @@ func (e EventsRepository) ByUserAction(ctx context.Context, tdid models.Polymorp
 	}
 
 	event := new(models.Event)
-	filter := bson.D{{"tdid", objID}, {"actionType", actionType}}
+	filter := bson.D{{Key: "tdid", Value: objID}, {Key: "actionType", Value: actionType}}
 	err = e.collection.FindOne(ctx, filter).Decode(event)
 
 	switch err {
@@ -127,11 +133,16 @@ func (e EventsRepository) VerifyFindNew(ctx context.Context) (*models.Event, err
 	event := new(models.Event)
 	updatedDocStatus := options.After
 	opts := &options.FindOneAndUpdateOptions{ReturnDocument: &updatedDocStatus}
-	if err := e.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(event); err != nil {
-		return nil, err
-	}
+	err := e.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(event)
 
-	return event, nil
+	switch err {
+	case nil:
+		return event, nil
+	case mongo.ErrNoDocuments:
+		return nil, errors.WithMessage(errors2.ErrDoesNotExist, "cannot find new event")
+	default:
+		return nil, errors.Wrap(err, "attempted to find new event, got")
+	}
 }
 
 func (e EventsRepository) Update(ctx context.Context, event *models.Event) error {
@@ -144,7 +155,7 @@ func (e EventsRepository) Update(ctx context.Context, event *models.Event) error
 		return errors.WithMessage(errors2.ErrInvalidID, "cannot continue event search")
 	}
 
-	filter := bson.D{{"_id", objID}}
+	filter := bson.D{{Key: "_id", Value: objID}}
 	eventDocument := bson.D{
 		{Key: "actionType", Value: event.ActionType},
 	}
@@ -169,20 +180,49 @@ func (e EventsRepository) Update(ctx context.Context, event *models.Event) error
 		}
 	}
 
-	if result.ModifiedCount == 0 {
+	if result.MatchedCount == 0 {
 		return errors.WithMessage(errors2.ErrDoesNotExist, "cannot find event")
 	}
 
 	return nil
 }
 
+func (e EventsRepository) VerifyAttempt(ctx context.Context, id models.PolymorphicID, limit uint8) (*models.Event, error) {
+	objID, err := id.ToObjectID()
+	if err != nil {
+		return nil, errors.WithMessage(errors2.ErrInvalidID, "cannot continue event search")
+	}
+
+	// попытка расходуется до сравнения пароля, так что параллельные запросы
+	// не могут прочитать одно и то же количество попыток
+	filter := bson.D{
+		{Key: "_id", Value: objID},
+		{Key: "verify.tries", Value: bson.D{{Key: "$lt", Value: limit}}},
+	}
+	update := bson.D{{Key: "$inc", Value: bson.D{{Key: "verify.tries", Value: 1}}}}
+
+	event := new(models.Event)
+	after := options.After
+	opts := &options.FindOneAndUpdateOptions{ReturnDocument: &after}
+	err = e.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(event)
+
+	switch err {
+	case nil:
+		return event, nil
+	case mongo.ErrNoDocuments:
+		return nil, errors.WithMessage(errors2.ErrTokenTriesExpired, "cannot verify event")
+	default:
+		return nil, errors.Wrap(err, "attempted to verify event, got")
+	}
+}
+
 func (e EventsRepository) ApproveVerificationSendStatus(ctx context.Context, tdid models.PolymorphicID, actionType string) error {
 	objID, err := tdid.ToObjectID()
 	if err != nil {
 		return errors.WithMessage(errors2.ErrInvalidID, "cannot continue event search for provided tdid")
 	}
 
-	filter := bson.D{{"tdid", objID}, {"actionType", actionType}}
+	filter := bson.D{{Key: "tdid", Value: objID}, {Key: "actionType", Value: actionType}}
 	update := bson.D{
 		{Key: "$set",
 			Value: bson.D{
@@ -201,7 +241,7 @@ func (e EventsRepository) ApproveVerificationSendStatus(ctx context.Context, tdi
 		}
 	}
 
-	if result.ModifiedCount == 0 {
+	if result.MatchedCount == 0 {
 		return errors.WithMessage(errors2.ErrDoesNotExist, "cannot find event")
 	}
 
@@ -214,7 +254,7 @@ func (e EventsRepository) DeleteByID(ctx context.Context, id models.PolymorphicI
 		return errors.WithMessage(errors2.ErrInvalidID, "cannot continue event search")
 	}
 
-	filter := bson.D{{"_id", objID}}
+	filter := bson.D{{Key: "_id", Value: objID}}
 	result, err := e.collection.DeleteOne(ctx, filter)
 	if err != nil {
 		switch err {
@@ -0,0 +1,107 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	"github.com/JetBrainer/sso/internal/domain/models"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LockoutFailure учитывает неудачную попытку входа одной атомарной командой,
+// чтобы параллельные попытки не терялись. Если предыдущая неудача была раньше
+// resetBefore, счетчики начинаются заново. Возвращает учет после изменения.
+func (m *Mongo) LockoutFailure(ctx context.Context, tdid primitive.ObjectID, now, resetBefore time.Time) (*models.Lockout, error) {
+	if tdid.IsZero() {
+		return nil, drivers.ErrUserIDNotSpec
+	}
+
+	stale := bson.D{{Key: "$lt", Value: bson.A{
+		bson.D{{Key: "$ifNull", Value: bson.A{"$lockout.last_failure", time.Time{}}}},
+		resetBefore,
+	}}}
+	update := bson.A{
+		bson.D{{Key: "$set", Value: bson.D{
+			{Key: "lockout.failures", Value: bson.D{{Key: "$cond", Value: bson.A{
+				stale, 1, bson.D{{Key: "$add", Value: bson.A{bson.D{{Key: "$ifNull", Value: bson.A{"$lockout.failures", 0}}}, 1}}},
+			}}}},
+			{Key: "lockout.locks", Value: bson.D{{Key: "$cond", Value: bson.A{
+				stale, 0, bson.D{{Key: "$ifNull", Value: bson.A{"$lockout.locks", 0}}},
+			}}}},
+			{Key: "lockout.last_failure", Value: now},
+		}}},
+	}
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetProjection(bson.D{{Key: "lockout", Value: 1}})
+
+	var user struct {
+		Lockout *models.Lockout `bson:"lockout"`
+	}
+	err := m.DB.Collection(CollectionUsers).FindOneAndUpdate(ctx, bson.D{{Key: "_id", Value: tdid}}, update, opts).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		return nil, drivers.ErrUserDoesNotExist
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "attempted to count failed sign in, got")
+	}
+
+	return user.Lockout, nil
+}
+
+// LockoutSet запрещает попытки входа до until. Блокировка (lock) в отличие
+// от задержки между попытками обнуляет счетчик неудач и увеличивает счетчик
+// блокировок.
+func (m *Mongo) LockoutSet(ctx context.Context, tdid primitive.ObjectID, until time.Time, lock bool) error {
+	if tdid.IsZero() {
+		return drivers.ErrUserIDNotSpec
+	}
+
+	set := bson.D{
+		{Key: "lockout.until", Value: until},
+		{Key: "lockout.locked", Value: lock},
+	}
+	update := bson.D{}
+	if lock {
+		set = append(set, bson.E{Key: "lockout.failures", Value: 0})
+		update = append(update, bson.E{Key: "$inc", Value: bson.D{{Key: "lockout.locks", Value: 1}}})
+	}
+	update = append(update, bson.E{Key: "$set", Value: set})
+
+	result, err := m.DB.Collection(CollectionUsers).UpdateOne(ctx, bson.D{{Key: "_id", Value: tdid}}, update)
+	if err != nil {
+		return errors.Wrap(err, "attempted to lock sign in, got")
+	}
+
+	if result.MatchedCount == 0 {
+		return drivers.ErrUserDoesNotExist
+	}
+
+	return nil
+}
+
+// LockoutReset снимает блокировку и удаляет учет неудачных попыток входа.
+func (m *Mongo) LockoutReset(ctx context.Context, tdid primitive.ObjectID) error {
+	if tdid.IsZero() {
+		return drivers.ErrUserIDNotSpec
+	}
+
+	update := bson.D{{Key: "$unset", Value: bson.D{{Key: "lockout", Value: ""}}}}
+
+	result, err := m.DB.Collection(CollectionUsers).UpdateOne(ctx, bson.D{{Key: "_id", Value: tdid}}, update)
+	if err != nil {
+		return errors.Wrap(err, "attempted to reset sign in lockout, got")
+	}
+
+	if result.MatchedCount == 0 {
+		return drivers.ErrUserDoesNotExist
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/domain/models"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LoginFailuresRepository хранит учет неудачных попыток входа с
+// несуществующими логинами. Документ удаляется MongoDB, когда учет
+// устаревает и не действует запрет входа.
+type LoginFailuresRepository struct {
+	collection *mongo.Collection
+}
+
+func (lf LoginFailuresRepository) ByKey(ctx context.Context, key string) (*models.Lockout, error) {
+	lockout := new(models.Lockout)
+	err := lf.collection.FindOne(ctx, bson.D{{Key: "_id", Value: key}}).Decode(lockout)
+	switch err {
+	case nil:
+		return lockout, nil
+	case mongo.ErrNoDocuments:
+		return nil, nil
+	default:
+		return nil, errors.Wrap(err, "attempted to find sign in failures, got")
+	}
+}
+
+// Failure учитывает неудачную попытку одной атомарной командой так же, как
+// Mongo.LockoutFailure, и продлевает хранение учета на время окна.
+func (lf LoginFailuresRepository) Failure(ctx context.Context, key string, now, resetBefore time.Time) (*models.Lockout, error) {
+	stale := bson.D{{Key: "$lt", Value: bson.A{
+		bson.D{{Key: "$ifNull", Value: bson.A{"$last_failure", time.Time{}}}},
+		resetBefore,
+	}}}
+	expires := now.Add(now.Sub(resetBefore))
+	update := bson.A{
+		bson.D{{Key: "$set", Value: bson.D{
+			{Key: "failures", Value: bson.D{{Key: "$cond", Value: bson.A{
+				stale, 1, bson.D{{Key: "$add", Value: bson.A{bson.D{{Key: "$ifNull", Value: bson.A{"$failures", 0}}}, 1}}},
+			}}}},
+			{Key: "locks", Value: bson.D{{Key: "$cond", Value: bson.A{
+				stale, 0, bson.D{{Key: "$ifNull", Value: bson.A{"$locks", 0}}},
+			}}}},
+			{Key: "last_failure", Value: now},
+			{Key: "expires", Value: bson.D{{Key: "$max", Value: bson.A{
+				bson.D{{Key: "$ifNull", Value: bson.A{"$until", time.Time{}}}},
+				expires,
+			}}}},
+		}}},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	lockout := new(models.Lockout)
+	err := lf.collection.FindOneAndUpdate(ctx, bson.D{{Key: "_id", Value: key}}, update, opts).Decode(lockout)
+	if mongo.IsDuplicateKeyError(err) {
+		// документ одновременно создал другой запрос, теперь он существует
+		err = lf.collection.FindOneAndUpdate(ctx, bson.D{{Key: "_id", Value: key}}, update, opts).Decode(lockout)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "attempted to count failed sign in, got")
+	}
+
+	return lockout, nil
+}
+
+// Set запрещает попытки входа до until так же, как Mongo.LockoutSet. Учет
+// хранится, пока действует запрет.
+func (lf LoginFailuresRepository) Set(ctx context.Context, key string, until time.Time, lock bool) error {
+	set := bson.D{
+		{Key: "until", Value: until},
+		{Key: "locked", Value: lock},
+	}
+	update := bson.D{{Key: "$max", Value: bson.D{{Key: "expires", Value: until}}}}
+	if lock {
+		set = append(set, bson.E{Key: "failures", Value: 0})
+		update = append(update, bson.E{Key: "$inc", Value: bson.D{{Key: "locks", Value: 1}}})
+	}
+	update = append(update, bson.E{Key: "$set", Value: set})
+
+	if _, err := lf.collection.UpdateOne(ctx, bson.D{{Key: "_id", Value: key}}, update); err != nil {
+		return errors.Wrap(err, "attempted to lock sign in, got")
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package mongo
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/JetBrainer/sso/internal/adapters/database/drivers/mongo"
+
+// commandMonitor передает время выполнения команд MongoDB в метрики и
+// создает клиентский спан на каждую команду. Имя коллекции есть только в
+// событии начала команды, поэтому оно запоминается по идентификатору
+// запроса до завершения команды.
+type commandMonitor struct {
+	metrics  *drivers.Metrics
+	tracer   trace.Tracer
+	commands sync.Map // RequestID -> *command
+}
+
+type command struct {
+	collection string
+	span       trace.Span
+}
+
+func newCommandMonitor(metrics *drivers.Metrics) *event.CommandMonitor {
+	cm := &commandMonitor{metrics: metrics, tracer: otel.Tracer(tracerName)}
+
+	return &event.CommandMonitor{
+		Started:   cm.started,
+		Succeeded: cm.succeeded,
+		Failed:    cm.failed,
+	}
+}
+
+func (cm *commandMonitor) started(ctx context.Context, e *event.CommandStartedEvent) {
+	// для команд над коллекцией первый элемент - {<команда>: <коллекция>},
+	// getMore передает коллекцию отдельным полем
+	key := e.CommandName
+	if key == "getMore" {
+		key = "collection"
+	}
+
+	collection, _ := e.Command.Lookup(key).StringValueOK()
+
+	_, span := cm.tracer.Start(ctx, e.CommandName+" "+collection,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemMongoDB,
+			semconv.DBNameKey.String(e.DatabaseName),
+			semconv.DBOperationKey.String(e.CommandName),
+			semconv.DBMongoDBCollectionKey.String(collection),
+		),
+	)
+
+	cm.commands.Store(e.RequestID, &command{collection: collection, span: span})
+}
+
+func (cm *commandMonitor) succeeded(_ context.Context, e *event.CommandSucceededEvent) {
+	cm.finish(e.CommandFinishedEvent, drivers.OutcomeSuccess, "")
+}
+
+func (cm *commandMonitor) failed(_ context.Context, e *event.CommandFailedEvent) {
+	cm.finish(e.CommandFinishedEvent, drivers.OutcomeFailure, e.Failure)
+}
+
+func (cm *commandMonitor) finish(e event.CommandFinishedEvent, outcome, failure string) {
+	value, ok := cm.commands.LoadAndDelete(e.RequestID)
+	if !ok {
+		cm.metrics.Observe(e.CommandName, "", outcome, time.Duration(e.DurationNanos))
+		return
+	}
+
+	cmd := value.(*command)
+	cm.metrics.Observe(e.CommandName, cmd.collection, outcome, time.Duration(e.DurationNanos))
+
+	if failure != "" {
+		cmd.span.SetStatus(codes.Error, failure)
+	}
+	cmd.span.End()
+}
@@ -0,0 +1,204 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	errors2 "github.com/JetBrainer/sso/internal/domain/errors"
+	"github.com/JetBrainer/sso/internal/domain/models"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type OutboxRepository struct {
+	collection *mongo.Collection
+}
+
+// Enqueue записывает сообщение в outbox. Сообщение с уже записанным ключом
+// идемпотентности игнорируется. Запись не порождает ошибок дубликата,
+// поэтому безопасна внутри транзакции.
+func (o OutboxRepository) Enqueue(ctx context.Context, msg *models.OutboxMessage) error {
+	if msg == nil {
+		return errors.WithMessage(errors2.ErrEmptyStruct, "cannot enqueue outbox message")
+	}
+
+	filter := bson.D{{Key: "idempotencyKey", Value: msg.IdempotencyKey}}
+	update := bson.D{{Key: "$setOnInsert", Value: msg}}
+	opts := options.Update().SetUpsert(true)
+
+	if _, err := o.collection.UpdateOne(ctx, filter, update, opts); err != nil {
+		return errors.Wrap(err, "attempted to enqueue outbox message, got")
+	}
+
+	return nil
+}
+
+// Claim забирает в отправку одно сообщение, время отправки которого
+// наступило, либо сообщение, аренда которого истекла.
+func (o OutboxRepository) Claim(ctx context.Context, now time.Time, lease time.Duration) (*models.OutboxMessage, error) {
+	filter := bson.D{{Key: "$or", Value: bson.A{
+		bson.D{
+			{Key: "status", Value: models.OutboxStatusPending},
+			{Key: "nextAttemptAt", Value: bson.D{{Key: "$lte", Value: now}}},
+		},
+		bson.D{
+			{Key: "status", Value: models.OutboxStatusSending},
+			{Key: "leaseUntil", Value: bson.D{{Key: "$lte", Value: now}}},
+		},
+	}}}
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "status", Value: models.OutboxStatusSending},
+			{Key: "leaseUntil", Value: now.Add(lease)},
+			{Key: "updated", Value: now},
+		}},
+		{Key: "$inc", Value: bson.D{{Key: "attempts", Value: 1}}},
+	}
+
+	msg := new(models.OutboxMessage)
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetSort(bson.D{{Key: "nextAttemptAt", Value: 1}})
+	err := o.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(msg)
+
+	switch err {
+	case nil:
+		return msg, nil
+	case mongo.ErrNoDocuments:
+		return nil, errors.WithMessage(errors2.ErrDoesNotExist, "no outbox messages to send")
+	default:
+		return nil, errors.Wrap(err, "attempted to claim outbox message, got")
+	}
+}
+
+// MarkSent помечает сообщение доставленным.
+func (o OutboxRepository) MarkSent(ctx context.Context, id models.PolymorphicID) error {
+	now := time.Now().In(time.UTC)
+
+	return o.updateByID(ctx, id, bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "status", Value: models.OutboxStatusSent},
+			{Key: "sentAt", Value: now},
+			{Key: "updated", Value: now},
+		}},
+		{Key: "$unset", Value: bson.D{{Key: "leaseUntil", Value: ""}}},
+	})
+}
+
+// MarkFailed сохраняет ошибку отправки и либо планирует следующую попытку
+// на nextAttemptAt, либо, если dead, переводит сообщение в dead-letter.
+func (o OutboxRepository) MarkFailed(ctx context.Context, id models.PolymorphicID, lastError string, nextAttemptAt time.Time, dead bool) error {
+	status := models.OutboxStatusPending
+	if dead {
+		status = models.OutboxStatusDead
+	}
+
+	return o.updateByID(ctx, id, bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "status", Value: status},
+			{Key: "lastError", Value: lastError},
+			{Key: "nextAttemptAt", Value: nextAttemptAt},
+			{Key: "updated", Value: time.Now().In(time.UTC)},
+		}},
+		{Key: "$unset", Value: bson.D{{Key: "leaseUntil", Value: ""}}},
+	})
+}
+
+// Replay возвращает сообщение из dead-letter в очередь на отправку.
+func (o OutboxRepository) Replay(ctx context.Context, id models.PolymorphicID) error {
+	objID, err := id.ToObjectID()
+	if err != nil {
+		return errors.WithMessage(errors2.ErrInvalidID, "cannot continue outbox message search")
+	}
+
+	now := time.Now().In(time.UTC)
+	filter := bson.D{{Key: "_id", Value: objID}, {Key: "status", Value: models.OutboxStatusDead}}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "status", Value: models.OutboxStatusPending},
+		{Key: "attempts", Value: 0},
+		{Key: "nextAttemptAt", Value: now},
+		{Key: "updated", Value: now},
+	}}}
+
+	result, err := o.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return errors.Wrap(err, "attempted to replay outbox message, got")
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.WithMessage(errors2.ErrDoesNotExist, "cannot find dead outbox message")
+	}
+
+	return nil
+}
+
+func (o OutboxRepository) ByID(ctx context.Context, id models.PolymorphicID) (*models.OutboxMessage, error) {
+	objID, err := id.ToObjectID()
+	if err != nil {
+		return nil, errors.WithMessage(errors2.ErrInvalidID, "cannot continue outbox message search")
+	}
+
+	msg := new(models.OutboxMessage)
+	err = o.collection.FindOne(ctx, bson.D{{Key: "_id", Value: objID}}).Decode(msg)
+
+	switch err {
+	case nil:
+		return msg, nil
+	case mongo.ErrNoDocuments:
+		return nil, errors.WithMessage(errors2.ErrDoesNotExist, "cannot find outbox message")
+	default:
+		return nil, errors.Wrap(err, "attempted to find outbox message, got")
+	}
+}
+
+// List возвращает сообщения, начиная с самых новых.
+func (o OutboxRepository) List(ctx context.Context, filters *models.OutboxFilters) ([]models.OutboxMessage, error) {
+	filter := bson.D{}
+	opts := options.Find().SetSort(bson.D{{Key: "created", Value: -1}})
+
+	if filters != nil {
+		if filters.Status != nil {
+			filter = append(filter, bson.E{Key: "status", Value: *filters.Status})
+		}
+		if filters.Limit > 0 {
+			opts.SetLimit(filters.Limit)
+		}
+		if filters.Offset > 0 {
+			opts.SetSkip(filters.Offset)
+		}
+	}
+
+	messages := make([]models.OutboxMessage, 0)
+	cur, err := o.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "attempted to find outbox messages, got")
+	}
+	defer cur.Close(ctx)
+
+	if err := cur.All(ctx, &messages); err != nil {
+		return nil, errors.Wrap(err, "could not map outbox messages from datastore")
+	}
+
+	return messages, nil
+}
+
+func (o OutboxRepository) updateByID(ctx context.Context, id models.PolymorphicID, update bson.D) error {
+	objID, err := id.ToObjectID()
+	if err != nil {
+		return errors.WithMessage(errors2.ErrInvalidID, "cannot continue outbox message search")
+	}
+
+	result, err := o.collection.UpdateOne(ctx, bson.D{{Key: "_id", Value: objID}}, update)
+	if err != nil {
+		return errors.Wrap(err, "attempted to update outbox message, got")
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.WithMessage(errors2.ErrDoesNotExist, "cannot find outbox message")
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package mongo
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/domain/models"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RateLimitsRepository struct {
+	collection *mongo.Collection
+}
+
+type rateLimitBucket struct {
+	Tokens  float64 `bson:"tokens"`
+	Allowed bool    `bson:"allowed"`
+}
+
+// Take пополняет корзину key за время с прошлого обращения и забирает из нее
+// токен одной атомарной командой, так что корзина общая для всех экземпляров
+// сервиса. Корзина удаляется MongoDB, когда она заполнится.
+func (rl RateLimitsRepository) Take(ctx context.Context, key string, limit models.RateLimit, now time.Time) (time.Duration, error) {
+	// даты в BSON хранятся с точностью до миллисекунды
+	now = now.Truncate(time.Millisecond)
+	burst := float64(limit.Burst)
+	perMs := burst / float64(limit.Period/time.Millisecond)
+
+	elapsed := bson.D{{Key: "$max", Value: bson.A{0, bson.D{{Key: "$subtract", Value: bson.A{
+		now, bson.D{{Key: "$ifNull", Value: bson.A{"$updated", now}}},
+	}}}}}}
+	refilled := bson.D{{Key: "$min", Value: bson.A{burst, bson.D{{Key: "$add", Value: bson.A{
+		bson.D{{Key: "$ifNull", Value: bson.A{"$tokens", burst}}},
+		bson.D{{Key: "$multiply", Value: bson.A{elapsed, perMs}}},
+	}}}}}}
+	hasToken := bson.D{{Key: "$gte", Value: bson.A{"$tokens", 1}}}
+
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.D{
+			{Key: "tokens", Value: refilled},
+			{Key: "updated", Value: now},
+			{Key: "expires", Value: now.Add(limit.Period)},
+		}}},
+		{{Key: "$set", Value: bson.D{
+			{Key: "allowed", Value: hasToken},
+			{Key: "tokens", Value: bson.D{{Key: "$cond", Value: bson.A{
+				hasToken, bson.D{{Key: "$subtract", Value: bson.A{"$tokens", 1}}}, "$tokens",
+			}}}},
+		}}},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var bucket rateLimitBucket
+	err := rl.collection.FindOneAndUpdate(ctx, bson.D{{Key: "_id", Value: key}}, update, opts).Decode(&bucket)
+	if mongo.IsDuplicateKeyError(err) {
+		// корзину одновременно создал другой запрос, теперь она существует
+		err = rl.collection.FindOneAndUpdate(ctx, bson.D{{Key: "_id", Value: key}}, update, opts).Decode(&bucket)
+	}
+	if err != nil {
+		return 0, errors.Wrap(err, "attempted to take rate limit token, got")
+	}
+
+	if bucket.Allowed {
+		return 0, nil
+	}
+
+	return time.Duration(math.Ceil((1-bucket.Tokens)/perMs)) * time.Millisecond, nil
+}
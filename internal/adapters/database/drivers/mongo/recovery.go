@@ -2,15 +2,17 @@ package mongo
 
 import (
 	"context"
-	"log"
 	"time"
 
 	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	"github.com/JetBrainer/sso/internal/domain"
 	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/JetBrainer/sso/pkg/logger"
 	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // RestoreUserByToken возвращает пользователя по токену восстановления.
@@ -37,7 +39,7 @@ func (m *Mongo) RestoreUserByToken(ctx context.Context, token string) (*models.U
 }
 
 // RestoreFindExpiredAndUpdate находит просроченные восстановления
-// и убирает из них токены.
+// и убирает из них токены. Канал закрывается, когда восстановлений больше нет.
 func (m *Mongo) RestoreFindExpiredAndUpdate(ctx context.Context, c chan<- models.User) {
 	collection := m.DB.Collection(CollectionUsers)
 
@@ -58,6 +60,8 @@ func (m *Mongo) RestoreFindExpiredAndUpdate(ctx context.Context, c chan<- models
 	}
 
 	go func() {
+		defer close(c)
+
 		for {
 			var u models.User
 
@@ -66,19 +70,24 @@ func (m *Mongo) RestoreFindExpiredAndUpdate(ctx context.Context, c chan<- models
 
 			switch err {
 			case nil:
-				c <- u
+				select {
+				case c <- u:
+				case <-ctx.Done():
+					return
+				}
 			case mongo.ErrNoDocuments:
 				return
 			default:
-				log.Printf("[ERROR] mongo unhandeled error in RestoreFindExpiredAndUpdate(): %v\n", err)
+				logger.Printf(ctx, "[ERROR] mongo unhandeled error in RestoreFindExpiredAndUpdate(): %v", err)
 				return
 			}
 		}
 	}()
 }
 
-// RestoreFindNew находит все новые восстановления паролей для пользователя.
-// Используется для того, чтобы послать им уведомления.
+// RestoreFindNew находит все новые восстановления паролей для пользователя
+// и переводит их в статус on_check. Используется для того, чтобы послать им
+// уведомления. Канал закрывается, когда новых восстановлений больше нет.
 func (m *Mongo) RestoreFindNew(ctx context.Context, c chan<- models.User) {
 	collection := m.DB.Collection(CollectionUsers)
 
@@ -87,17 +96,19 @@ func (m *Mongo) RestoreFindNew(ctx context.Context, c chan<- models.User) {
 			Value: bson.D{{Key: "$ne", Value: ""}},
 		},
 		{Key: "restore.send", Value: false},
-		{Key: "restore.status", Value: "new"},
+		{Key: "restore.status", Value: domain.TokenStatusNew},
 	}
 	update := bson.D{
 		{Key: "$set",
 			Value: bson.D{
-				{Key: "restore.status", Value: "on_check"},
+				{Key: "restore.status", Value: domain.TokenStatusOnCheck},
 			},
 		},
 	}
 
 	go func() {
+		defer close(c)
+
 		for {
 			var u models.User
 
@@ -106,11 +117,15 @@ func (m *Mongo) RestoreFindNew(ctx context.Context, c chan<- models.User) {
 
 			switch err {
 			case nil:
-				c <- u
+				select {
+				case c <- u:
+				case <-ctx.Done():
+					return
+				}
 			case mongo.ErrNoDocuments:
 				return
 			default:
-				log.Printf("[ERROR] mongo unhandeled error in RestoreFindNew(): %v\n", err)
+				logger.Printf(ctx, "[ERROR] mongo unhandeled error in RestoreFindNew(): %v", err)
 				return
 			}
 		}
@@ -144,6 +159,8 @@ func (m *Mongo) RestoreSendNotificationSuccessfully(ctx context.Context, tdid pr
 	return nil
 }
 
+// VerifySendNotificationSuccessfully проставляет признак успешности отправки пользователю
+// токена верификации.
 func (m *Mongo) VerifySendNotificationSuccessfully(ctx context.Context, tdid primitive.ObjectID) error {
 	if tdid.IsZero() {
 		return drivers.ErrUserIDNotSpec
@@ -186,6 +203,7 @@ func (m *Mongo) RestoreByEmailNew(ctx context.Context, tdid primitive.ObjectID,
 				{Key: "restore.status", Value: "new"},
 				{Key: "restore.method", Value: "email"},
 				{Key: "restore.email", Value: email},
+				{Key: "restore.tries", Value: 0},
 			},
 		},
 	}
@@ -300,6 +318,38 @@ func (m *Mongo) RestoreIncrementTries(ctx context.Context, tdid primitive.Object
 	return nil
 }
 
+// RestoreAttempt расходует попытку ввода кода восстановления до его проверки
+// одной атомарной командой, так что параллельные запросы не могут
+// использовать одну попытку. Возвращает восстановление после изменения или
+// ErrRestoreTriesExceeded, если попыток не осталось.
+func (m *Mongo) RestoreAttempt(ctx context.Context, tdid primitive.ObjectID, limit uint8) (*models.Restore, error) {
+	if tdid.IsZero() {
+		return nil, drivers.ErrUserIDNotSpec
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: tdid},
+		{Key: "restore.tries", Value: bson.D{{Key: "$lt", Value: limit}}},
+	}
+	update := bson.D{{Key: "$inc", Value: bson.D{{Key: "restore.tries", Value: 1}}}}
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetProjection(bson.D{{Key: "restore", Value: 1}})
+
+	var user struct {
+		Restore *models.Restore `bson:"restore"`
+	}
+	err := m.DB.Collection(CollectionUsers).FindOneAndUpdate(ctx, filter, update, opts).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		return nil, drivers.ErrRestoreTriesExceeded
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "attempted to count recovery attempt, got")
+	}
+
+	return user.Restore, nil
+}
+
 func (m *Mongo) RestoreUpdate(ctx context.Context, user *models.User) error {
 	if user == nil {
 		return drivers.ErrEmptyUserStruct
@@ -43,11 +43,16 @@ func (rr *RolesRepository) Create(ctx context.Context, role *models.Role) error
 func (rr *RolesRepository) RoleByName(ctx context.Context, name string) (*models.Role, error) {
 	role := new(models.Role)
 
-	if err := rr.collection.FindOne(ctx, bson.D{{Key: "name", Value: name}}).Decode(role); err != nil {
+	err := rr.collection.FindOne(ctx, bson.D{{Key: "name", Value: name}}).Decode(role)
+
+	switch err {
+	case nil:
+		return role, nil
+	case mongo.ErrNoDocuments:
+		return nil, drivers.ErrRoleDoesNotExist
+	default:
 		return nil, err
 	}
-
-	return role, nil
 }
 
 func (rr *RolesRepository) Update(ctx context.Context, role *models.Role) error {
@@ -70,7 +75,7 @@ func (rr *RolesRepository) Update(ctx context.Context, role *models.Role) error
 		return err
 	}
 
-	if result.ModifiedCount == 0 {
+	if result.MatchedCount == 0 {
 		return drivers.ErrRoleDoesNotExist
 	}
 
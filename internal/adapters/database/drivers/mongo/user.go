@@ -211,15 +211,41 @@ func (m *Mongo) UserFullNamesByTDID(ctx context.Context, tdidList []primitive.Ob
 	return fullNames, nil
 }
 
+// UsersByTDID возвращает найденных пользователей из списка tdid.
+// Отсутствующие пользователи пропускаются.
+func (m *Mongo) UsersByTDID(ctx context.Context, tdidList []primitive.ObjectID) ([]models.User, error) {
+	filter := bson.D{{Key: "_id", Value: bson.D{
+		{Key: "$in", Value: tdidList},
+	}}}
+
+	cursor, err := m.DB.Collection(CollectionUsers).Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	users := make([]models.User, 0, len(tdidList))
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
 func (m *Mongo) UserDevicesByTDID(ctx context.Context, tdid primitive.ObjectID) ([]models.Device, error) {
 	filter := bson.D{{Key: "_id", Value: tdid}}
 
 	user := new(models.User)
-	if err := m.DB.Collection(CollectionUsers).FindOne(ctx, filter).Decode(user); err != nil {
+	err := m.DB.Collection(CollectionUsers).FindOne(ctx, filter).Decode(user)
+
+	switch err {
+	case nil:
+		return user.Devices, nil
+	case mongo.ErrNoDocuments:
+		return nil, drivers.ErrUserDoesNotExist
+	default:
 		return nil, err
 	}
-
-	return user.Devices, nil
 }
 func (m *Mongo) searchFilters(filters *models.UsersSearchFilters) bson.D {
 	queryFilters := bson.D{}
@@ -259,6 +285,10 @@ func (m *Mongo) searchFilters(filters *models.UsersSearchFilters) bson.D {
 				Options: "i",
 			}})
 		}
+
+		if filters.Role != nil {
+			queryFilters = append(queryFilters, bson.E{Key: "roles", Value: *filters.Role})
+		}
 	}
 
 	return queryFilters
@@ -0,0 +1,166 @@
+package mongo
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	"github.com/JetBrainer/sso/internal/domain/models"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Коды ошибок сервера, означающие, что с указанной позиции поток продолжить нельзя.
+const (
+	codeInvalidResumeToken      = 260
+	codeChangeStreamFatalError  = 280
+	codeChangeStreamHistoryLost = 286
+)
+
+// internalUserFields служебные поля пользователя. Изменения только этих
+// полей (учет попыток входа, коды восстановления и подтверждения, отметки об
+// отправке уведомлений) не транслируются подписчикам.
+var internalUserFields = map[string]bool{
+	"lockout":       true,
+	"restore":       true,
+	"verify":        true,
+	"last_verified": true,
+	"password":      true,
+	"updated":       true,
+}
+
+// userChangeEvent событие change stream коллекции пользователей.
+type userChangeEvent struct {
+	OperationType string              `bson:"operationType"`
+	ClusterTime   primitive.Timestamp `bson:"clusterTime"`
+	DocumentKey   struct {
+		ID primitive.ObjectID `bson:"_id"`
+	} `bson:"documentKey"`
+	FullDocument      *models.User `bson:"fullDocument"`
+	UpdateDescription struct {
+		UpdatedFields bson.M   `bson:"updatedFields"`
+		RemovedFields []string `bson:"removedFields"`
+	} `bson:"updateDescription"`
+}
+
+// WatchUsers читает изменения пользователей из change stream MongoDB.
+// Change streams доступны только в replica set и sharded cluster.
+func (m *Mongo) WatchUsers(ctx context.Context, resumeToken string, fn func(change *models.UserChange) error) error {
+	if !m.transactions {
+		return drivers.ErrWatchNotSupported
+	}
+
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+	if resumeToken != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(resumeToken)
+		if err != nil {
+			return drivers.ErrInvalidResumeToken
+		}
+		opts.SetResumeAfter(bson.Raw(raw))
+	}
+
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: bson.D{
+		{Key: "operationType", Value: bson.D{{Key: "$in", Value: bson.A{"insert", "update", "replace", "delete"}}}},
+	}}}}
+
+	stream, err := m.DB.Collection(CollectionUsers).Watch(ctx, pipeline, opts)
+	if err != nil {
+		return watchError(err)
+	}
+	defer stream.Close(context.Background())
+
+	for stream.Next(ctx) {
+		var event userChangeEvent
+		if err := stream.Decode(&event); err != nil {
+			return errors.Wrap(err, "decoding user change event")
+		}
+
+		if event.internal() {
+			continue
+		}
+
+		change := event.change()
+		change.ResumeToken = base64.RawURLEncoding.EncodeToString(stream.ResumeToken())
+
+		if err := fn(change); err != nil {
+			return err
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return watchError(stream.Err())
+}
+
+// internal сообщает, что обновление затронуло только служебные поля.
+func (e *userChangeEvent) internal() bool {
+	if e.OperationType != "update" {
+		return false
+	}
+
+	fields := e.UpdateDescription.RemovedFields
+	for field := range e.UpdateDescription.UpdatedFields {
+		fields = append(fields, field)
+	}
+
+	for _, field := range fields {
+		// вложенные поля приходят в виде "restore.token"
+		if i := strings.IndexByte(field, '.'); i >= 0 {
+			field = field[:i]
+		}
+		if !internalUserFields[field] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (e *userChangeEvent) change() *models.UserChange {
+	change := &models.UserChange{
+		TDID: e.DocumentKey.ID,
+		User: e.FullDocument,
+		Time: time.Unix(int64(e.ClusterTime.T), 0).In(time.UTC),
+	}
+
+	switch e.OperationType {
+	case "insert":
+		change.Type = models.UserChangeCreated
+	case "delete":
+		change.Type = models.UserChangeDeleted
+	default:
+		change.Type = models.UserChangeUpdated
+		if enabled, ok := e.UpdateDescription.UpdatedFields["enabled"].(bool); ok {
+			change.Type = models.UserChangeDisabled
+			if enabled {
+				change.Type = models.UserChangeEnabled
+			}
+		}
+	}
+
+	// пользователь мог быть удален до того, как было прочитано изменение
+	if change.User == nil && change.Type != models.UserChangeDeleted {
+		change.Type = models.UserChangeDeleted
+	}
+
+	return change
+}
+
+func watchError(err error) error {
+	var se mongo.ServerError
+	if errors.As(err, &se) && (se.HasErrorCode(codeInvalidResumeToken) ||
+		se.HasErrorCode(codeChangeStreamFatalError) ||
+		se.HasErrorCode(codeChangeStreamHistoryLost)) {
+		return drivers.ErrInvalidResumeToken
+	}
+
+	return err
+}
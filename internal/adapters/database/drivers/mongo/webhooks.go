@@ -0,0 +1,337 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	errors2 "github.com/JetBrainer/sso/internal/domain/errors"
+	"github.com/JetBrainer/sso/internal/domain/models"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type WebhooksRepository struct {
+	collection *mongo.Collection
+}
+
+func (wr WebhooksRepository) Create(ctx context.Context, webhook *models.Webhook) error {
+	if webhook == nil {
+		return errors.WithMessage(drivers.ErrEmptyStruct, "cannot create new webhook")
+	}
+
+	result, err := wr.collection.InsertOne(ctx, webhook)
+	if err != nil {
+		return errors.Wrap(err, "attempted to create webhook, got")
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		webhook.ID = models.PolymorphicID(oid.Hex())
+	}
+
+	return nil
+}
+
+func (wr WebhooksRepository) ByID(ctx context.Context, id models.PolymorphicID) (*models.Webhook, error) {
+	objID, err := id.ToObjectID()
+	if err != nil {
+		return nil, errors.WithMessage(errors2.ErrInvalidID, "cannot continue webhook search")
+	}
+
+	webhook := new(models.Webhook)
+	err = wr.collection.FindOne(ctx, bson.D{{Key: "_id", Value: objID}}).Decode(webhook)
+
+	switch err {
+	case nil:
+		return webhook, nil
+	case mongo.ErrNoDocuments:
+		return nil, errors.WithMessage(errors2.ErrDoesNotExist, "cannot find webhook")
+	default:
+		return nil, errors.Wrap(err, "attempted to find webhook, got")
+	}
+}
+
+func (wr WebhooksRepository) All(ctx context.Context) ([]models.Webhook, error) {
+	return wr.find(ctx, bson.D{})
+}
+
+// Subscribed возвращает включенные webhook, подписанные на событие event.
+func (wr WebhooksRepository) Subscribed(ctx context.Context, event string) ([]models.Webhook, error) {
+	return wr.find(ctx, bson.D{
+		{Key: "enabled", Value: true},
+		{Key: "events", Value: bson.D{{Key: "$in", Value: bson.A{event, models.WebhookEventAll}}}},
+	})
+}
+
+func (wr WebhooksRepository) Update(ctx context.Context, webhook *models.Webhook) error {
+	if webhook == nil {
+		return errors.WithMessage(drivers.ErrEmptyStruct, "cannot update webhook")
+	}
+
+	objID, err := webhook.ID.ToObjectID()
+	if err != nil {
+		return errors.WithMessage(errors2.ErrInvalidID, "cannot continue webhook search")
+	}
+
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "url", Value: webhook.URL},
+		{Key: "secret", Value: webhook.Secret},
+		{Key: "events", Value: webhook.Events},
+		{Key: "description", Value: webhook.Description},
+		{Key: "enabled", Value: webhook.Enabled},
+		{Key: "updated", Value: webhook.Updated},
+	}}}
+
+	result, err := wr.collection.UpdateOne(ctx, bson.D{{Key: "_id", Value: objID}}, update)
+	if err != nil {
+		return errors.Wrap(err, "attempted to update webhook, got")
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.WithMessage(errors2.ErrDoesNotExist, "cannot find webhook")
+	}
+
+	return nil
+}
+
+func (wr WebhooksRepository) DeleteByID(ctx context.Context, id models.PolymorphicID) error {
+	objID, err := id.ToObjectID()
+	if err != nil {
+		return errors.WithMessage(errors2.ErrInvalidID, "cannot continue webhook search")
+	}
+
+	result, err := wr.collection.DeleteOne(ctx, bson.D{{Key: "_id", Value: objID}})
+	if err != nil {
+		return errors.Wrap(err, "attempted to delete webhook, got")
+	}
+
+	if result.DeletedCount == 0 {
+		return errors.WithMessage(errors2.ErrDoesNotExist, "cannot find webhook")
+	}
+
+	return nil
+}
+
+func (wr WebhooksRepository) find(ctx context.Context, filter bson.D) ([]models.Webhook, error) {
+	webhooks := make([]models.Webhook, 0)
+
+	cur, err := wr.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created", Value: 1}}))
+	if err != nil {
+		return nil, errors.Wrap(err, "attempted to find webhooks, got")
+	}
+	defer cur.Close(ctx)
+
+	if err := cur.All(ctx, &webhooks); err != nil {
+		return nil, errors.Wrap(err, "could not map webhooks from datastore")
+	}
+
+	return webhooks, nil
+}
+
+type WebhookDeliveriesRepository struct {
+	collection *mongo.Collection
+}
+
+// Enqueue записывает отправку события подписчику. Повторная запись того же
+// события для того же подписчика игнорируется, поэтому безопасна внутри
+// транзакции.
+func (wd WebhookDeliveriesRepository) Enqueue(ctx context.Context, delivery *models.WebhookDelivery) error {
+	if delivery == nil {
+		return errors.WithMessage(drivers.ErrEmptyStruct, "cannot enqueue webhook delivery")
+	}
+
+	filter := bson.D{
+		{Key: "eventId", Value: delivery.EventID},
+		{Key: "webhookId", Value: delivery.WebhookID},
+	}
+	update := bson.D{{Key: "$setOnInsert", Value: delivery}}
+	opts := options.Update().SetUpsert(true)
+
+	if _, err := wd.collection.UpdateOne(ctx, filter, update, opts); err != nil {
+		return errors.Wrap(err, "attempted to enqueue webhook delivery, got")
+	}
+
+	return nil
+}
+
+// Claim забирает в отправку одну доставку, время которой наступило, либо
+// доставку, аренда которой истекла.
+func (wd WebhookDeliveriesRepository) Claim(ctx context.Context, now time.Time, lease time.Duration) (*models.WebhookDelivery, error) {
+	filter := bson.D{{Key: "$or", Value: bson.A{
+		bson.D{
+			{Key: "status", Value: models.WebhookDeliveryPending},
+			{Key: "nextAttemptAt", Value: bson.D{{Key: "$lte", Value: now}}},
+		},
+		bson.D{
+			{Key: "status", Value: models.WebhookDeliverySending},
+			{Key: "leaseUntil", Value: bson.D{{Key: "$lte", Value: now}}},
+		},
+	}}}
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "status", Value: models.WebhookDeliverySending},
+			{Key: "leaseUntil", Value: now.Add(lease)},
+			{Key: "updated", Value: now},
+		}},
+		{Key: "$inc", Value: bson.D{{Key: "attempts", Value: 1}}},
+	}
+
+	delivery := new(models.WebhookDelivery)
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetSort(bson.D{{Key: "nextAttemptAt", Value: 1}})
+	err := wd.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(delivery)
+
+	switch err {
+	case nil:
+		return delivery, nil
+	case mongo.ErrNoDocuments:
+		return nil, errors.WithMessage(errors2.ErrDoesNotExist, "no webhook deliveries to send")
+	default:
+		return nil, errors.Wrap(err, "attempted to claim webhook delivery, got")
+	}
+}
+
+// MarkDelivered помечает доставку выполненной.
+func (wd WebhookDeliveriesRepository) MarkDelivered(ctx context.Context, id models.PolymorphicID, responseStatus int) error {
+	now := time.Now().In(time.UTC)
+
+	return wd.updateByID(ctx, id, bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "status", Value: models.WebhookDeliveryDelivered},
+			{Key: "responseStatus", Value: responseStatus},
+			{Key: "deliveredAt", Value: now},
+			{Key: "updated", Value: now},
+		}},
+		{Key: "$unset", Value: bson.D{
+			{Key: "leaseUntil", Value: ""},
+			{Key: "lastError", Value: ""},
+		}},
+	})
+}
+
+// MarkFailed сохраняет результат неудачной попытки и либо планирует следующую
+// на nextAttemptAt, либо, если dead, прекращает попытки.
+func (wd WebhookDeliveriesRepository) MarkFailed(ctx context.Context, id models.PolymorphicID, responseStatus int, lastError string, nextAttemptAt time.Time, dead bool) error {
+	status := models.WebhookDeliveryPending
+	if dead {
+		status = models.WebhookDeliveryDead
+	}
+
+	return wd.updateByID(ctx, id, bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "status", Value: status},
+			{Key: "responseStatus", Value: responseStatus},
+			{Key: "lastError", Value: lastError},
+			{Key: "nextAttemptAt", Value: nextAttemptAt},
+			{Key: "updated", Value: time.Now().In(time.UTC)},
+		}},
+		{Key: "$unset", Value: bson.D{{Key: "leaseUntil", Value: ""}}},
+	})
+}
+
+// Replay возвращает прекращенную доставку в очередь на отправку.
+func (wd WebhookDeliveriesRepository) Replay(ctx context.Context, id models.PolymorphicID) error {
+	objID, err := id.ToObjectID()
+	if err != nil {
+		return errors.WithMessage(errors2.ErrInvalidID, "cannot continue webhook delivery search")
+	}
+
+	now := time.Now().In(time.UTC)
+	filter := bson.D{{Key: "_id", Value: objID}, {Key: "status", Value: models.WebhookDeliveryDead}}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "status", Value: models.WebhookDeliveryPending},
+		{Key: "attempts", Value: 0},
+		{Key: "nextAttemptAt", Value: now},
+		{Key: "updated", Value: now},
+	}}}
+
+	result, err := wd.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return errors.Wrap(err, "attempted to replay webhook delivery, got")
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.WithMessage(errors2.ErrDoesNotExist, "cannot find dead webhook delivery")
+	}
+
+	return nil
+}
+
+func (wd WebhookDeliveriesRepository) ByID(ctx context.Context, id models.PolymorphicID) (*models.WebhookDelivery, error) {
+	objID, err := id.ToObjectID()
+	if err != nil {
+		return nil, errors.WithMessage(errors2.ErrInvalidID, "cannot continue webhook delivery search")
+	}
+
+	delivery := new(models.WebhookDelivery)
+	err = wd.collection.FindOne(ctx, bson.D{{Key: "_id", Value: objID}}).Decode(delivery)
+
+	switch err {
+	case nil:
+		return delivery, nil
+	case mongo.ErrNoDocuments:
+		return nil, errors.WithMessage(errors2.ErrDoesNotExist, "cannot find webhook delivery")
+	default:
+		return nil, errors.Wrap(err, "attempted to find webhook delivery, got")
+	}
+}
+
+// List возвращает журнал отправок, начиная с самых новых.
+func (wd WebhookDeliveriesRepository) List(ctx context.Context, filters *models.WebhookDeliveryFilters) ([]models.WebhookDelivery, error) {
+	filter := bson.D{}
+	opts := options.Find().SetSort(bson.D{{Key: "created", Value: -1}})
+
+	if filters != nil {
+		if filters.WebhookID != nil {
+			filter = append(filter, bson.E{Key: "webhookId", Value: *filters.WebhookID})
+		}
+		if filters.Event != nil {
+			filter = append(filter, bson.E{Key: "event", Value: *filters.Event})
+		}
+		if filters.Status != nil {
+			filter = append(filter, bson.E{Key: "status", Value: *filters.Status})
+		}
+		if filters.Limit > 0 {
+			opts.SetLimit(filters.Limit)
+		}
+		if filters.Offset > 0 {
+			opts.SetSkip(filters.Offset)
+		}
+	}
+
+	deliveries := make([]models.WebhookDelivery, 0)
+	cur, err := wd.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "attempted to find webhook deliveries, got")
+	}
+	defer cur.Close(ctx)
+
+	if err := cur.All(ctx, &deliveries); err != nil {
+		return nil, errors.Wrap(err, "could not map webhook deliveries from datastore")
+	}
+
+	return deliveries, nil
+}
+
+func (wd WebhookDeliveriesRepository) updateByID(ctx context.Context, id models.PolymorphicID, update bson.D) error {
+	objID, err := id.ToObjectID()
+	if err != nil {
+		return errors.WithMessage(errors2.ErrInvalidID, "cannot continue webhook delivery search")
+	}
+
+	result, err := wd.collection.UpdateOne(ctx, bson.D{{Key: "_id", Value: objID}}, update)
+	if err != nil {
+		return errors.Wrap(err, "attempted to update webhook delivery, got")
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.WithMessage(errors2.ErrDoesNotExist, "cannot find webhook delivery")
+	}
+
+	return nil
+}
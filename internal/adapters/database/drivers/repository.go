@@ -2,6 +2,7 @@ package drivers
 
 import (
 	"context"
+	"time"
 
 	"github.com/JetBrainer/sso/internal/domain/models"
 )
@@ -14,3 +15,93 @@ type RolesRepository interface {
 	DeleteByName(ctx context.Context, name string) error
 }
 
+type ActionsRepository interface {
+	Create(ctx context.Context, action *models.Action) error
+	ByID(ctx context.Context, id models.PolymorphicID) (*models.Action, error)
+	ByType(ctx context.Context, actionType string) (*models.Action, error)
+	All(ctx context.Context) ([]models.Action, error)
+	Update(ctx context.Context, action *models.Action) error
+	DeleteByID(ctx context.Context, id models.PolymorphicID) error
+	DeleteByType(ctx context.Context, actionType string) error
+}
+
+type EventsRepository interface {
+	Create(ctx context.Context, event *models.Event) error
+	ByID(ctx context.Context, id models.PolymorphicID) (*models.Event, error)
+	ByUserAction(ctx context.Context, tdid models.PolymorphicID, actionType string) (*models.Event, error)
+	All(ctx context.Context) ([]models.Event, error)
+	VerifyFindNew(ctx context.Context) (*models.Event, error)
+	Update(ctx context.Context, event *models.Event) error
+	// VerifyAttempt атомарно расходует попытку ввода одноразового пароля и
+	// возвращает событие после этого. Если попыток не осталось, возвращает
+	// ErrTokenTriesExpired.
+	VerifyAttempt(ctx context.Context, id models.PolymorphicID, limit uint8) (*models.Event, error)
+	ApproveVerificationSendStatus(ctx context.Context, tdid models.PolymorphicID, actionType string) error
+	DeleteByID(ctx context.Context, id models.PolymorphicID) error
+}
+
+type OutboxRepository interface {
+	Enqueue(ctx context.Context, msg *models.OutboxMessage) error
+	Claim(ctx context.Context, now time.Time, lease time.Duration) (*models.OutboxMessage, error)
+	MarkSent(ctx context.Context, id models.PolymorphicID) error
+	MarkFailed(ctx context.Context, id models.PolymorphicID, lastError string, nextAttemptAt time.Time, dead bool) error
+	Replay(ctx context.Context, id models.PolymorphicID) error
+	ByID(ctx context.Context, id models.PolymorphicID) (*models.OutboxMessage, error)
+	List(ctx context.Context, filters *models.OutboxFilters) ([]models.OutboxMessage, error)
+}
+
+type WebhooksRepository interface {
+	Create(ctx context.Context, webhook *models.Webhook) error
+	ByID(ctx context.Context, id models.PolymorphicID) (*models.Webhook, error)
+	All(ctx context.Context) ([]models.Webhook, error)
+	Subscribed(ctx context.Context, event string) ([]models.Webhook, error)
+	Update(ctx context.Context, webhook *models.Webhook) error
+	DeleteByID(ctx context.Context, id models.PolymorphicID) error
+}
+
+type WebhookDeliveriesRepository interface {
+	Enqueue(ctx context.Context, delivery *models.WebhookDelivery) error
+	Claim(ctx context.Context, now time.Time, lease time.Duration) (*models.WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, id models.PolymorphicID, responseStatus int) error
+	MarkFailed(ctx context.Context, id models.PolymorphicID, responseStatus int, lastError string, nextAttemptAt time.Time, dead bool) error
+	Replay(ctx context.Context, id models.PolymorphicID) error
+	ByID(ctx context.Context, id models.PolymorphicID) (*models.WebhookDelivery, error)
+	List(ctx context.Context, filters *models.WebhookDeliveryFilters) ([]models.WebhookDelivery, error)
+}
+
+// AuditRepository хранит журнал аудита. Записи только добавляются,
+// старые записи удаляются хранилищем по истечении срока хранения.
+type AuditRepository interface {
+	Append(ctx context.Context, entry *models.AuditEntry) error
+	List(ctx context.Context, filters *models.AuditFilters) ([]models.AuditEntry, error)
+}
+
+// RateLimitsRepository хранит корзины токенов ограничения частоты запросов,
+// общие для всех экземпляров сервиса.
+type RateLimitsRepository interface {
+	// Take забирает токен из корзины key. Если корзина пуста, возвращает
+	// время до появления следующего токена.
+	Take(ctx context.Context, key string, limit models.RateLimit, now time.Time) (time.Duration, error)
+}
+
+// LoginFailuresRepository учитывает неудачные попытки входа с логинами, для
+// которых нет учетной записи, чтобы ответ на них не отличался от ответа для
+// существующих учетных записей.
+type LoginFailuresRepository interface {
+	// ByKey возвращает учет попыток для логина key или nil, если его нет.
+	ByKey(ctx context.Context, key string) (*models.Lockout, error)
+	// Failure учитывает неудачную попытку так же, как DataStore.LockoutFailure.
+	Failure(ctx context.Context, key string, now, resetBefore time.Time) (*models.Lockout, error)
+	// Set запрещает попытки входа до until так же, как DataStore.LockoutSet.
+	Set(ctx context.Context, key string, until time.Time, lock bool) error
+}
+
+// UsersWatcher реализуется хранилищами, которые умеют сами отдавать поток
+// изменений пользователей. Если хранилище не реализует интерфейс или
+// возвращает ErrWatchNotSupported, изменения транслируются внутри процесса.
+type UsersWatcher interface {
+	// WatchUsers вызывает fn для каждого изменения пользователей после позиции
+	// resumeToken (с текущего момента, если позиция не указана), пока не
+	// отменен ctx или fn не вернет ошибку.
+	WatchUsers(ctx context.Context, resumeToken string, fn func(change *models.UserChange) error) error
+}
@@ -0,0 +1,137 @@
+package notifier
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	DefaultBreakerThreshold = 5                // количество ошибок подряд, после которого провайдер отключается
+	DefaultBreakerCooldown  = time.Second * 30 // время, на которое отключается провайдер
+)
+
+// Состояния автоматического выключателя.
+const (
+	BreakerClosed   = "closed"    // провайдер работает
+	BreakerOpen     = "open"      // провайдер отключен до истечения cooldown
+	BreakerHalfOpen = "half-open" // провайдеру разрешен один пробный запрос
+)
+
+// Breaker - автоматический выключатель провайдера. После threshold ошибок
+// подряд запросы к провайдеру не выполняются в течение cooldown, затем
+// пропускается один пробный запрос: успех возвращает провайдер в работу,
+// ошибка снова отключает его.
+type Breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	state     string
+	failures  int
+	openedAt  time.Time
+	trial     bool // пробный запрос в состоянии half-open уже выполняется
+	lastError string
+	now       func() time.Time
+}
+
+func NewBreaker(threshold int, cooldown time.Duration) *Breaker {
+	if threshold < 1 {
+		threshold = DefaultBreakerThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultBreakerCooldown
+	}
+
+	return &Breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		state:     BreakerClosed,
+		now:       time.Now,
+	}
+}
+
+// Allow сообщает, можно ли выполнить запрос к провайдеру.
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		b.trial = true
+		return true
+	case BreakerHalfOpen:
+		if b.trial {
+			return false
+		}
+		b.trial = true
+		return true
+	default:
+		return true
+	}
+}
+
+// Success фиксирует успешный запрос.
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = BreakerClosed
+	b.failures = 0
+	b.trial = false
+	b.lastError = ""
+}
+
+// Failure фиксирует ошибку запроса.
+func (b *Breaker) Failure(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.trial = false
+	if err != nil {
+		b.lastError = err.Error()
+	}
+
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		b.state = BreakerOpen
+		b.openedAt = b.now()
+	}
+}
+
+// Release снимает отметку о пробном запросе, результат которого не говорит
+// о работоспособности провайдера (например, запрос был отменен).
+func (b *Breaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+}
+
+// State возвращает текущее состояние выключателя.
+func (b *Breaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.cooldown {
+		return BreakerHalfOpen
+	}
+
+	return b.state
+}
+
+// Status возвращает состояние выключателя для отчета о здоровье.
+func (b *Breaker) Status() ProviderStatus {
+	state := b.State()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return ProviderStatus{
+		State:     state,
+		Failures:  b.failures,
+		LastError: b.lastError,
+	}
+}
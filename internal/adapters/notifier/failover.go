@@ -0,0 +1,206 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/JetBrainer/sso/pkg/logger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Каналы уведомлений в метриках.
+const (
+	channelSMS   = "sms"
+	channelEmail = "email"
+)
+
+var ErrNoProviderAvailable = errors.New("all notifier providers are unavailable")
+
+var tracer = otel.Tracer("github.com/JetBrainer/sso/internal/adapters/notifier")
+
+// ProviderStatus состояние провайдера уведомлений.
+type ProviderStatus struct {
+	Name      string
+	State     string // состояние выключателя: closed/open/half-open
+	Failures  int    // ошибок подряд
+	LastError string
+}
+
+// StatusReporter сообщает состояние провайдеров уведомлений.
+type StatusReporter interface {
+	ProvidersStatus() []ProviderStatus
+}
+
+type provider struct {
+	Notifier
+	breaker *Breaker
+}
+
+// Failover отправляет уведомления через первого доступного провайдера из
+// списка. Ошибка провайдера переводит отправку на следующего, а провайдер,
+// ошибающийся подряд, отключается своим выключателем и не задерживает
+// отправку, пока не истечет время отключения.
+type Failover struct {
+	providers []*provider
+	metrics   *Metrics
+}
+
+// NewFailover создает список провайдеров в порядке приоритета.
+func NewFailover(threshold int, cooldown time.Duration, providers ...Notifier) *Failover {
+	f := &Failover{providers: make([]*provider, 0, len(providers))}
+	for _, n := range providers {
+		f.providers = append(f.providers, &provider{
+			Notifier: n,
+			breaker:  NewBreaker(threshold, cooldown),
+		})
+	}
+
+	return f
+}
+
+// WithMetrics устанавливает метрики провайдеров.
+func (f *Failover) WithMetrics(m *Metrics) *Failover {
+	f.metrics = m
+	for _, p := range f.providers {
+		m.setState(p.Name(), p.breaker.State())
+	}
+
+	return f
+}
+
+func (f *Failover) Name() string {
+	if len(f.providers) == 1 {
+		return f.providers[0].Name()
+	}
+
+	names := make([]string, 0, len(f.providers))
+	for _, p := range f.providers {
+		names = append(names, p.Name())
+	}
+
+	return fmt.Sprintf("failover(%s)", strings.Join(names, ","))
+}
+
+func (f *Failover) SendSMS(ctx context.Context, sms SMS) error {
+	return f.send(ctx, channelSMS, func(ctx context.Context, n Notifier) error {
+		return n.SendSMS(ctx, sms)
+	})
+}
+
+func (f *Failover) SendEmail(ctx context.Context, email Email) error {
+	return f.send(ctx, channelEmail, func(ctx context.Context, n Notifier) error {
+		return n.SendEmail(ctx, email)
+	})
+}
+
+// SupportsEmail сообщает, может ли письма доставить хотя бы один провайдер.
+func (f *Failover) SupportsEmail() bool {
+	for _, p := range f.providers {
+		if SupportsEmail(p.Notifier) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// send перебирает провайдеров, пока один из них не отправит уведомление.
+// Провайдеры, не поддерживающие канал, пропускаются без учета в выключателе.
+func (f *Failover) send(ctx context.Context, channel string, fn func(ctx context.Context, n Notifier) error) error {
+	var lastErr error
+	rejected := false
+
+	for _, p := range f.providers {
+		if !p.breaker.Allow() {
+			rejected = true
+			f.metrics.observe(p.Name(), channel, outcomeRejected, 0)
+			continue
+		}
+
+		spanCtx, span := tracer.Start(ctx, "notifier "+channel, trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attribute.String("notifier.provider", p.Name()), attribute.String("notifier.channel", channel)))
+
+		start := time.Now()
+		err := fn(spanCtx, p.Notifier)
+		elapsed := time.Since(start)
+
+		if err != nil && !errors.Is(err, ErrChannelNotSupported) {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+
+		switch {
+		case err == nil:
+			p.breaker.Success()
+			f.metrics.observe(p.Name(), channel, outcomeSuccess, elapsed)
+			f.metrics.setState(p.Name(), p.breaker.State())
+			return nil
+		case errors.Is(err, ErrChannelNotSupported):
+			p.breaker.Release()
+			continue
+		case ctx.Err() != nil:
+			p.breaker.Release()
+			return ctx.Err()
+		}
+
+		p.breaker.Failure(err)
+		f.metrics.observe(p.Name(), channel, outcomeFailure, elapsed)
+		f.metrics.setState(p.Name(), p.breaker.State())
+
+		logger.Printf(ctx, "[WARN] notifier %s failed to send %s: %v", p.Name(), channel, err)
+		lastErr = fmt.Errorf("%s: %w", p.Name(), err)
+	}
+
+	switch {
+	case lastErr != nil:
+		return lastErr
+	case rejected:
+		return ErrNoProviderAvailable
+	default:
+		return ErrChannelNotSupported
+	}
+}
+
+// Ping считает отправку работоспособной, если доступен хотя бы один
+// провайдер с замкнутым выключателем.
+func (f *Failover) Ping(ctx context.Context) error {
+	errs := make([]string, 0, len(f.providers))
+
+	for _, p := range f.providers {
+		if p.breaker.State() == BreakerOpen {
+			errs = append(errs, fmt.Sprintf("%s: circuit is open", p.Name()))
+			continue
+		}
+
+		err := p.Ping(ctx)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", p.Name(), err))
+	}
+
+	if len(errs) == 0 {
+		return ErrNoProviderAvailable
+	}
+
+	return fmt.Errorf("%w: %s", ErrNoProviderAvailable, strings.Join(errs, "; "))
+}
+
+// ProvidersStatus возвращает состояние выключателей провайдеров в порядке
+// приоритета.
+func (f *Failover) ProvidersStatus() []ProviderStatus {
+	statuses := make([]ProviderStatus, 0, len(f.providers))
+	for _, p := range f.providers {
+		s := p.breaker.Status()
+		s.Name = p.Name()
+		statuses = append(statuses, s)
+	}
+
+	return statuses
+}
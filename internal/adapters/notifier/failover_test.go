@@ -0,0 +1,108 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFailover_SendSMS(t *testing.T) {
+	ctx := context.Background()
+	primary, secondary := NewFake(), NewFake()
+	f := NewFailover(2, time.Minute, primary, secondary).WithMetrics(NewMetrics())
+
+	now := time.Now()
+	for _, p := range f.providers {
+		p.breaker.now = func() time.Time { return now }
+	}
+
+	if err := f.SendSMS(ctx, SMS{Phone: "87071234567", Text: "1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(primary.SMS()) != 1 || len(secondary.SMS()) != 0 {
+		t.Fatalf("sms must be sent by primary provider")
+	}
+
+	primary.FailWith(errors.New("gateway is down"))
+	for i := 0; i < 2; i++ {
+		if err := f.SendSMS(ctx, SMS{Phone: "87071234567", Text: "2"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(secondary.SMS()) != 2 {
+		t.Fatalf("sms must fail over to secondary provider, got %d", len(secondary.SMS()))
+	}
+	if state := f.ProvidersStatus()[0].State; state != BreakerOpen {
+		t.Fatalf("primary breaker must be open, got %s", state)
+	}
+
+	// разомкнутый выключатель не пропускает запросы к провайдеру
+	primary.FailWith(nil)
+	if err := f.SendSMS(ctx, SMS{Phone: "87071234567", Text: "3"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(primary.SMS()) != 1 {
+		t.Fatalf("open breaker must skip primary provider")
+	}
+
+	// после cooldown пробный запрос возвращает провайдер в работу
+	now = now.Add(time.Minute)
+	if err := f.SendSMS(ctx, SMS{Phone: "87071234567", Text: "4"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(primary.SMS()) != 2 {
+		t.Fatalf("trial request must be sent by primary provider")
+	}
+	if state := f.ProvidersStatus()[0].State; state != BreakerClosed {
+		t.Fatalf("primary breaker must be closed, got %s", state)
+	}
+}
+
+func TestFailover_AllFailed(t *testing.T) {
+	ctx := context.Background()
+	primary, secondary := NewFake(), NewFake()
+	primary.FailWith(errors.New("primary is down"))
+	secondary.FailWith(errors.New("secondary is down"))
+
+	f := NewFailover(1, time.Minute, primary, secondary)
+	if err := f.SendSMS(ctx, SMS{Phone: "87071234567"}); err == nil {
+		t.Fatalf("expected error")
+	}
+	if err := f.SendSMS(ctx, SMS{Phone: "87071234567"}); !errors.Is(err, ErrNoProviderAvailable) {
+		t.Fatalf("expected ErrNoProviderAvailable, got %v", err)
+	}
+	if err := f.Ping(ctx); !errors.Is(err, ErrNoProviderAvailable) {
+		t.Fatalf("expected ErrNoProviderAvailable on ping, got %v", err)
+	}
+}
+
+func TestFailover_ChannelNotSupported(t *testing.T) {
+	ctx := context.Background()
+	sms, email := NewHTTPSMS("http://127.0.0.1:0", "", ""), NewFake()
+
+	f := NewFailover(1, time.Minute, sms, email)
+	if err := f.SendEmail(ctx, Email{To: "user@example.com"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(email.Emails()) != 1 {
+		t.Fatalf("email must be sent by provider supporting it")
+	}
+	if state := f.ProvidersStatus()[0].State; state != BreakerClosed {
+		t.Fatalf("unsupported channel must not open breaker, got %s", state)
+	}
+}
+
+func TestSupportsEmail(t *testing.T) {
+	sms := NewHTTPSMS("http://sms.local", "sso", "SSO")
+
+	if SupportsEmail(NewFailover(1, time.Minute, sms)) {
+		t.Fatalf("sms gateway must not support email")
+	}
+	if !SupportsEmail(NewFailover(1, time.Minute, sms, NewFake())) {
+		t.Fatalf("failover must support email if any provider does")
+	}
+	if !SupportsEmail(NewRouter(sms, NewFake())) {
+		t.Fatalf("router must support email by email provider")
+	}
+}
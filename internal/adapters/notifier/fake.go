@@ -0,0 +1,137 @@
+package notifier
+
+import (
+	"context"
+	"sync"
+
+	"github.com/JetBrainer/sso/utils"
+)
+
+// Fake запоминает уведомления в памяти вместо отправки.
+// Предназначен для тестов: отправленные сообщения можно посмотреть,
+// а отказ провайдера сымитировать через FailWith. Как и реальный провайдер,
+// повторно не принимает сообщение с уже полученным ключом идемпотентности.
+type Fake struct {
+	mu     sync.RWMutex
+	sms    []SMS
+	emails []Email
+	err    error
+}
+
+func NewFake() *Fake {
+	return &Fake{}
+}
+
+func (f *Fake) Name() string {
+	return "fake"
+}
+
+func (f *Fake) SendSMS(_ context.Context, sms SMS) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return f.err
+	}
+
+	if sms.IdempotencyKey != "" {
+		for _, s := range f.sms {
+			if s.IdempotencyKey == sms.IdempotencyKey {
+				return nil
+			}
+		}
+	}
+
+	f.sms = append(f.sms, sms)
+	return nil
+}
+
+func (f *Fake) SendEmail(_ context.Context, email Email) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return f.err
+	}
+
+	if email.IdempotencyKey != "" {
+		for _, e := range f.emails {
+			if e.IdempotencyKey == email.IdempotencyKey {
+				return nil
+			}
+		}
+	}
+
+	f.emails = append(f.emails, email)
+	return nil
+}
+
+func (f *Fake) Ping(_ context.Context) error {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.err
+}
+
+// FailWith заставляет все последующие вызовы возвращать err.
+// nil возвращает провайдер в рабочее состояние.
+func (f *Fake) FailWith(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.err = err
+}
+
+// SMS возвращает все отправленные SMS в порядке отправки.
+func (f *Fake) SMS() []SMS {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return append([]SMS(nil), f.sms...)
+}
+
+// Emails возвращает все отправленные письма в порядке отправки.
+func (f *Fake) Emails() []Email {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return append([]Email(nil), f.emails...)
+}
+
+// LastSMS возвращает последнее SMS, отправленное на номер phone.
+func (f *Fake) LastSMS(phone string) (SMS, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	phone = utils.NormPhoneNum(phone)
+	for i := len(f.sms) - 1; i >= 0; i-- {
+		if utils.NormPhoneNum(f.sms[i].Phone) == phone {
+			return f.sms[i], true
+		}
+	}
+
+	return SMS{}, false
+}
+
+// LastEmail возвращает последнее письмо, отправленное на адрес to.
+func (f *Fake) LastEmail(to string) (Email, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	for i := len(f.emails) - 1; i >= 0; i-- {
+		if f.emails[i].To == to {
+			return f.emails[i], true
+		}
+	}
+
+	return Email{}, false
+}
+
+// Reset забывает все отправленные уведомления.
+func (f *Fake) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.sms = nil
+	f.emails = nil
+}
@@ -0,0 +1,144 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/JetBrainer/sso/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const defaultHTTPTimeout = time.Second * 10
+
+// HTTPSMS отправляет SMS через HTTP шлюз.
+type HTTPSMS struct {
+	name      string
+	client    *http.Client
+	url       string
+	healthURL string
+	prefix    string
+	sender    string
+}
+
+// smsRequest тело запроса к SMS шлюзу.
+type smsRequest struct {
+	Prefix  string `json:"prefix"`
+	Sender  string `json:"sender"`
+	Phone   string `json:"phone"`
+	Message string `json:"message"`
+}
+
+func NewHTTPSMS(url, prefix, sender string) *HTTPSMS {
+	return &HTTPSMS{
+		name:   "http",
+		client: &http.Client{Timeout: defaultHTTPTimeout},
+		url:    url,
+		prefix: prefix,
+		sender: sender,
+	}
+}
+
+// WithHealthURL устанавливает адрес проверки работоспособности шлюза.
+// Если он не задан, проверяется доступность адреса отправки.
+func (n *HTTPSMS) WithHealthURL(url string) *HTTPSMS {
+	n.healthURL = url
+	return n
+}
+
+// WithName устанавливает название шлюза, чтобы различать несколько шлюзов
+// в метриках и отчете о здоровье.
+func (n *HTTPSMS) WithName(name string) *HTTPSMS {
+	n.name = name
+	return n
+}
+
+// WithClient устанавливает HTTP клиент, которым выполняются запросы к шлюзу.
+func (n *HTTPSMS) WithClient(client *http.Client) *HTTPSMS {
+	n.client = client
+	return n
+}
+
+func (n *HTTPSMS) Name() string {
+	return n.name
+}
+
+func (n *HTTPSMS) SendSMS(ctx context.Context, sms SMS) error {
+	body, err := json.Marshal(smsRequest{
+		Prefix:  n.prefix,
+		Sender:  n.sender,
+		Phone:   utils.NormPhoneNum(sms.Phone),
+		Message: sms.Text,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	if sms.IdempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", sms.IdempotencyKey)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sms gateway: %w", err)
+	}
+	defer drain(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("sms gateway: unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (n *HTTPSMS) SendEmail(_ context.Context, _ Email) error {
+	return ErrChannelNotSupported
+}
+
+// SupportsEmail SMS шлюз не доставляет письма.
+func (n *HTTPSMS) SupportsEmail() bool {
+	return false
+}
+
+// Ping считает шлюз доступным, если он отвечает без ошибки сервера.
+func (n *HTTPSMS) Ping(ctx context.Context) error {
+	method, url := http.MethodHead, n.url
+	if n.healthURL != "" {
+		method, url = http.MethodGet, n.healthURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sms gateway: %w", err)
+	}
+	defer drain(resp.Body)
+
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("sms gateway: unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// drain дочитывает и закрывает тело ответа, чтобы соединение вернулось в пул.
+func drain(body io.ReadCloser) {
+	_, _ = io.Copy(ioutil.Discard, body)
+	_ = body.Close()
+}
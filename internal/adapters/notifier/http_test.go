@@ -0,0 +1,52 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPSMS_SendSMS(t *testing.T) {
+	var got smsRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("cannot decode request: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	n := NewHTTPSMS(srv.URL, "sso", "TECHNODOM.")
+	if err := n.SendSMS(context.Background(), SMS{Phone: "87071234567", Text: "Код: 1111"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.Prefix != "sso" || got.Sender != "TECHNODOM." || got.Message != "Код: 1111" {
+		t.Errorf("unexpected request: %+v", got)
+	}
+	if got.Phone == "" {
+		t.Errorf("phone not sent")
+	}
+}
+
+func TestHTTPSMS_Errors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	n := NewHTTPSMS(srv.URL, "sso", "TECHNODOM.")
+	if err := n.SendSMS(context.Background(), SMS{Phone: "87071234567", Text: "1111"}); err == nil {
+		t.Error("expected error on gateway failure")
+	}
+
+	if err := n.Ping(context.Background()); err == nil {
+		t.Error("expected ping error on gateway failure")
+	}
+
+	if err := n.SendEmail(context.Background(), Email{To: "user@example.com"}); err != ErrChannelNotSupported {
+		t.Errorf("expected ErrChannelNotSupported, got %v", err)
+	}
+}
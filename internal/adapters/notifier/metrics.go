@@ -0,0 +1,72 @@
+package notifier
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Результаты запроса к провайдеру в метриках.
+const (
+	outcomeSuccess  = "success"
+	outcomeFailure  = "failure"
+	outcomeRejected = "rejected" // запрос не выполнялся, выключатель провайдера разомкнут
+)
+
+// Metrics содержит метрики Prometheus по каждому провайдеру уведомлений.
+// Нулевой указатель допустим: метрики в этом случае не собираются.
+type Metrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	breaker  *prometheus.GaugeVec
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "sso_notifier_requests_total",
+			Help: "Total number of notification provider requests.",
+		}, []string{"provider", "channel", "outcome"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "sso_notifier_request_duration_seconds",
+			Help:    "Notification provider request latency.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"provider", "channel"}),
+		breaker: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "sso_notifier_circuit_state",
+			Help: "Notification provider circuit breaker state (0 - closed, 1 - half-open, 2 - open).",
+		}, []string{"provider"}),
+	}
+}
+
+// Collectors возвращает метрики для регистрации в Prometheus.
+func (m *Metrics) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{m.requests, m.duration, m.breaker}
+}
+
+func (m *Metrics) observe(provider, channel, outcome string, elapsed time.Duration) {
+	if m == nil {
+		return
+	}
+
+	m.requests.WithLabelValues(provider, channel, outcome).Inc()
+	if outcome != outcomeRejected {
+		m.duration.WithLabelValues(provider, channel).Observe(elapsed.Seconds())
+	}
+}
+
+func (m *Metrics) setState(provider, state string) {
+	if m == nil {
+		return
+	}
+
+	var v float64
+	switch state {
+	case BreakerHalfOpen:
+		v = 1
+	case BreakerOpen:
+		v = 2
+	}
+
+	m.breaker.WithLabelValues(provider).Set(v)
+}
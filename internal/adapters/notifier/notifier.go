@@ -0,0 +1,120 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrChannelNotSupported = errors.New("notification channel not supported by provider")
+	ErrUnknownProvider     = errors.New("unknown notifier provider")
+)
+
+// SMS содержит данные SMS сообщения.
+type SMS struct {
+	Phone          string
+	Text           string
+	IdempotencyKey string // провайдер не должен повторно доставлять сообщение с тем же ключом
+}
+
+// Email содержит данные письма. HTML часть необязательна.
+type Email struct {
+	To             string
+	Subject        string
+	Text           string
+	HTML           string
+	IdempotencyKey string // провайдер не должен повторно доставлять сообщение с тем же ключом
+}
+
+// Notifier доставляет уведомления пользователям.
+type Notifier interface {
+	Name() string
+	SendSMS(ctx context.Context, sms SMS) error
+	SendEmail(ctx context.Context, email Email) error
+	// Ping проверяет доступность провайдера.
+	Ping(ctx context.Context) error
+}
+
+// emailSupporter реализуют провайдеры, которые могут не доставлять письма.
+type emailSupporter interface {
+	SupportsEmail() bool
+}
+
+// SupportsEmail сообщает, может ли n доставлять письма.
+func SupportsEmail(n Notifier) bool {
+	if s, ok := n.(emailSupporter); ok {
+		return s.SupportsEmail()
+	}
+
+	return true
+}
+
+// Config содержит настройки провайдеров уведомлений.
+type Config struct {
+	Provider         string        // провайдеры через запятую в порядке приоритета: http/file/stdout/fake
+	URLs             []string      // адреса SMS шлюзов в порядке приоритета
+	HealthURLs       []string      // адреса проверки работоспособности SMS шлюзов в том же порядке
+	Prefix           string        // префикс системы, вызывающей SMS шлюз
+	Sender           string        // идентификатор отправителя SMS
+	File             string        // файл, в который пишутся уведомления
+	BreakerThreshold int           // ошибок подряд, после которых провайдер отключается
+	BreakerCooldown  time.Duration // время отключения провайдера
+	Metrics          *Metrics
+}
+
+// New создает провайдеров уведомлений по их названиям и объединяет их в
+// Failover. Провайдер http создается для каждого адреса SMS шлюза.
+func New(conf Config) (*Failover, error) {
+	var providers []Notifier
+
+	for _, name := range strings.Split(conf.Provider, ",") {
+		switch strings.TrimSpace(name) {
+		case "http":
+			providers = append(providers, newHTTPProviders(conf)...)
+		case "file":
+			n, err := NewFile(conf.File)
+			if err != nil {
+				return nil, err
+			}
+			providers = append(providers, n)
+		case "stdout", "":
+			providers = append(providers, NewStdout())
+		case "fake":
+			providers = append(providers, NewFake())
+		default:
+			return nil, ErrUnknownProvider
+		}
+	}
+
+	if len(providers) == 0 {
+		return nil, ErrUnknownProvider
+	}
+
+	return NewFailover(conf.BreakerThreshold, conf.BreakerCooldown, providers...).WithMetrics(conf.Metrics), nil
+}
+
+// newHTTPProviders создает провайдера для каждого SMS шлюза. Если шлюзов
+// несколько, они различаются по номеру: http-1, http-2 и т.д.
+func newHTTPProviders(conf Config) []Notifier {
+	urls := conf.URLs
+	if len(urls) == 0 {
+		urls = []string{""}
+	}
+
+	providers := make([]Notifier, 0, len(urls))
+	for i, url := range urls {
+		n := NewHTTPSMS(strings.TrimSpace(url), conf.Prefix, conf.Sender)
+		if i < len(conf.HealthURLs) {
+			n.WithHealthURL(strings.TrimSpace(conf.HealthURLs[i]))
+		}
+		if len(urls) > 1 {
+			n.WithName(fmt.Sprintf("http-%d", i+1))
+		}
+		providers = append(providers, n)
+	}
+
+	return providers
+}
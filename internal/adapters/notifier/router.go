@@ -0,0 +1,55 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+)
+
+// Router отправляет SMS и письма через разных провайдеров.
+type Router struct {
+	sms   Notifier
+	email Notifier
+}
+
+func NewRouter(sms, email Notifier) *Router {
+	return &Router{
+		sms:   sms,
+		email: email,
+	}
+}
+
+func (r *Router) Name() string {
+	return fmt.Sprintf("%s+%s", r.sms.Name(), r.email.Name())
+}
+
+func (r *Router) SendSMS(ctx context.Context, sms SMS) error {
+	return r.sms.SendSMS(ctx, sms)
+}
+
+func (r *Router) SendEmail(ctx context.Context, email Email) error {
+	return r.email.SendEmail(ctx, email)
+}
+
+func (r *Router) SupportsEmail() bool {
+	return SupportsEmail(r.email)
+}
+
+func (r *Router) Ping(ctx context.Context) error {
+	if err := r.sms.Ping(ctx); err != nil {
+		return err
+	}
+
+	return r.email.Ping(ctx)
+}
+
+// ProvidersStatus возвращает состояние провайдеров SMS и писем.
+func (r *Router) ProvidersStatus() []ProviderStatus {
+	var statuses []ProviderStatus
+	for _, n := range []Notifier{r.sms, r.email} {
+		if sr, ok := n.(StatusReporter); ok {
+			statuses = append(statuses, sr.ProvidersStatus()...)
+		}
+	}
+
+	return statuses
+}
@@ -0,0 +1,241 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const defaultSMTPTimeout = time.Second * 10
+
+// SMTPConfig содержит настройки подключения к SMTP серверу.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string // если не задан, аутентификация не выполняется
+	Password string
+	From     string
+	StartTLS bool // требовать STARTTLS перед аутентификацией и отправкой
+}
+
+// SMTP отправляет письма через SMTP сервер.
+type SMTP struct {
+	conf      SMTPConfig
+	timeout   time.Duration
+	tlsConfig *tls.Config
+}
+
+func NewSMTP(conf SMTPConfig) *SMTP {
+	return &SMTP{
+		conf:      conf,
+		timeout:   defaultSMTPTimeout,
+		tlsConfig: &tls.Config{ServerName: conf.Host},
+	}
+}
+
+// WithTLSConfig устанавливает настройки TLS для STARTTLS.
+func (n *SMTP) WithTLSConfig(conf *tls.Config) *SMTP {
+	n.tlsConfig = conf
+	return n
+}
+
+func (n *SMTP) Name() string {
+	return "smtp"
+}
+
+func (n *SMTP) SendSMS(_ context.Context, _ SMS) error {
+	return ErrChannelNotSupported
+}
+
+func (n *SMTP) SendEmail(ctx context.Context, email Email) error {
+	msg, err := n.message(email)
+	if err != nil {
+		return err
+	}
+
+	c, err := n.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if err := c.Mail(n.conf.From); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	if err := c.Rcpt(email.To); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+
+	return c.Quit()
+}
+
+// Ping проверяет, что SMTP сервер принимает подключение и аутентификацию.
+func (n *SMTP) Ping(ctx context.Context) error {
+	c, err := n.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if err := c.Noop(); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+
+	return c.Quit()
+}
+
+// dial подключается к серверу, при необходимости включает STARTTLS и
+// проходит аутентификацию.
+func (n *SMTP) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(n.conf.Host, strconv.Itoa(n.conf.Port))
+
+	dialer := &net.Dialer{Timeout: n.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("smtp: %w", err)
+	}
+
+	deadline := time.Now().Add(n.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetDeadline(deadline)
+
+	c, err := smtp.NewClient(conn, n.conf.Host)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("smtp: %w", err)
+	}
+
+	if n.conf.StartTLS {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			_ = c.Close()
+			return nil, fmt.Errorf("smtp: server %s does not support STARTTLS", addr)
+		}
+
+		if err := c.StartTLS(n.tlsConfig); err != nil {
+			_ = c.Close()
+			return nil, fmt.Errorf("smtp: %w", err)
+		}
+	}
+
+	if n.conf.Username != "" {
+		auth := smtp.PlainAuth("", n.conf.Username, n.conf.Password, n.conf.Host)
+		if err := c.Auth(auth); err != nil {
+			_ = c.Close()
+			return nil, fmt.Errorf("smtp: %w", err)
+		}
+	}
+
+	return c, nil
+}
+
+// message собирает письмо в формате MIME. При наличии HTML письмо состоит
+// из текстовой и HTML частей (multipart/alternative).
+func (n *SMTP) message(email Email) ([]byte, error) {
+	var buf bytes.Buffer
+
+	header := textproto.MIMEHeader{}
+	header.Set("From", n.conf.From)
+	header.Set("To", email.To)
+	header.Set("Subject", mime.QEncoding.Encode("utf-8", email.Subject))
+	header.Set("Date", time.Now().Format(time.RFC1123Z))
+	header.Set("MIME-Version", "1.0")
+	if email.IdempotencyKey != "" {
+		// одинаковый Message-ID позволяет почтовым системам отбросить дубликат
+		sum := sha256.Sum256([]byte(email.IdempotencyKey))
+		header.Set("Message-ID", fmt.Sprintf("<%s@%s>", hex.EncodeToString(sum[:16]), n.conf.Host))
+	}
+
+	if email.HTML == "" {
+		header.Set("Content-Type", "text/plain; charset=utf-8")
+		header.Set("Content-Transfer-Encoding", "quoted-printable")
+		writeHeader(&buf, header)
+
+		if err := writeQuotedPrintable(&buf, email.Text); err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+
+	for _, part := range []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=utf-8", email.Text},
+		{"text/html; charset=utf-8", email.HTML},
+	} {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if err := writeQuotedPrintable(pw, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	header.Set("Content-Type", "multipart/alternative; boundary="+mw.Boundary())
+	writeHeader(&buf, header)
+	buf.Write(body.Bytes())
+
+	return buf.Bytes(), nil
+}
+
+// writeHeader пишет заголовки письма в детерминированном порядке.
+func writeHeader(w io.Writer, header textproto.MIMEHeader) {
+	keys := make([]string, 0, len(header))
+	for k := range header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s: %s\r\n", k, header.Get(k))
+	}
+	fmt.Fprint(w, "\r\n")
+}
+
+func writeQuotedPrintable(w io.Writer, text string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(text)); err != nil {
+		return err
+	}
+
+	return qp.Close()
+}
@@ -0,0 +1,158 @@
+package notifier
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+// smtpStandIn минимальный SMTP сервер, принимающий одно письмо за подключение.
+type smtpStandIn struct {
+	ln       net.Listener
+	auth     chan string
+	messages chan string
+}
+
+func newSMTPStandIn(t *testing.T) *smtpStandIn {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("cannot listen: %v", err)
+	}
+
+	s := &smtpStandIn{ln: ln, auth: make(chan string, 10), messages: make(chan string, 10)}
+	go s.serve()
+
+	return s
+}
+
+func (s *smtpStandIn) port() int {
+	return s.ln.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpStandIn) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *smtpStandIn) handle(conn net.Conn) {
+	defer conn.Close()
+
+	tp := textproto.NewConn(conn)
+	_ = tp.PrintfLine("220 localhost ESMTP stand-in")
+
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch cmd {
+		case "EHLO", "HELO":
+			_ = tp.PrintfLine("250-localhost")
+			_ = tp.PrintfLine("250 AUTH PLAIN")
+		case "AUTH":
+			s.auth <- line
+			_ = tp.PrintfLine("235 2.7.0 Authentication successful")
+		case "MAIL", "RCPT", "NOOP", "RSET":
+			_ = tp.PrintfLine("250 OK")
+		case "DATA":
+			_ = tp.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			s.messages <- string(data)
+			_ = tp.PrintfLine("250 OK")
+		case "QUIT":
+			_ = tp.PrintfLine("221 Bye")
+			return
+		default:
+			_ = tp.PrintfLine("502 Command not implemented")
+		}
+	}
+}
+
+func TestSMTP_SendEmail(t *testing.T) {
+	srv := newSMTPStandIn(t)
+	defer srv.ln.Close()
+
+	n := NewSMTP(SMTPConfig{
+		Host:     "localhost",
+		Port:     srv.port(),
+		Username: "sso",
+		Password: "secret",
+		From:     "noreply@example.com",
+	})
+
+	err := n.SendEmail(context.Background(), Email{
+		To:      "user@example.com",
+		Subject: "Восстановление доступа",
+		Text:    "Код: 1234",
+		HTML:    "<b>1234</b>",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if auth := <-srv.auth; !strings.HasPrefix(auth, "AUTH PLAIN") {
+		t.Errorf("unexpected auth command: %s", auth)
+	}
+
+	msg := <-srv.messages
+	for _, want := range []string{
+		"multipart/alternative",
+		"text/plain; charset=utf-8",
+		"text/html; charset=utf-8",
+		"=?utf-8?q?",
+		"To: user@example.com",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message does not contain %q:\n%s", want, msg)
+		}
+	}
+
+	if err := n.Ping(context.Background()); err != nil {
+		t.Errorf("unexpected ping error: %v", err)
+	}
+}
+
+func TestSMTP_RequireStartTLS(t *testing.T) {
+	srv := newSMTPStandIn(t)
+	defer srv.ln.Close()
+
+	n := NewSMTP(SMTPConfig{Host: "localhost", Port: srv.port(), From: "noreply@example.com", StartTLS: true})
+	if err := n.SendEmail(context.Background(), Email{To: "user@example.com", Text: "1234"}); err == nil {
+		t.Error("expected error when server does not offer STARTTLS")
+	}
+}
+
+func TestSMTP_PlainMessage(t *testing.T) {
+	n := NewSMTP(SMTPConfig{Host: "localhost", Port: 25, From: "noreply@example.com"})
+
+	msg, err := n.message(Email{To: "user@example.com", Subject: "Hi", Text: "Код: 1234"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	r := textproto.NewReader(bufio.NewReader(strings.NewReader(string(msg))))
+	header, err := r.ReadMIMEHeader()
+	if err != nil {
+		t.Fatalf("cannot parse header: %v", err)
+	}
+
+	if ct := header.Get("Content-Type"); ct != "text/plain; charset=utf-8" {
+		t.Errorf("unexpected content type %q", ct)
+	}
+	if subject := header.Get("Subject"); subject != "Hi" {
+		t.Errorf("unexpected subject %q", subject)
+	}
+}
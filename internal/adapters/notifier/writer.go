@@ -0,0 +1,72 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/JetBrainer/sso/utils"
+)
+
+// Writer вместо реальной отправки пишет уведомления в w.
+// Используется при разработке.
+type Writer struct {
+	mu   sync.Mutex
+	w    io.Writer
+	name string
+}
+
+// NewStdout создает Writer, пишущий уведомления в стандартный вывод.
+func NewStdout() *Writer {
+	return &Writer{w: os.Stdout, name: "stdout"}
+}
+
+// NewFile создает Writer, дописывающий уведомления в файл path.
+func NewFile(path string) (*Writer, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Writer{w: f, name: "file"}, nil
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w, name: "writer"}
+}
+
+func (n *Writer) Name() string {
+	return n.name
+}
+
+func (n *Writer) SendSMS(_ context.Context, sms SMS) error {
+	return n.write("sms to %s: %s", utils.MaskPhoneNum(sms.Phone), sms.Text)
+}
+
+func (n *Writer) SendEmail(_ context.Context, email Email) error {
+	return n.write("email to %s: [%s] %s", email.To, email.Subject, email.Text)
+}
+
+func (n *Writer) Ping(_ context.Context) error {
+	return nil
+}
+
+// Close закрывает w, если он это поддерживает. Стандартный вывод не закрывается.
+func (n *Writer) Close() error {
+	if c, ok := n.w.(io.Closer); ok && n.w != os.Stdout {
+		return c.Close()
+	}
+
+	return nil
+}
+
+func (n *Writer) write(format string, args ...interface{}) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	_, err := fmt.Fprintf(n.w, "%s "+format+"\n", append([]interface{}{time.Now().Format(time.RFC3339)}, args...)...)
+	return err
+}
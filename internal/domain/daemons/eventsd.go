@@ -1 +1,129 @@
 package daemons
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	"github.com/JetBrainer/sso/internal/adapters/notifier"
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/JetBrainer/sso/internal/domain/templates"
+	"github.com/JetBrainer/sso/pkg/logger"
+)
+
+const (
+	DefaultPollInterval = time.Second * 5 // период опроса outbox
+	DefaultStuckTimeout = time.Minute * 2 // время, после которого взятое в отправку сообщение возвращается в очередь
+	DefaultMaxAttempts  = 8               // количество попыток отправки, после которого сообщение уходит в dead-letter
+)
+
+// Eventsd доставляет уведомления из outbox и события подписчикам webhook,
+// а также очищает просроченные восстановления доступа.
+//
+// Сообщения outbox записываются вместе с изменением состояния, которое их
+// порождает. Взятое в отправку сообщение арендуется на stuckTimeout, так что
+// при падении процесса оно будет отправлено повторно. Ключ идемпотентности
+// передается провайдеру, чтобы повторная отправка не дублировала сообщение.
+// События webhook доставляются так же, с идентификатором события в заголовке.
+type Eventsd struct {
+	db           drivers.DataStore
+	notifier     notifier.Notifier
+	client       *http.Client
+	templates    *templates.Engine
+	pollInterval time.Duration
+	stuckTimeout time.Duration
+	maxAttempts  int
+	lastRun      int64 // время окончания последнего прохода в наносекундах Unix
+}
+
+func NewEventsd(db drivers.DataStore, n notifier.Notifier, tpl *templates.Engine) *Eventsd {
+	return &Eventsd{
+		db:           db,
+		notifier:     n,
+		client:       &http.Client{Timeout: DefaultWebhookTimeout},
+		templates:    tpl,
+		pollInterval: DefaultPollInterval,
+		stuckTimeout: DefaultStuckTimeout,
+		maxAttempts:  DefaultMaxAttempts,
+	}
+}
+
+// WithPollInterval устанавливает период опроса outbox.
+func (d *Eventsd) WithPollInterval(t time.Duration) *Eventsd {
+	d.pollInterval = t
+	return d
+}
+
+// WithStuckTimeout устанавливает время, после которого неотправленное
+// сообщение возвращается в очередь.
+func (d *Eventsd) WithStuckTimeout(t time.Duration) *Eventsd {
+	d.stuckTimeout = t
+	return d
+}
+
+// WithMaxAttempts устанавливает количество попыток отправки сообщения.
+func (d *Eventsd) WithMaxAttempts(n int) *Eventsd {
+	d.maxAttempts = n
+	return d
+}
+
+// WithWebhookTimeout устанавливает время ожидания ответа подписчика webhook.
+func (d *Eventsd) WithWebhookTimeout(t time.Duration) *Eventsd {
+	d.client = &http.Client{Timeout: t}
+	return d
+}
+
+// Run разбирает очереди до отмены ctx.
+func (d *Eventsd) Run(ctx context.Context) error {
+	logger.Printf(ctx, "[INFO] eventsd started with notifier %q, poll interval %s", d.notifier.Name(), d.pollInterval)
+
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			logger.Printf(ctx, "[INFO] eventsd stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll выполняет один проход по очередям.
+func (d *Eventsd) poll(ctx context.Context) {
+	d.cleanExpiredRestores(ctx)
+	d.dispatchOutbox(ctx)
+	d.dispatchWebhooks(ctx)
+
+	atomic.StoreInt64(&d.lastRun, time.Now().UnixNano())
+}
+
+// LastRun возвращает время окончания последнего прохода по очередям,
+// нулевое до первого прохода.
+func (d *Eventsd) LastRun() time.Time {
+	ns := atomic.LoadInt64(&d.lastRun)
+	if ns == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, ns)
+}
+
+// PollInterval возвращает период опроса очередей.
+func (d *Eventsd) PollInterval() time.Duration {
+	return d.pollInterval
+}
+
+func (d *Eventsd) cleanExpiredRestores(ctx context.Context) {
+	c := make(chan models.User)
+	d.db.RestoreFindExpiredAndUpdate(ctx, c)
+
+	for u := range c {
+		logger.Printf(ctx, "[DEBUG] eventsd: restore token of user %s expired", u.ID.Hex())
+	}
+}
@@ -0,0 +1,138 @@
+package daemons
+
+import (
+	"context"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/adapters/notifier"
+	errors2 "github.com/JetBrainer/sso/internal/domain/errors"
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/JetBrainer/sso/internal/domain/templates"
+	"github.com/JetBrainer/sso/pkg/logger"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	baseBackoff = time.Second * 10
+	maxBackoff  = time.Minute * 30
+
+	untilLayout = "02.01.2006 15:04 UTC" // время в уведомлениях
+)
+
+// dispatchOutbox отправляет все сообщения outbox, время отправки которых наступило.
+func (d *Eventsd) dispatchOutbox(ctx context.Context) {
+	for ctx.Err() == nil {
+		msg, err := d.db.Outbox().Claim(ctx, time.Now().In(time.UTC), d.stuckTimeout)
+		if err != nil {
+			if errors.Cause(err) != errors2.ErrDoesNotExist {
+				logger.Printf(ctx, "[ERROR] eventsd: cannot claim outbox message: %v", err)
+			}
+			return
+		}
+
+		if err := d.send(ctx, msg); err != nil {
+			d.fail(ctx, msg, err)
+			continue
+		}
+
+		if err := d.db.Outbox().MarkSent(ctx, msg.ID); err != nil {
+			logger.Printf(ctx, "[ERROR] eventsd: cannot mark outbox message %s as sent: %v", msg.ID, err)
+		}
+
+		d.markStateSent(ctx, msg)
+	}
+}
+
+// send отрисовывает шаблон сообщения на языке получателя и отправляет его.
+func (d *Eventsd) send(ctx context.Context, msg *models.OutboxMessage) error {
+	data := templates.Data{
+		Name:   msg.Name,
+		Code:   msg.Code,
+		Action: msg.Action,
+	}
+	if msg.Until != nil {
+		data.Until = msg.Until.In(time.UTC).Format(untilLayout)
+	}
+
+	rendered, err := d.templates.Render(msg.Language, msg.Template, data)
+	if err != nil {
+		return err
+	}
+
+	if msg.Channel == models.OutboxChannelEmail {
+		return d.notifier.SendEmail(ctx, notifier.Email{
+			To:             msg.Recipient,
+			Subject:        rendered.Subject,
+			Text:           rendered.Text,
+			HTML:           rendered.HTML,
+			IdempotencyKey: msg.IdempotencyKey,
+		})
+	}
+
+	return d.notifier.SendSMS(ctx, notifier.SMS{
+		Phone:          msg.Recipient,
+		Text:           rendered.Text,
+		IdempotencyKey: msg.IdempotencyKey,
+	})
+}
+
+// fail планирует повторную отправку сообщения с экспоненциальной задержкой,
+// а по исчерпании попыток переводит его в dead-letter.
+func (d *Eventsd) fail(ctx context.Context, msg *models.OutboxMessage, sendErr error) {
+	dead := msg.Attempts >= d.maxAttempts
+	nextAttemptAt := time.Now().In(time.UTC).Add(backoff(msg.Attempts))
+
+	if dead {
+		logger.Printf(ctx, "[ERROR] eventsd: outbox message %s moved to dead-letter after %d attempts: %v", msg.ID, msg.Attempts, sendErr)
+	} else {
+		logger.Printf(ctx, "[WARN] eventsd: cannot send outbox message %s (attempt %d): %v", msg.ID, msg.Attempts, sendErr)
+	}
+
+	if err := d.db.Outbox().MarkFailed(ctx, msg.ID, sendErr.Error(), nextAttemptAt, dead); err != nil {
+		logger.Printf(ctx, "[ERROR] eventsd: cannot mark outbox message %s as failed: %v", msg.ID, err)
+	}
+}
+
+// markStateSent проставляет признак отправки в состоянии, породившем сообщение.
+func (d *Eventsd) markStateSent(ctx context.Context, msg *models.OutboxMessage) {
+	var err error
+
+	switch msg.Template {
+	case templates.Restore, templates.Verify:
+		tdid, idErr := msg.TDID.ToObjectID()
+		if idErr != nil {
+			err = idErr
+			break
+		}
+
+		if msg.Template == templates.Restore {
+			err = d.db.RestoreSendNotificationSuccessfully(ctx, tdid)
+		} else {
+			err = d.db.VerifySendNotificationSuccessfully(ctx, tdid)
+		}
+	case templates.Confirm:
+		err = d.db.Events().ApproveVerificationSendStatus(ctx, msg.TDID, msg.ActionType)
+	}
+
+	if err != nil {
+		logger.Printf(ctx, "[WARN] eventsd: cannot mark %s state of user %s as sent: %v", msg.Template, msg.TDID, err)
+	}
+}
+
+// backoff возвращает задержку перед следующей попыткой после attempts неудачных.
+func backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		return baseBackoff
+	}
+
+	d := baseBackoff
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= maxBackoff {
+			return maxBackoff
+		}
+	}
+
+	return d
+}
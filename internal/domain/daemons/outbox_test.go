@@ -0,0 +1,27 @@
+package daemons
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, baseBackoff},
+		{1, baseBackoff},
+		{2, 2 * baseBackoff},
+		{3, 4 * baseBackoff},
+		{8, 128 * baseBackoff},
+		{9, maxBackoff},
+		{100, maxBackoff},
+	}
+
+	for _, tt := range tests {
+		if got := backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
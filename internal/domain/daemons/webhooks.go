@@ -0,0 +1,120 @@
+package daemons
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	errors2 "github.com/JetBrainer/sso/internal/domain/errors"
+	"github.com/JetBrainer/sso/internal/domain/manager/webhooks"
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/JetBrainer/sso/pkg/logger"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const (
+	DefaultWebhookTimeout = time.Second * 10 // время ожидания ответа подписчика
+	webhookErrorBodyLen   = 512              // сколько байт тела ответа с ошибкой сохраняется в журнале
+)
+
+// dispatchWebhooks отправляет подписчикам все события, время отправки которых наступило.
+func (d *Eventsd) dispatchWebhooks(ctx context.Context) {
+	for ctx.Err() == nil {
+		delivery, err := d.db.WebhookDeliveries().Claim(ctx, time.Now().In(time.UTC), d.stuckTimeout)
+		if err != nil {
+			if errors.Cause(err) != errors2.ErrDoesNotExist {
+				logger.Printf(ctx, "[ERROR] eventsd: cannot claim webhook delivery: %v", err)
+			}
+			return
+		}
+
+		status, err := d.deliver(ctx, delivery)
+		if err != nil {
+			d.failWebhook(ctx, delivery, status, err)
+			continue
+		}
+
+		if err := d.db.WebhookDeliveries().MarkDelivered(ctx, delivery.ID, status); err != nil {
+			logger.Printf(ctx, "[ERROR] eventsd: cannot mark webhook delivery %s as delivered: %v", delivery.ID, err)
+		}
+	}
+}
+
+// deliver отправляет событие включенному webhook.
+func (d *Eventsd) deliver(ctx context.Context, delivery *models.WebhookDelivery) (int, error) {
+	webhook, err := d.db.Webhooks().ByID(ctx, delivery.WebhookID)
+	if err != nil {
+		if errors.Cause(err) == errors2.ErrDoesNotExist {
+			return 0, webhooks.ErrWebhookNotActive
+		}
+		return 0, err
+	}
+
+	if !webhook.Enabled {
+		return 0, webhooks.ErrWebhookNotActive
+	}
+
+	return d.post(ctx, webhook, delivery)
+}
+
+// post выполняет подписанный запрос к подписчику и возвращает код ответа.
+// Доставленным считается событие, на которое получен ответ 2xx.
+func (d *Eventsd) post(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhooks.HeaderEvent, delivery.Event)
+	req.Header.Set(webhooks.HeaderEventID, delivery.EventID)
+	req.Header.Set(webhooks.HeaderDelivery, string(delivery.ID))
+	req.Header.Set(webhooks.HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(webhooks.HeaderSignature, webhooks.Sign(webhook.Secret, timestamp, body))
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		return resp.StatusCode, nil
+	}
+
+	respBody, _ := ioutil.ReadAll(io.LimitReader(resp.Body, webhookErrorBodyLen))
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+	return resp.StatusCode, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+}
+
+// failWebhook планирует повторную отправку события с экспоненциальной
+// задержкой. По исчерпании попыток, а также если webhook удален или
+// выключен, попытки прекращаются.
+func (d *Eventsd) failWebhook(ctx context.Context, delivery *models.WebhookDelivery, status int, sendErr error) {
+	dead := delivery.Attempts >= d.maxAttempts || sendErr == webhooks.ErrWebhookNotActive
+	nextAttemptAt := time.Now().In(time.UTC).Add(backoff(delivery.Attempts))
+
+	if dead {
+		logger.Printf(ctx, "[ERROR] eventsd: webhook delivery %s stopped after %d attempts: %v", delivery.ID, delivery.Attempts, sendErr)
+	} else {
+		logger.Printf(ctx, "[WARN] eventsd: cannot deliver webhook %s (attempt %d): %v", delivery.ID, delivery.Attempts, sendErr)
+	}
+
+	if err := d.db.WebhookDeliveries().MarkFailed(ctx, delivery.ID, status, sendErr.Error(), nextAttemptAt, dead); err != nil {
+		logger.Printf(ctx, "[ERROR] eventsd: cannot mark webhook delivery %s as failed: %v", delivery.ID, err)
+	}
+}
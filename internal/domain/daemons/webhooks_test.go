@@ -0,0 +1,56 @@
+package daemons
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/JetBrainer/sso/internal/domain/manager/webhooks"
+	"github.com/JetBrainer/sso/internal/domain/models"
+)
+
+func TestEventsd_Post(t *testing.T) {
+	payload := `{"id":"1","type":"user.created","data":{}}`
+
+	var code int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if string(body) != payload {
+			t.Errorf("unexpected body: %s", body)
+		}
+
+		ts, err := strconv.ParseInt(r.Header.Get(webhooks.HeaderTimestamp), 10, 64)
+		if err != nil {
+			t.Errorf("invalid timestamp header: %v", err)
+		}
+		if !webhooks.Verify("secret", ts, body, r.Header.Get(webhooks.HeaderSignature)) {
+			t.Errorf("invalid signature")
+		}
+		if r.Header.Get(webhooks.HeaderEvent) != models.WebhookEventUserCreated || r.Header.Get(webhooks.HeaderEventID) != "1" {
+			t.Errorf("unexpected event headers: %v", r.Header)
+		}
+
+		w.WriteHeader(code)
+		_, _ = w.Write([]byte("busy"))
+	}))
+	defer srv.Close()
+
+	d := NewEventsd(nil, nil, nil)
+	webhook := &models.Webhook{URL: srv.URL, Secret: "secret", Enabled: true}
+	delivery := models.NewWebhookDelivery("hook", "1", models.WebhookEventUserCreated, payload)
+
+	code = http.StatusNoContent
+	status, err := d.post(context.Background(), webhook, delivery)
+	if err != nil || status != http.StatusNoContent {
+		t.Fatalf("post() = %d, %v", status, err)
+	}
+
+	code = http.StatusServiceUnavailable
+	status, err = d.post(context.Background(), webhook, delivery)
+	if err == nil || status != http.StatusServiceUnavailable {
+		t.Fatalf("post() = %d, %v, want error", status, err)
+	}
+}
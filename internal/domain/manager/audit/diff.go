@@ -0,0 +1,73 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/JetBrainer/sso/internal/domain/models"
+)
+
+// skipFields - поля, которые не попадают в журнал: служебные отметки и
+// секреты (хэш пароля, токены восстановления и верификации).
+var skipFields = map[string]bool{
+	"id":            true,
+	"created":       true,
+	"updated":       true,
+	"password":      true,
+	"verify":        true,
+	"restore":       true,
+	"last_verified": true,
+	"lockout":       true,
+}
+
+// Diff сравнивает поля структур before и after одного типа и возвращает
+// изменившиеся под их именами в JSON. Поля без JSON имени пропускаются.
+func Diff(before, after interface{}) []models.AuditChange {
+	bv, av := reflect.Indirect(reflect.ValueOf(before)), reflect.Indirect(reflect.ValueOf(after))
+	if bv.Kind() != reflect.Struct || bv.Type() != av.Type() {
+		return nil
+	}
+
+	var changes []models.AuditChange
+	for i := 0; i < bv.NumField(); i++ {
+		field := bv.Type().Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if field.PkgPath != "" || name == "" || name == "-" || skipFields[name] {
+			continue
+		}
+
+		b, a := bv.Field(i), av.Field(i)
+		if equal(b, a) {
+			continue
+		}
+
+		changes = append(changes, models.AuditChange{Field: name, Before: value(b), After: value(a)})
+	}
+
+	return changes
+}
+
+// equal считает пустой и отсутствующий списки одинаковыми.
+func equal(a, b reflect.Value) bool {
+	if a.Kind() == reflect.Slice || a.Kind() == reflect.Map {
+		if a.Len() == 0 && b.Len() == 0 {
+			return true
+		}
+	}
+
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+func value(v reflect.Value) models.AuditValue {
+	if v.IsZero() {
+		return nil
+	}
+
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return nil
+	}
+
+	return data
+}
@@ -0,0 +1,47 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	before := models.User{
+		FirstName: "Ivan",
+		Email:     "ivan@example.com",
+		Password:  "old-hash",
+		Roles:     []string{"user"},
+		Updated:   time.Now(),
+	}
+
+	after := before
+	after.FirstName = "Ivan"
+	after.LastName = "Petrov"
+	after.Email = "petrov@example.com"
+	after.Password = "new-hash"
+	after.Roles = []string{"user", "admin"}
+	after.Phones = []string{}
+	after.Updated = before.Updated.Add(time.Minute)
+
+	changes := make(map[string][2]string)
+	for _, c := range Diff(before, after) {
+		changes[c.Field] = [2]string{string(c.Before), string(c.After)}
+	}
+
+	assert.Equal(t, map[string][2]string{
+		"lastname": {"", `"Petrov"`},
+		"email":    {`"ivan@example.com"`, `"petrov@example.com"`},
+		"roles":    {`["user"]`, `["user","admin"]`},
+	}, changes)
+}
+
+func TestDiffSkipsHiddenFields(t *testing.T) {
+	before := models.Webhook{URL: "https://a.example.com", Secret: "one"}
+	after := models.Webhook{URL: "https://a.example.com", Secret: "two"}
+
+	assert.Empty(t, Diff(before, after))
+	assert.Nil(t, Diff(before, &models.User{}))
+}
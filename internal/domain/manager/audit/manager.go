@@ -0,0 +1,91 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/JetBrainer/sso/pkg/logger"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 500
+
+	// appendTimeout ограничивает запись, чтобы журнал сохранялся и после
+	// отмены запроса клиентом.
+	appendTimeout = 5 * time.Second
+)
+
+type ctxKeySource struct{}
+
+// WithSource сохраняет в контексте сведения о том, откуда выполняется запрос.
+func WithSource(ctx context.Context, source models.AuditSource) context.Context {
+	return context.WithValue(ctx, ctxKeySource{}, source)
+}
+
+// SourceFromContext возвращает сведения об источнике запроса.
+func SourceFromContext(ctx context.Context) models.AuditSource {
+	source, _ := ctx.Value(ctxKeySource{}).(models.AuditSource)
+	return source
+}
+
+// Manager ведет журнал аудита. Нулевой указатель допустим: записи в этом
+// случае не сохраняются.
+type Manager struct {
+	db drivers.DataStore
+}
+
+func New(db drivers.DataStore) *Manager {
+	return &Manager{db: db}
+}
+
+// Record дописывает запись в журнал, дополняя ее источником запроса из ctx.
+// Ошибка записи журналируется и не прерывает выполненную операцию.
+func (m *Manager) Record(ctx context.Context, entry *models.AuditEntry) {
+	if m == nil {
+		return
+	}
+
+	source := SourceFromContext(ctx)
+	entry.IP = source.IP
+	entry.UserAgent = source.UserAgent
+	entry.DeviceID = source.DeviceID
+	entry.RequestID = source.RequestID
+	entry.Created = time.Now().In(time.UTC)
+	if entry.Outcome == "" {
+		entry.Outcome = models.AuditOutcomeSuccess
+	}
+
+	appendCtx, cancel := context.WithTimeout(context.Background(), appendTimeout)
+	defer cancel()
+
+	if err := m.db.Audit().Append(appendCtx, entry); err != nil {
+		logger.Printf(ctx, "[ERROR] cannot append %s audit entry: %v", entry.Type, err)
+	}
+}
+
+// List возвращает записи журнала, начиная с самых новых.
+func (m *Manager) List(ctx context.Context, filters *models.AuditFilters) ([]models.AuditEntry, error) {
+	if m == nil {
+		return []models.AuditEntry{}, nil
+	}
+
+	if filters.Limit <= 0 {
+		filters.Limit = DefaultLimit
+	}
+	if filters.Limit > MaxLimit {
+		filters.Limit = MaxLimit
+	}
+
+	return m.db.Audit().List(ctx, filters)
+}
+
+// Activity возвращает события, касающиеся пользователя tdid.
+func (m *Manager) Activity(ctx context.Context, tdid string, filters *models.AuditFilters) ([]models.AuditEntry, error) {
+	filters.TDID = &tdid
+	filters.Actor = nil
+
+	return m.List(ctx, filters)
+}
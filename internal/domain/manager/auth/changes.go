@@ -0,0 +1,160 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	"github.com/JetBrainer/sso/internal/domain/models"
+)
+
+const (
+	DefaultChangesHistory = 1024 // количество последних изменений, с которых можно продолжить поток
+	changesBuffer         = 64   // очередь изменений одного подписчика
+)
+
+// ErrWatcherLagging возвращается подписчику, который не успевает читать
+// изменения. Он может переподключиться с последней полученной позиции.
+var ErrWatcherLagging = errors.New("watcher is too slow to receive user changes")
+
+type sequencedChange struct {
+	seq    uint64
+	change models.UserChange
+}
+
+// Broadcaster транслирует изменения пользователей подписчикам внутри
+// процесса. Используется, если хранилище не умеет отдавать поток изменений
+// самостоятельно. Позиция в потоке - порядковый номер изменения с отметкой
+// запуска процесса, продолжить можно с любого из последних history изменений.
+type Broadcaster struct {
+	mu          sync.Mutex
+	epoch       string // позиции, выданные до перезапуска, недействительны
+	seq         uint64
+	history     []sequencedChange
+	historySize int
+	subscribers map[chan models.UserChange]struct{}
+}
+
+func NewBroadcaster(historySize int) *Broadcaster {
+	if historySize < 1 {
+		historySize = DefaultChangesHistory
+	}
+
+	return &Broadcaster{
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		historySize: historySize,
+		subscribers: make(map[chan models.UserChange]struct{}),
+	}
+}
+
+// Publish рассылает изменение всем подписчикам. Подписчик с переполненной
+// очередью отключается.
+func (b *Broadcaster) Publish(change models.UserChange) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	change.ResumeToken = b.epoch + "-" + strconv.FormatUint(b.seq, 10)
+	if change.Time.IsZero() {
+		change.Time = time.Now().In(time.UTC)
+	}
+
+	b.history = append(b.history, sequencedChange{seq: b.seq, change: change})
+	if len(b.history) > b.historySize {
+		b.history = b.history[len(b.history)-b.historySize:]
+	}
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- change:
+		default:
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// WatchUsers вызывает fn для каждого изменения после позиции resumeToken,
+// пока не отменен ctx или fn не вернет ошибку.
+func (b *Broadcaster) WatchUsers(ctx context.Context, resumeToken string, fn func(change *models.UserChange) error) error {
+	ch, backlog, err := b.subscribe(resumeToken)
+	if err != nil {
+		return err
+	}
+	defer b.unsubscribe(ch)
+
+	for i := range backlog {
+		if err := fn(&backlog[i]); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case change, ok := <-ch:
+			if !ok {
+				return ErrWatcherLagging
+			}
+			if err := fn(&change); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// subscribe регистрирует подписчика и возвращает изменения после позиции
+// resumeToken, которые уже есть в истории.
+func (b *Broadcaster) subscribe(resumeToken string) (chan models.UserChange, []models.UserChange, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var backlog []models.UserChange
+	if resumeToken != "" {
+		after, err := b.position(resumeToken)
+		if err != nil || after > b.seq {
+			return nil, nil, drivers.ErrInvalidResumeToken
+		}
+
+		// изменения после позиции уже вытеснены из истории
+		if after < b.seq && (len(b.history) == 0 || b.history[0].seq > after+1) {
+			return nil, nil, drivers.ErrInvalidResumeToken
+		}
+
+		for _, c := range b.history {
+			if c.seq > after {
+				backlog = append(backlog, c.change)
+			}
+		}
+	}
+
+	ch := make(chan models.UserChange, changesBuffer)
+	b.subscribers[ch] = struct{}{}
+
+	return ch, backlog, nil
+}
+
+// position возвращает порядковый номер изменения из позиции resumeToken.
+func (b *Broadcaster) position(resumeToken string) (uint64, error) {
+	i := strings.LastIndexByte(resumeToken, '-')
+	if i < 0 || resumeToken[:i] != b.epoch {
+		return 0, drivers.ErrInvalidResumeToken
+	}
+
+	return strconv.ParseUint(resumeToken[i+1:], 10, 64)
+}
+
+func (b *Broadcaster) unsubscribe(ch chan models.UserChange) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscribers[ch]; ok {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
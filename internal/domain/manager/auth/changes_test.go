@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var errStop = errors.New("stop")
+
+// Тестирует продолжение потока изменений с позиции, полученной ранее.
+func TestBroadcaster_Resume(t *testing.T) {
+	b := NewBroadcaster(10)
+	b.Publish(models.UserChange{Type: models.UserChangeCreated})
+	b.Publish(models.UserChange{Type: models.UserChangeUpdated})
+	b.Publish(models.UserChange{Type: models.UserChangeDeleted})
+
+	var first string
+	_ = b.WatchUsers(context.Background(), b.history[0].change.ResumeToken, func(c *models.UserChange) error {
+		first = c.Type
+		return errStop
+	})
+	assert.Equal(t, models.UserChangeUpdated, first)
+
+	// изменения после позиции вытеснены из истории
+	b = NewBroadcaster(1)
+	b.Publish(models.UserChange{})
+	token := b.history[0].change.ResumeToken
+	b.Publish(models.UserChange{})
+	b.Publish(models.UserChange{})
+	err := b.WatchUsers(context.Background(), token, func(*models.UserChange) error { return nil })
+	assert.Equal(t, drivers.ErrInvalidResumeToken, err)
+
+	// позиция другого процесса
+	err = b.WatchUsers(context.Background(), "other-1", func(*models.UserChange) error { return nil })
+	assert.Equal(t, drivers.ErrInvalidResumeToken, err)
+}
+
+// Тестирует доставку новых изменений и отключение медленного подписчика.
+func TestBroadcaster_Publish(t *testing.T) {
+	b := NewBroadcaster(10)
+	received := make(chan string)
+	done := make(chan error)
+
+	go func() {
+		done <- b.WatchUsers(context.Background(), "", func(c *models.UserChange) error {
+			received <- c.Type
+			return nil
+		})
+	}()
+
+	// ждем регистрации подписчика
+	for {
+		b.mu.Lock()
+		n := len(b.subscribers)
+		b.mu.Unlock()
+		if n == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	b.Publish(models.UserChange{Type: models.UserChangeDisabled})
+	assert.Equal(t, models.UserChangeDisabled, <-received)
+
+	// подписчик занят обработкой, очередь переполняется
+	for i := 0; i < changesBuffer+2; i++ {
+		b.Publish(models.UserChange{})
+	}
+	for {
+		select {
+		case <-received:
+			continue
+		case err := <-done:
+			assert.Equal(t, ErrWatcherLagging, err)
+		}
+		break
+	}
+}
@@ -18,6 +18,18 @@ var ErrEmailNotLinkedToAccount = errors.New("email is not linked to account")
 var ErrInvalidLoginOrPassword = errors.New("login or password is incorrect")
 var ErrUserDisabled = errors.New("user disabled")
 
+var ErrInvalidRecoveryCode = errors.New("recovery code is incorrect or expired")
+var ErrRecoveryTriesExceeded = errors.New("recovery code attempts exceeded, request a new code")
+
+var ErrInvalidAccessToken = errors.New("access token is incorrect or expired")
+
+var ErrRoleDoesNotExist = errors.New("role does not exist")
+var ErrRoleAlreadyExists = errors.New("role already exists")
+var ErrRoleInUse = errors.New("role is assigned to users")
+var ErrUnknownPermission = errors.New("unknown permission")
+
+var ErrInvalidTdID = errors.New("invalid tdid in list")
+
 func ErrInvalidTdIDList(id string) error {
-	return errors.New(fmt.Sprintf("invalid tdid in list: %s", id))
+	return fmt.Errorf("%w: %s", ErrInvalidTdID, id)
 }
@@ -0,0 +1,237 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/JetBrainer/sso/internal/domain/templates"
+	"github.com/JetBrainer/sso/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrSignInDelayed = errors.New("too many failed sign in attempts, try again later")
+	ErrAccountLocked = errors.New("account is temporarily locked after too many failed sign in attempts")
+)
+
+// LockoutError отказ во входе до окончания задержки между попытками или
+// блокировки учетной записи. errors.Is сопоставляет его с ErrSignInDelayed
+// или ErrAccountLocked.
+type LockoutError struct {
+	RetryAfter time.Duration
+	Locked     bool
+}
+
+func (e *LockoutError) Error() string {
+	return e.Unwrap().Error()
+}
+
+func (e *LockoutError) Unwrap() error {
+	if e.Locked {
+		return ErrAccountLocked
+	}
+
+	return ErrSignInDelayed
+}
+
+// LockoutPolicy защита от подбора пароля. После FreeAttempts неудачных
+// попыток подряд каждая следующая откладывает вход на BaseDelay, удваивая
+// задержку до MaxDelay. После Threshold неудач учетная запись блокируется на
+// Duration, каждая следующая блокировка вдвое дольше, но не дольше
+// MaxDuration. Неудачи старше Window забываются.
+type LockoutPolicy struct {
+	FreeAttempts int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	Threshold    int
+	Duration     time.Duration
+	MaxDuration  time.Duration
+	Window       time.Duration
+}
+
+// DefaultLockoutPolicy возвращает политику по умолчанию.
+func DefaultLockoutPolicy() LockoutPolicy {
+	return LockoutPolicy{
+		FreeAttempts: 3,
+		BaseDelay:    time.Second,
+		MaxDelay:     time.Minute,
+		Threshold:    10,
+		Duration:     time.Minute * 15,
+		MaxDuration:  time.Hour * 24,
+		Window:       time.Hour * 24,
+	}
+}
+
+// next возвращает время, до которого после очередной неудачи вход запрещен,
+// и является ли запрет блокировкой. Нулевое время - запрета нет.
+func (p LockoutPolicy) next(l *models.Lockout, now time.Time) (time.Time, bool) {
+	if l == nil {
+		return time.Time{}, false
+	}
+
+	if p.Threshold > 0 && l.Failures >= p.Threshold {
+		return now.Add(backoff(p.Duration, p.MaxDuration, l.Locks)), true
+	}
+
+	if p.BaseDelay > 0 && l.Failures > p.FreeAttempts {
+		return now.Add(backoff(p.BaseDelay, p.MaxDelay, l.Failures-p.FreeAttempts-1)), false
+	}
+
+	return time.Time{}, false
+}
+
+// backoff возвращает base, удвоенное n раз, но не больше max.
+func backoff(base, max time.Duration, n int) time.Duration {
+	d := base
+	for i := 0; i < n && (max <= 0 || d < max); i++ {
+		d *= 2
+	}
+
+	if max > 0 && d > max {
+		return max
+	}
+
+	return d
+}
+
+// WithLockout устанавливает политику защиты от подбора пароля.
+func (m *Manager) WithLockout(p LockoutPolicy) *Manager {
+	m.lockout = p
+	return m
+}
+
+// checkLockout отказывает во входе, пока не истекла задержка или блокировка.
+func (lm *LoginManager) checkLockout(user *models.User) error {
+	if wait := user.Lockout.Remaining(lm.now()); wait > 0 {
+		return &LockoutError{RetryAfter: wait, Locked: user.Lockout.Locked}
+	}
+
+	return nil
+}
+
+// failed учитывает неудачную попытку входа пользователя и, если пора,
+// откладывает следующую попытку или блокирует учетную запись. При блокировке
+// пользователь получает уведомление. Ошибки хранилища журналируются, чтобы
+// сбой учета не менял ответ на попытку входа.
+func (lm *LoginManager) failed(ctx context.Context, user *models.User) error {
+	now := lm.now().In(time.UTC)
+
+	lockout, err := lm.db.LockoutFailure(ctx, user.ID, now, now.Add(-lm.lockout.Window))
+	if err != nil {
+		logger.Printf(ctx, "[WARN] cannot count failed sign in of %s: %v", user.ID.Hex(), err)
+		return ErrInvalidLoginOrPassword
+	}
+
+	until, locked := lm.lockout.next(lockout, now)
+	if until.IsZero() {
+		return ErrInvalidLoginOrPassword
+	}
+
+	if err := lm.db.LockoutSet(ctx, user.ID, until, locked); err != nil {
+		logger.Printf(ctx, "[WARN] cannot delay sign in of %s: %v", user.ID.Hex(), err)
+		return ErrInvalidLoginOrPassword
+	}
+
+	if locked {
+		logger.Printf(ctx, "[INFO] account %s is locked until %s after failed sign in attempts", user.ID.Hex(), until.Format(time.RFC3339))
+		if err := lm.notifyLocked(ctx, user, until); err != nil {
+			logger.Printf(ctx, "[ERROR] cannot enqueue lock notification for %s: %v", user.ID.Hex(), err)
+		}
+	}
+
+	return &LockoutError{RetryAfter: until.Sub(now), Locked: locked}
+}
+
+// unknownFailed учитывает неудачную попытку входа с логином, для которого нет
+// учетной записи, по той же политике, что и для существующих. Пароль
+// сверяется с фиктивным хэшем, чтобы время ответа тоже не отличалось.
+func (lm *LoginManager) unknownFailed(ctx context.Context, login, password string) error {
+	key := strings.ToLower(strings.TrimSpace(login))
+	failures := lm.db.LoginFailures()
+	now := lm.now().In(time.UTC)
+
+	current, err := failures.ByKey(ctx, key)
+	if err != nil {
+		logger.Printf(ctx, "[WARN] cannot check failed sign in of unknown login: %v", err)
+		return ErrInvalidLoginOrPassword
+	}
+	if wait := current.Remaining(now); wait > 0 {
+		return &LockoutError{RetryAfter: wait, Locked: current.Locked}
+	}
+
+	CheckPasswordHash(password, unknownHash())
+
+	lockout, err := failures.Failure(ctx, key, now, now.Add(-lm.lockout.Window))
+	if err != nil {
+		logger.Printf(ctx, "[WARN] cannot count failed sign in of unknown login: %v", err)
+		return ErrInvalidLoginOrPassword
+	}
+
+	until, locked := lm.lockout.next(lockout, now)
+	if until.IsZero() {
+		return ErrInvalidLoginOrPassword
+	}
+
+	if err := failures.Set(ctx, key, until, locked); err != nil {
+		logger.Printf(ctx, "[WARN] cannot delay sign in of unknown login: %v", err)
+		return ErrInvalidLoginOrPassword
+	}
+
+	return &LockoutError{RetryAfter: until.Sub(now), Locked: locked}
+}
+
+var (
+	unknownHashOnce  sync.Once
+	unknownHashValue []byte
+)
+
+// unknownHash возвращает хэш случайного пароля той же стоимости, что и
+// хэши паролей пользователей.
+func unknownHash() []byte {
+	unknownHashOnce.Do(func() {
+		unknownHashValue, _ = HashPassword(primitive.NewObjectID().Hex())
+	})
+
+	return unknownHashValue
+}
+
+// succeeded удаляет учет неудачных попыток после успешного входа.
+func (lm *LoginManager) succeeded(ctx context.Context, user *models.User) {
+	if user.Lockout == nil {
+		return
+	}
+
+	if err := lm.db.LockoutReset(ctx, user.ID); err != nil {
+		logger.Printf(ctx, "[WARN] cannot reset sign in lockout of %s: %v", user.ID.Hex(), err)
+	}
+}
+
+// notifyLocked ставит в очередь уведомление о блокировке на email
+// пользователя, а если его нет - на основной телефон.
+func (lm *LoginManager) notifyLocked(ctx context.Context, user *models.User, until time.Time) error {
+	channel, recipient := models.OutboxChannelEmail, user.Email
+	if recipient == "" {
+		channel, recipient = models.OutboxChannelSMS, user.PrimaryPhone
+	}
+	if recipient == "" {
+		return nil
+	}
+
+	msg := models.NewOutboxMessage(
+		models.OutboxKey(templates.Locked, user.ID.Hex(), strconv.FormatInt(until.UnixNano(), 10)),
+		templates.Locked,
+		channel,
+		recipient,
+	)
+	msg.TDID = models.PolymorphicID(user.ID.Hex())
+	msg.Language = user.Language
+	msg.Name = user.FirstName
+	msg.Until = &until
+
+	return lm.db.Outbox().Enqueue(ctx, msg)
+}
@@ -0,0 +1,296 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/JetBrainer/sso/internal/domain/templates"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// lockoutStore хранит одного пользователя и учитывает неудачные попытки
+// входа так же, как MongoDB.
+type lockoutStore struct {
+	drivers.DataStore
+	user     *models.User
+	outbox   *outboxStore
+	failures loginFailures
+}
+
+func (s *lockoutStore) UserByLogin(_ context.Context, login string) (*models.User, error) {
+	if login != s.user.Login {
+		return nil, drivers.ErrUserDoesNotExist
+	}
+
+	u := *s.user
+	if s.user.Lockout != nil {
+		l := *s.user.Lockout
+		u.Lockout = &l
+	}
+
+	return &u, nil
+}
+
+func (s *lockoutStore) UserByTDID(ctx context.Context, _ primitive.ObjectID) (*models.User, error) {
+	return s.UserByLogin(ctx, s.user.Login)
+}
+
+func (s *lockoutStore) UserUpdate(_ context.Context, user *models.User) error {
+	s.user.Password = user.Password
+	return nil
+}
+
+func (s *lockoutStore) LockoutFailure(_ context.Context, _ primitive.ObjectID, now, resetBefore time.Time) (*models.Lockout, error) {
+	if s.user.Lockout == nil {
+		s.user.Lockout = &models.Lockout{}
+	}
+	if s.user.Lockout.LastFailure.Before(resetBefore) {
+		s.user.Lockout.Failures, s.user.Lockout.Locks = 0, 0
+	}
+	s.user.Lockout.Failures++
+	s.user.Lockout.LastFailure = now
+
+	l := *s.user.Lockout
+	return &l, nil
+}
+
+func (s *lockoutStore) LockoutSet(_ context.Context, _ primitive.ObjectID, until time.Time, lock bool) error {
+	s.user.Lockout.Until = until
+	s.user.Lockout.Locked = lock
+	if lock {
+		s.user.Lockout.Failures = 0
+		s.user.Lockout.Locks++
+	}
+
+	return nil
+}
+
+func (s *lockoutStore) LockoutReset(context.Context, primitive.ObjectID) error {
+	s.user.Lockout = nil
+	return nil
+}
+
+func (s *lockoutStore) Outbox() drivers.OutboxRepository {
+	return s.outbox
+}
+
+func (s *lockoutStore) LoginFailures() drivers.LoginFailuresRepository {
+	if s.failures == nil {
+		s.failures = make(loginFailures)
+	}
+
+	return s.failures
+}
+
+// loginFailures учет попыток входа с несуществующими логинами.
+type loginFailures map[string]*models.Lockout
+
+func (f loginFailures) ByKey(_ context.Context, key string) (*models.Lockout, error) {
+	return f[key], nil
+}
+
+func (f loginFailures) Failure(_ context.Context, key string, now, resetBefore time.Time) (*models.Lockout, error) {
+	l, ok := f[key]
+	if !ok {
+		l = &models.Lockout{}
+		f[key] = l
+	}
+	if l.LastFailure.Before(resetBefore) {
+		l.Failures, l.Locks = 0, 0
+	}
+	l.Failures++
+	l.LastFailure = now
+
+	c := *l
+	return &c, nil
+}
+
+func (f loginFailures) Set(_ context.Context, key string, until time.Time, lock bool) error {
+	l := f[key]
+	l.Until = until
+	l.Locked = lock
+	if lock {
+		l.Failures = 0
+		l.Locks++
+	}
+
+	return nil
+}
+
+type outboxStore struct {
+	drivers.OutboxRepository
+	messages []*models.OutboxMessage
+}
+
+func (o *outboxStore) Enqueue(_ context.Context, msg *models.OutboxMessage) error {
+	o.messages = append(o.messages, msg)
+	return nil
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, backoff(time.Second, time.Minute, 0))
+	assert.Equal(t, 8*time.Second, backoff(time.Second, time.Minute, 3))
+	assert.Equal(t, time.Minute, backoff(time.Second, time.Minute, 10))
+	assert.Equal(t, time.Minute, backoff(time.Second, time.Minute, 1000))
+}
+
+func TestLoginManager_Lockout(t *testing.T) {
+	hash, err := HashPassword("correct horse")
+	assert.NoError(t, err)
+
+	store := &lockoutStore{
+		user: &models.User{
+			ID:       primitive.NewObjectID(),
+			Login:    "user@example.com",
+			Email:    "user@example.com",
+			Password: string(hash),
+			Enabled:  true,
+		},
+		outbox: &outboxStore{},
+	}
+
+	now := time.Date(2021, 2, 1, 10, 0, 0, 0, time.UTC)
+	lm := &LoginManager{
+		db:    store,
+		users: &Users{db: store},
+		lockout: LockoutPolicy{
+			FreeAttempts: 2,
+			BaseDelay:    time.Second,
+			MaxDelay:     time.Minute,
+			Threshold:    4,
+			Duration:     time.Minute * 15,
+			MaxDuration:  time.Hour,
+			Window:       time.Hour * 24,
+		},
+		now: func() time.Time { return now },
+	}
+
+	// первые неудачи без задержки
+	for i := 0; i < 2; i++ {
+		_, err := lm.SignInByLogin("user@example.com", "wrong")
+		assert.Equal(t, ErrInvalidLoginOrPassword, err)
+	}
+
+	// третья неудача откладывает следующую попытку
+	_, err = lm.SignInByLogin("user@example.com", "wrong")
+	var lockErr *LockoutError
+	assert.True(t, errors.As(err, &lockErr))
+	assert.True(t, errors.Is(err, ErrSignInDelayed))
+	assert.Equal(t, time.Second, lockErr.RetryAfter)
+
+	// во время задержки не принимается даже верный пароль
+	_, err = lm.SignInByLogin("user@example.com", "correct horse")
+	assert.True(t, errors.Is(err, ErrSignInDelayed))
+
+	// четвертая неудача блокирует учетную запись и уведомляет пользователя
+	now = now.Add(time.Second)
+	_, err = lm.SignInByLogin("user@example.com", "wrong")
+	assert.True(t, errors.As(err, &lockErr))
+	assert.True(t, errors.Is(err, ErrAccountLocked))
+	assert.Equal(t, time.Minute*15, lockErr.RetryAfter)
+	if assert.Len(t, store.outbox.messages, 1) {
+		msg := store.outbox.messages[0]
+		assert.Equal(t, templates.Locked, msg.Template)
+		assert.Equal(t, "user@example.com", msg.Recipient)
+		assert.Equal(t, now.Add(time.Minute*15), *msg.Until)
+	}
+
+	// после окончания блокировки следующая вдвое дольше
+	now = now.Add(time.Minute * 15)
+	for i := 0; i < 4; i++ {
+		now = now.Add(time.Minute)
+		_, err = lm.SignInByLogin("user@example.com", "wrong")
+	}
+	assert.True(t, errors.As(err, &lockErr))
+	assert.True(t, lockErr.Locked)
+	assert.Equal(t, time.Minute*30, lockErr.RetryAfter)
+
+	// успешный вход после блокировки сбрасывает учет
+	now = now.Add(time.Minute * 30)
+	tdid, err := lm.SignInByLogin("user@example.com", "correct horse")
+	assert.NoError(t, err)
+	assert.Equal(t, store.user.ID.Hex(), tdid)
+	assert.Nil(t, store.user.Lockout)
+
+	// новый пароль, например при восстановлении доступа, снимает блокировку
+	for i := 0; i < 4; i++ {
+		now = now.Add(time.Minute)
+		_, _ = lm.SignInByLogin("user@example.com", "wrong")
+	}
+	_, err = lm.SignInByLogin("user@example.com", "correct horse")
+	assert.True(t, errors.Is(err, ErrAccountLocked))
+	assert.NoError(t, lm.users.UpdatePassword(store.user.ID, "battery staple"))
+	assert.Nil(t, store.user.Lockout)
+	_, err = lm.SignInByLogin("user@example.com", "battery staple")
+	assert.NoError(t, err)
+
+	// неизвестный логин не отличается от неверного пароля
+	_, err = lm.SignInByLogin("nobody@example.com", "wrong")
+	assert.Equal(t, ErrInvalidLoginOrPassword, err)
+}
+
+func TestLoginManager_UnknownLoginLockout(t *testing.T) {
+	hash, err := HashPassword("correct horse")
+	assert.NoError(t, err)
+
+	store := &lockoutStore{
+		user: &models.User{
+			ID:       primitive.NewObjectID(),
+			Login:    "user@example.com",
+			Email:    "user@example.com",
+			Password: string(hash),
+			Enabled:  true,
+		},
+		outbox: &outboxStore{},
+	}
+
+	now := time.Date(2021, 2, 1, 10, 0, 0, 0, time.UTC)
+	lm := &LoginManager{
+		db:    store,
+		users: &Users{db: store},
+		lockout: LockoutPolicy{
+			FreeAttempts: 1,
+			BaseDelay:    time.Second,
+			MaxDelay:     time.Minute,
+			Threshold:    3,
+			Duration:     time.Minute * 15,
+			MaxDuration:  time.Hour,
+			Window:       time.Hour * 24,
+		},
+		now: func() time.Time { return now },
+	}
+
+	// существующий и несуществующий логины получают одинаковые ответы
+	attempts := []struct {
+		advance time.Duration
+		err     error
+		retry   time.Duration
+	}{
+		{0, ErrInvalidLoginOrPassword, 0},
+		{0, ErrSignInDelayed, time.Second},
+		{0, ErrSignInDelayed, time.Second},
+		{time.Second, ErrAccountLocked, time.Minute * 15},
+		{time.Minute, ErrAccountLocked, time.Minute * 14},
+	}
+	for i, a := range attempts {
+		now = now.Add(a.advance)
+		for _, login := range []string{"user@example.com", " Nobody@Example.com"} {
+			_, err := lm.SignInByLogin(login, "wrong")
+			assert.True(t, errors.Is(err, a.err), "attempt %d, %s: %v", i, login, err)
+
+			var lockErr *LockoutError
+			if a.retry > 0 && assert.True(t, errors.As(err, &lockErr)) {
+				assert.Equal(t, a.retry, lockErr.RetryAfter, "attempt %d, %s", i, login)
+			}
+		}
+	}
+
+	// логин нормализуется, так что регистр не дает новых попыток
+	_, err = lm.SignInByLogin("NOBODY@example.com", "wrong")
+	assert.True(t, errors.Is(err, ErrAccountLocked))
+}
@@ -1,6 +1,9 @@
 package auth
 
 import (
+	"context"
+
+	"github.com/JetBrainer/sso/internal/domain/models"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -10,61 +13,63 @@ import (
 // выключенного пользователя. Функция возвращает глобальный идентификатор
 // пользователя и ошибку.
 func (lm *LoginManager) SignInByLogin(login, password string) (string, error) {
-	// совпадает ли хэш? существует ли такой пользователь?
-	valid, _ := lm.users.CheckPasswordByLogin(login, password)
-	// Не нужно обрабатывать error от users.CheckPasswordByTDID(), дабы при
+	// Не нужно обрабатывать error от users.ByLogin(), дабы при
 	// неверном имени пользователя иметь ту же самую ошибку ErrInvalidLoginOrPassword, что
 	// и при неправильном пароле. Тем самым мы избавляемся от проблемы брутфорса логинов.
-
-	// если пользователь не валидный, посылаем
-	if !valid {
-		return "", ErrInvalidLoginOrPassword
-	}
-
+	// Неудачи с несуществующим логином учитываются так же, как для
+	// существующего, чтобы задержка и блокировка его не выдавали.
 	user, err := lm.users.ByLogin(login)
 	if err != nil {
-		return "", err
-	}
-
-	// проверяем, если пользователь включен
-	if !user.Enabled {
-		return user.ID.Hex(), ErrUserDisabled
+		return "", lm.unknownFailed(context.Background(), login, password)
 	}
 
-	return user.ID.Hex(), nil
+	return lm.signIn(user, password)
 }
 
 func (lm *LoginManager) SignInByTDID(tdid primitive.ObjectID, password string) (string, error) {
-	// совпадает ли хэш? существует ли такой пользователь?
-	valid, _ := lm.users.CheckPasswordByTDID(tdid, password)
-	// Не нужно обрабатывать error от users.CheckPasswordByTDID(), дабы при
+	// Не нужно обрабатывать error от users.ByTDID(), дабы при
 	// неверном имени пользователя иметь ту же самую ошибку ErrInvalidLoginOrPassword, что
 	// и при неправильном пароле. Тем самым мы избавляемся от проблемы брутфорса логинов.
-
-	// если пользователь не валидный, посылаем
-	if !valid {
-		return "", ErrInvalidLoginOrPassword
-	}
-
 	user, err := lm.users.ByTDID(tdid)
 	if err != nil {
-		return "", err
-	}
-
-	// проверяем, если пользователь включен
-	if !user.Enabled {
-		return user.ID.Hex(), ErrUserDisabled
+		return "", lm.unknownFailed(context.Background(), tdid.Hex(), password)
 	}
 
-	return user.ID.Hex(), nil
+	return lm.signIn(user, password)
 }
 
 // SignInByEmail осуществляет логику входа пользователя в систему по его email.
 func (lm *LoginManager) SignInByEmail(email, password string) (string, error) {
+	// как и для логина, несуществующий email не отличается от неверного пароля
 	user, err := lm.users.ByEmail(email)
-	if err != nil {
+	if err != nil || user == nil {
+		return "", lm.unknownFailed(context.Background(), email, password)
+	}
+
+	return lm.signIn(user, password)
+}
+
+// signIn сверяет пароль найденного пользователя. Пока действует задержка
+// после неудачных попыток или блокировка, пароль не проверяется, каждая
+// неудача учитывается.
+func (lm *LoginManager) signIn(user *models.User, password string) (string, error) {
+	ctx := context.Background()
+
+	if err := lm.checkLockout(user); err != nil {
 		return "", err
 	}
 
-	return lm.SignInByTDID(user.ID, password)
+	// совпадает ли хэш?
+	if !CheckPasswordHash(password, []byte(user.Password)) {
+		return "", lm.failed(ctx, user)
+	}
+
+	lm.succeeded(ctx, user)
+
+	// проверяем, если пользователь включен
+	if !user.Enabled {
+		return user.ID.Hex(), ErrUserDisabled
+	}
+
+	return user.ID.Hex(), nil
 }
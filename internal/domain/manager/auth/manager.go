@@ -1,11 +1,13 @@
 package auth
 
 import (
-	"log"
+	"context"
 	"time"
 
 	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	"github.com/JetBrainer/sso/internal/domain/manager/webhooks"
 	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/JetBrainer/sso/pkg/logger"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/go-chi/jwtauth"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -19,6 +21,12 @@ type Manager struct {
 	RefreshTokenTTL  time.Duration
 	DelegateTokenTTL time.Duration
 	isTesting        bool
+	lockout          LockoutPolicy
+	passwords        PasswordPolicy
+
+	permissions *PermissionsManager
+	webhooks    *webhooks.Manager
+	changes     *Broadcaster
 }
 
 // Claims структура, хранящая закодированный JWT авторизации.
@@ -37,6 +45,10 @@ func New(db drivers.DataStore, jwtKey []byte, tokenTTL, refreshTokenTTL, delegat
 		TokenTTL:         tokenTTL,
 		RefreshTokenTTL:  refreshTokenTTL,
 		DelegateTokenTTL: delegateTokenTTL,
+		lockout:          DefaultLockoutPolicy(),
+		passwords:        DefaultPasswordPolicy(),
+		permissions:      newPermissionsManager(db),
+		changes:          NewBroadcaster(DefaultChangesHistory),
 	}
 }
 
@@ -44,6 +56,19 @@ func (m *Manager) Testing() {
 	m.isTesting = true
 }
 
+// WithWebhooks устанавливает менеджер webhook, через который подписчикам
+// публикуются изменения пользователей.
+func (m *Manager) WithWebhooks(wm *webhooks.Manager) *Manager {
+	m.webhooks = wm
+	return m
+}
+
+// WithPasswordPolicy устанавливает требования к новым паролям.
+func (m *Manager) WithPasswordPolicy(p PasswordPolicy) *Manager {
+	m.passwords = p
+	return m
+}
+
 // JWT key
 func (m *Manager) JWTKey() []byte {
 	return m.jwtKey
@@ -53,7 +78,7 @@ func (m *Manager) TokenAuth() *jwtauth.JWTAuth {
 	return jwtauth.New("HS256", m.jwtKey, nil)
 }
 
-func (m Manager) NewRefreshToken(tdid string) (string, error) {
+func (m Manager) NewRefreshToken(ctx context.Context, tdid string) (string, error) {
 	claims := new(Claims)
 	claims.TDID = tdid
 	claims.IsRefresh = true
@@ -61,12 +86,12 @@ func (m Manager) NewRefreshToken(tdid string) (string, error) {
 
 	userTDID, err := primitive.ObjectIDFromHex(tdid)
 	if err != nil {
-		log.Printf("Could not convert %s into primitive object id", tdid)
+		logger.Printf(ctx, "[WARN] could not convert %s into primitive object id", tdid)
 		return "", err
 	}
 	user, err := m.Users().ByTDID(userTDID)
 	if err != nil {
-		log.Printf("Could not find user with tdid %s to add roles into JWT token", tdid)
+		logger.Printf(ctx, "[WARN] could not find user with tdid %s to add roles into JWT token", tdid)
 		return "", err
 	}
 	claims.Roles = user.Roles
@@ -76,19 +101,19 @@ func (m Manager) NewRefreshToken(tdid string) (string, error) {
 	return tokenString, err
 }
 
-func (m Manager) NewAccessToken(tdid string) (string, error) {
+func (m Manager) NewAccessToken(ctx context.Context, tdid string) (string, error) {
 	claims := new(Claims)
 	claims.TDID = tdid
 	claims.ExpiresAt = time.Now().Add(m.TokenTTL).Unix()
 
 	userTDID, err := primitive.ObjectIDFromHex(tdid)
 	if err != nil {
-		log.Printf("Could not convert %s into primitive object id", tdid)
+		logger.Printf(ctx, "[WARN] could not convert %s into primitive object id", tdid)
 		return "", err
 	}
 	user, err := m.Users().ByTDID(userTDID)
 	if err != nil {
-		log.Printf("Could not find user with tdid %s to add roles into JWT token", tdid)
+		logger.Printf(ctx, "[WARN] could not find user with tdid %s to add roles into JWT token", tdid)
 		return "", err
 	}
 	claims.Roles = user.Roles
@@ -98,6 +123,23 @@ func (m Manager) NewAccessToken(tdid string) (string, error) {
 	return tokenString, err
 }
 
+// ParseAccessToken проверяет подпись и срок действия access токена и
+// возвращает его claims. Refresh токены не принимаются.
+func (m Manager) ParseAccessToken(token string) (*Claims, error) {
+	claims := new(Claims)
+	tkn, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		return m.jwtKey, nil
+	})
+	if err != nil {
+		return nil, ErrInvalidAccessToken
+	}
+
+	if !tkn.Valid || claims.IsRefresh {
+		return nil, ErrInvalidAccessToken
+	}
+
+	return claims, nil
+}
 
 func userToClaims(u *models.User) UserClaims {
 	return UserClaims{
@@ -112,22 +154,29 @@ func userToClaims(u *models.User) UserClaims {
 
 // Users осуществляет примитивы для работы с пользователями.
 type Users struct {
-	db drivers.DataStore
+	db        drivers.DataStore
+	webhooks  *webhooks.Manager
+	changes   *Broadcaster
+	passwords PasswordPolicy
 }
 
 // Users создает менеджер по управлению пользователями.
 func (m *Manager) Users() *Users {
-	return &Users{db: m.db}
+	return &Users{db: m.db, webhooks: m.webhooks, changes: m.changes, passwords: m.passwords}
 }
 
 type LoginManager struct {
-	db    drivers.DataStore
-	users *Users
+	db      drivers.DataStore
+	users   *Users
+	lockout LockoutPolicy
+	now     func() time.Time
 }
 
 func (m *Manager) LoginManager() *LoginManager {
 	return &LoginManager{
-		db:    m.db,
-		users: m.Users(),
+		db:      m.db,
+		users:   m.Users(),
+		lockout: m.lockout,
+		now:     time.Now,
 	}
 }
@@ -0,0 +1,180 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/JetBrainer/sso/utils"
+)
+
+// Правила политики паролей.
+const (
+	PasswordRuleMinLength = "min_length"
+	PasswordRuleMaxLength = "max_length"
+	PasswordRuleLower     = "lower"
+	PasswordRuleUpper     = "upper"
+	PasswordRuleDigit     = "digit"
+	PasswordRuleSymbol    = "symbol"
+	PasswordRulePersonal  = "personal_data"
+)
+
+const (
+	// MaxPasswordBytes - bcrypt учитывает только первые 72 байта пароля,
+	// остальное молча отбрасывается.
+	MaxPasswordBytes = 72
+
+	minPersonalLength = 3  // более короткие части имени и email не проверяются
+	phoneDigits       = 10 // номер без кода страны
+)
+
+var (
+	ErrWeakPassword        = errors.New("password does not meet the password policy")
+	ErrUnknownPasswordRule = errors.New("unknown password character class")
+)
+
+// PasswordPolicyError перечисляет нарушенные правила политики паролей.
+// errors.Is сопоставляет его с ErrWeakPassword.
+type PasswordPolicyError struct {
+	Violations []models.RuleViolation
+}
+
+func (e *PasswordPolicyError) Error() string {
+	rules := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		rules = append(rules, v.Rule)
+	}
+
+	return fmt.Sprintf("%s: %s", ErrWeakPassword, strings.Join(rules, ", "))
+}
+
+func (e *PasswordPolicyError) Unwrap() error {
+	return ErrWeakPassword
+}
+
+// PasswordPolicy требования к новым паролям. Длина ограничивается снизу в
+// символах и сверху в байтах, классы символов перечисляются правилами
+// PasswordRuleLower, PasswordRuleUpper, PasswordRuleDigit и PasswordRuleSymbol.
+type PasswordPolicy struct {
+	MinLength        int
+	Require          []string
+	DisallowPersonal bool // пароль не может содержать телефон, email или имя пользователя
+}
+
+// DefaultPasswordPolicy возвращает политику по умолчанию: длина без
+// требований к составу и запрет личных данных.
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:        8,
+		DisallowPersonal: true,
+	}
+}
+
+// ParsePasswordClasses разбирает список классов символов из конфигурации.
+func ParsePasswordClasses(classes []string) ([]string, error) {
+	var rules []string
+	for _, class := range classes {
+		class = strings.ToLower(strings.TrimSpace(class))
+		switch class {
+		case "":
+			continue
+		case PasswordRuleLower, PasswordRuleUpper, PasswordRuleDigit, PasswordRuleSymbol:
+			rules = append(rules, class)
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrUnknownPasswordRule, class)
+		}
+	}
+
+	return rules, nil
+}
+
+// Validate проверяет пароль пользователя user и возвращает
+// *PasswordPolicyError со всеми нарушенными правилами. user может быть nil,
+// тогда личные данные не проверяются.
+func (p PasswordPolicy) Validate(password string, user *models.User) error {
+	var violations []models.RuleViolation
+	violate := func(rule, format string, args ...interface{}) {
+		violations = append(violations, models.RuleViolation{
+			Field:   "password",
+			Rule:    rule,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	if utf8.RuneCountInString(password) < p.MinLength {
+		violate(PasswordRuleMinLength, "must be at least %d characters long", p.MinLength)
+	}
+
+	if len(password) > MaxPasswordBytes {
+		violate(PasswordRuleMaxLength, "must be at most %d bytes long", MaxPasswordBytes)
+	}
+
+	for _, class := range p.Require {
+		if !containsClass(password, class) {
+			violate(class, "must contain at least one %s character", class)
+		}
+	}
+
+	if p.DisallowPersonal && user != nil && containsPersonal(password, user) {
+		violate(PasswordRulePersonal, "must not contain phone number, email or name")
+	}
+
+	if len(violations) > 0 {
+		return &PasswordPolicyError{Violations: violations}
+	}
+
+	return nil
+}
+
+// containsClass сообщает, есть ли в пароле символ класса class.
+func containsClass(password, class string) bool {
+	for _, r := range password {
+		switch {
+		case class == PasswordRuleLower && unicode.IsLower(r),
+			class == PasswordRuleUpper && unicode.IsUpper(r),
+			class == PasswordRuleDigit && unicode.IsDigit(r),
+			class == PasswordRuleSymbol && !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsSpace(r):
+			return true
+		}
+	}
+
+	return false
+}
+
+// containsPersonal сообщает, содержит ли пароль телефон, email, часть email
+// до @ или имя пользователя без учета регистра.
+func containsPersonal(password string, user *models.User) bool {
+	lower := strings.ToLower(password)
+
+	parts := []string{user.FirstName, user.LastName, user.Patronymic, user.Email}
+	if i := strings.Index(user.Email, "@"); i > 0 {
+		parts = append(parts, user.Email[:i])
+	}
+
+	for _, part := range parts {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if utf8.RuneCountInString(part) >= minPersonalLength && strings.Contains(lower, part) {
+			return true
+		}
+	}
+
+	phones := append([]string{user.PrimaryPhone}, user.Phones...)
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, password)
+
+	for _, phone := range phones {
+		phone = utils.NormPhoneNum(phone)
+		if len(phone) >= phoneDigits && strings.Contains(digits, phone[len(phone)-phoneDigits:]) {
+			return true
+		}
+	}
+
+	return false
+}
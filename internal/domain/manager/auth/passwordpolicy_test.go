@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// rules возвращает нарушенные правила из ошибки политики паролей.
+func rules(t *testing.T, err error) []string {
+	var policyErr *PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		t.Fatalf("expected password policy error, got %v", err)
+	}
+
+	var rules []string
+	for _, v := range policyErr.Violations {
+		rules = append(rules, v.Rule)
+	}
+
+	return rules
+}
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	policy := PasswordPolicy{
+		MinLength:        8,
+		Require:          []string{PasswordRuleLower, PasswordRuleUpper, PasswordRuleDigit, PasswordRuleSymbol},
+		DisallowPersonal: true,
+	}
+	user := &models.User{
+		FirstName:    "Айдос",
+		LastName:     "Ли",
+		Email:        "aidos.k@example.com",
+		PrimaryPhone: "77071234567",
+	}
+
+	assert.NoError(t, policy.Validate("Tr0ub4dor&3", user))
+	assert.NoError(t, policy.Validate("Ключ-Доступа1", user))
+
+	// все нарушения перечисляются сразу
+	err := policy.Validate("abc", user)
+	assert.True(t, errors.Is(err, ErrWeakPassword))
+	assert.Equal(t, []string{PasswordRuleMinLength, PasswordRuleUpper, PasswordRuleDigit, PasswordRuleSymbol}, rules(t, err))
+
+	// длина в символах, а не в байтах
+	assert.Equal(t, []string{PasswordRuleMinLength}, rules(t, policy.Validate("Ключ-1я", nil)))
+
+	// bcrypt не учитывает байты после 72-го
+	assert.Equal(t, []string{PasswordRuleMaxLength}, rules(t, policy.Validate("Aa1!"+strings.Repeat("x", MaxPasswordBytes), nil)))
+
+	// личные данные без учета регистра, телефон в любой записи, короткие части имени не проверяются
+	for _, password := range []string{"АЙДОС-2021!a", "Aidos.K@example.com1", "My-8(707)123-45-67a", "+77071234567Aa!"} {
+		assert.Equal(t, []string{PasswordRulePersonal}, rules(t, policy.Validate(password, user)), password)
+	}
+	assert.NoError(t, policy.Validate("Лимон-2021!", user))
+	assert.NoError(t, PasswordPolicy{MinLength: 8}.Validate("aidos.k@example.com", user))
+}
+
+func TestParsePasswordClasses(t *testing.T) {
+	classes, err := ParsePasswordClasses([]string{" Digit", "", "symbol"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{PasswordRuleDigit, PasswordRuleSymbol}, classes)
+
+	_, err = ParsePasswordClasses([]string{"emoji"})
+	assert.True(t, errors.Is(err, ErrUnknownPasswordRule))
+}
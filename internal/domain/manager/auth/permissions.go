@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+)
+
+const defaultPermissionsCacheTTL = time.Minute // время жизни закэшированных прав роли
+
+// PermissionsManager вычисляет права пользователя на основе его ролей.
+// Права ролей кэшируются, чтобы не обращаться к БД на каждый запрос.
+type PermissionsManager struct {
+	db       drivers.DataStore
+	cacheTTL time.Duration
+
+	mu    sync.RWMutex
+	cache map[string]cachedRole
+}
+
+// cachedRole хранит права роли и момент, после которого они считаются устаревшими.
+type cachedRole struct {
+	permissions []string
+	expiresAt   time.Time
+}
+
+func newPermissionsManager(db drivers.DataStore) *PermissionsManager {
+	return &PermissionsManager{
+		db:       db,
+		cacheTTL: defaultPermissionsCacheTTL,
+		cache:    make(map[string]cachedRole),
+	}
+}
+
+// PermissionsManager возвращает общий для всего приложения менеджер прав.
+func (m *Manager) PermissionsManager() *PermissionsManager {
+	return m.permissions
+}
+
+// WithCacheTTL устанавливает время жизни закэшированных прав роли.
+func (pm *PermissionsManager) WithCacheTTL(t time.Duration) *PermissionsManager {
+	pm.cacheTTL = t
+	return pm
+}
+
+// Invalidate удаляет роль из кэша. Вызывается при изменении или удалении роли.
+func (pm *PermissionsManager) Invalidate(role string) {
+	pm.mu.Lock()
+	delete(pm.cache, role)
+	pm.mu.Unlock()
+}
+
+// ByRoles возвращает список прав, выданных перечисленным ролям.
+// Несуществующие роли пропускаются.
+func (pm *PermissionsManager) ByRoles(ctx context.Context, roles []string) ([]string, error) {
+	seen := make(map[string]struct{})
+	permissions := make([]string, 0)
+
+	for _, name := range roles {
+		rolePermissions, err := pm.byRole(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, permission := range rolePermissions {
+			if _, ok := seen[permission]; ok {
+				continue
+			}
+
+			seen[permission] = struct{}{}
+			permissions = append(permissions, permission)
+		}
+	}
+
+	return permissions, nil
+}
+
+// HasPermission проверяет, выдано ли право permission хотя бы одной из ролей.
+func (pm *PermissionsManager) HasPermission(ctx context.Context, roles []string, permission string) (bool, error) {
+	permissions, err := pm.ByRoles(ctx, roles)
+	if err != nil {
+		return false, err
+	}
+
+	for _, p := range permissions {
+		if p == permission {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// byRole возвращает права одной роли, по возможности из кэша.
+func (pm *PermissionsManager) byRole(ctx context.Context, name string) ([]string, error) {
+	pm.mu.RLock()
+	cached, ok := pm.cache[name]
+	pm.mu.RUnlock()
+
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.permissions, nil
+	}
+
+	permissions := make([]string, 0)
+
+	role, err := pm.db.Roles().RoleByName(ctx, name)
+	switch err {
+	case nil:
+		for _, group := range role.Permissions {
+			permissions = append(permissions, group...)
+		}
+	case drivers.ErrRoleDoesNotExist:
+		// кэшируем и отсутствие роли, чтобы не опрашивать БД повторно
+	default:
+		return nil, err
+	}
+
+	pm.mu.Lock()
+	pm.cache[name] = cachedRole{
+		permissions: permissions,
+		expiresAt:   time.Now().Add(pm.cacheTTL),
+	}
+	pm.mu.Unlock()
+
+	return permissions, nil
+}
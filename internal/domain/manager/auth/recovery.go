@@ -2,12 +2,16 @@ package auth
 
 import (
 	"context"
+	"crypto/subtle"
+	"errors"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
 	"github.com/JetBrainer/sso/internal/domain"
 	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/JetBrainer/sso/internal/domain/templates"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -61,7 +65,84 @@ func (rm *RecoveryManager) RecoveryByEmail(ctx context.Context, email string) er
 		return err
 	}
 
-	return rm.db.RestoreByEmailNew(ctx, user.ID, email, token, time.Now().In(time.UTC).Add(restoreTTL))
+	expiredAt := time.Now().In(time.UTC).Add(restoreTTL)
+
+	msg := models.NewOutboxMessage(
+		models.OutboxKey(templates.Restore, user.ID.Hex(), strconv.FormatInt(expiredAt.UnixNano(), 10)),
+		templates.Restore,
+		models.OutboxChannelEmail,
+		email,
+	)
+	msg.TDID = models.PolymorphicID(user.ID.Hex())
+	msg.Language = user.Language
+	msg.Name = user.FirstName
+	msg.Code = token
+
+	return rm.db.Transaction(ctx, func(ctx context.Context) error {
+		if err := rm.db.RestoreByEmailNew(ctx, user.ID, email, token, expiredAt); err != nil {
+			return err
+		}
+
+		return rm.db.Outbox().Enqueue(ctx, msg)
+	})
+}
+
+// ResetPassword завершает восстановление доступа по email: сверяет код
+// восстановления, устанавливает новый пароль и снимает блокировку входа после
+// неудачных попыток. Каждая проверка кода расходует одну из TriesLimit попыток,
+// поэтому пароль проверяется по политике паролей до нее.
+func (rm *RecoveryManager) ResetPassword(ctx context.Context, email, code, password string) (*models.User, error) {
+	user, err := rm.users.ByEmail(email)
+	if err != nil || user == nil {
+		return nil, ErrInvalidRecoveryCode
+	}
+
+	if user.Restore == nil || user.Restore.Token == "" || user.Restore.Email != email {
+		return nil, ErrInvalidRecoveryCode
+	}
+
+	if err := rm.users.ValidatePassword(password, user); err != nil {
+		return nil, err
+	}
+
+	// попытка расходуется до сравнения, чтобы параллельные запросы не
+	// получили больше TriesLimit проверок одного кода
+	restore, err := rm.db.RestoreAttempt(ctx, user.ID, TriesLimit)
+	if errors.Is(err, drivers.ErrRestoreTriesExceeded) {
+		return nil, ErrRecoveryTriesExceeded
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// код мог быть перевыпущен между чтением пользователя и попыткой
+	if restore == nil || restore.Email != email || !time.Now().In(time.UTC).Before(restore.Expired) ||
+		subtle.ConstantTimeCompare([]byte(restore.Token), []byte(code)) != 1 {
+		return nil, ErrInvalidRecoveryCode
+	}
+
+	hash, err := HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+	user.Password = string(hash)
+
+	err = rm.db.Transaction(ctx, func(ctx context.Context) error {
+		if err := rm.db.UserUpdate(ctx, user); err != nil {
+			return err
+		}
+
+		if err := rm.db.RestoreClean(ctx, user.ID); err != nil {
+			return err
+		}
+
+		return rm.db.LockoutReset(ctx, user.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
 }
 
 // NewTokenForEmail генерирует новый токен.
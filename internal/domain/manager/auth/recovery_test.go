@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// recoveryStore дополняет lockoutStore восстановлением доступа.
+type recoveryStore struct {
+	*lockoutStore
+}
+
+func (s *recoveryStore) UserByEmail(ctx context.Context, email string) (*models.User, error) {
+	if email != s.user.Email {
+		return nil, drivers.ErrUserDoesNotExist
+	}
+
+	u, err := s.UserByLogin(ctx, s.user.Login)
+	if err != nil {
+		return nil, err
+	}
+	if s.user.Restore != nil {
+		r := *s.user.Restore
+		u.Restore = &r
+	}
+
+	return u, nil
+}
+
+func (s *recoveryStore) RestoreAttempt(_ context.Context, _ primitive.ObjectID, limit uint8) (*models.Restore, error) {
+	if s.user.Restore == nil || s.user.Restore.Tries >= limit {
+		return nil, drivers.ErrRestoreTriesExceeded
+	}
+	s.user.Restore.Tries++
+
+	r := *s.user.Restore
+	return &r, nil
+}
+
+func (s *recoveryStore) RestoreClean(context.Context, primitive.ObjectID) error {
+	s.user.Restore.Token = ""
+	s.user.Restore.Tries = 0
+	return nil
+}
+
+func (s *recoveryStore) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestRecoveryManager_ResetPassword(t *testing.T) {
+	hash, err := HashPassword("correct horse")
+	assert.NoError(t, err)
+
+	store := &recoveryStore{&lockoutStore{
+		user: &models.User{
+			ID:       primitive.NewObjectID(),
+			Login:    "user@example.com",
+			Email:    "user@example.com",
+			Password: string(hash),
+			Enabled:  true,
+			Lockout: &models.Lockout{
+				Locked: true,
+				Until:  time.Now().Add(time.Hour),
+			},
+		},
+		outbox: &outboxStore{},
+	}}
+	newRestore := func() {
+		store.user.Restore = &models.Restore{
+			Token:   "111111",
+			Email:   "user@example.com",
+			Expired: time.Now().In(time.UTC).Add(restoreTTL),
+		}
+	}
+	users := &Users{db: store, passwords: DefaultPasswordPolicy()}
+	rm := &RecoveryManager{db: store, users: users}
+	lm := &LoginManager{db: store, users: users, now: time.Now}
+
+	_, err = lm.SignInByLogin("user@example.com", "correct horse")
+	assert.True(t, errors.Is(err, ErrAccountLocked))
+
+	// попытки ввода кода ограничены, даже верный код после них не принимается
+	newRestore()
+	for i := 0; i < TriesLimit; i++ {
+		_, err = rm.ResetPassword(context.Background(), "user@example.com", "000000", "battery staple")
+		assert.Equal(t, ErrInvalidRecoveryCode, err)
+	}
+	_, err = rm.ResetPassword(context.Background(), "user@example.com", "111111", "battery staple")
+	assert.Equal(t, ErrRecoveryTriesExceeded, err)
+	assert.Equal(t, string(hash), store.user.Password)
+
+	// пароль не по политике отклоняется, не расходуя попытку
+	newRestore()
+	_, err = rm.ResetPassword(context.Background(), "user@example.com", "111111", "short")
+	var policyErr *PasswordPolicyError
+	assert.True(t, errors.As(err, &policyErr))
+	_, err = rm.ResetPassword(context.Background(), "user@example.com", "111111", "user@example.com")
+	assert.True(t, errors.As(err, &policyErr))
+	assert.Zero(t, store.user.Restore.Tries)
+	assert.Equal(t, string(hash), store.user.Password)
+
+	// верный код устанавливает пароль и снимает блокировку входа
+	_, err = rm.ResetPassword(context.Background(), "user@example.com", "000000", "battery staple")
+	assert.Equal(t, ErrInvalidRecoveryCode, err)
+	user, err := rm.ResetPassword(context.Background(), "user@example.com", "111111", "battery staple")
+	assert.NoError(t, err)
+	assert.Equal(t, store.user.ID, user.ID)
+	assert.Nil(t, store.user.Lockout)
+	assert.Empty(t, store.user.Restore.Token)
+
+	_, err = lm.SignInByLogin("user@example.com", "battery staple")
+	assert.NoError(t, err)
+
+	// использованный код повторно не принимается
+	_, err = rm.ResetPassword(context.Background(), "user@example.com", "111111", "battery staple")
+	assert.Equal(t, ErrInvalidRecoveryCode, err)
+}
@@ -0,0 +1,146 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/JetBrainer/sso/internal/domain/permissions"
+)
+
+// RolesManager управляет ролями и выданными им правами.
+type RolesManager struct {
+	db          drivers.DataStore
+	permissions *PermissionsManager
+}
+
+func (m *Manager) RolesManager() *RolesManager {
+	return &RolesManager{
+		db:          m.db,
+		permissions: m.permissions,
+	}
+}
+
+// Roles возвращает все роли.
+func (rm *RolesManager) Roles(ctx context.Context) ([]models.Role, error) {
+	return rm.db.Roles().Roles(ctx)
+}
+
+// ByName возвращает роль по ее имени.
+func (rm *RolesManager) ByName(ctx context.Context, name string) (*models.Role, error) {
+	role, err := rm.db.Roles().RoleByName(ctx, name)
+	if err == drivers.ErrRoleDoesNotExist {
+		return nil, ErrRoleDoesNotExist
+	}
+
+	return role, err
+}
+
+// Create создает новую роль, предварительно проверив ее права.
+func (rm *RolesManager) Create(ctx context.Context, role *models.Role) error {
+	if err := validateRolePermissions(role.Permissions); err != nil {
+		return err
+	}
+
+	_, err := rm.ByName(ctx, role.Name)
+	switch err {
+	case nil:
+		return ErrRoleAlreadyExists
+	case ErrRoleDoesNotExist:
+	default:
+		return err
+	}
+
+	if err := rm.db.Roles().Create(ctx, role); err != nil {
+		return err
+	}
+
+	rm.permissions.Invalidate(role.Name)
+
+	return nil
+}
+
+// Update заменяет права существующей роли.
+func (rm *RolesManager) Update(ctx context.Context, role *models.Role) error {
+	if err := validateRolePermissions(role.Permissions); err != nil {
+		return err
+	}
+
+	err := rm.db.Roles().Update(ctx, role)
+	if err == drivers.ErrRoleDoesNotExist {
+		return ErrRoleDoesNotExist
+	}
+	if err != nil {
+		return err
+	}
+
+	rm.permissions.Invalidate(role.Name)
+
+	return nil
+}
+
+// Delete удаляет роль. Роль, назначенную хотя бы одному пользователю,
+// удалить нельзя.
+func (rm *RolesManager) Delete(ctx context.Context, name string) error {
+	if _, err := rm.ByName(ctx, name); err != nil {
+		return err
+	}
+
+	assigned, err := rm.db.UsersCount(ctx, &models.UsersSearchFilters{Role: &name})
+	if err != nil {
+		return err
+	}
+
+	if assigned > 0 {
+		return ErrRoleInUse
+	}
+
+	if err := rm.db.Roles().DeleteByName(ctx, name); err != nil {
+		return err
+	}
+
+	rm.permissions.Invalidate(name)
+
+	return nil
+}
+
+// EnsureBuiltIn создает встроенные роли, если их еще нет.
+// Роль user уже существующей не изменяется, чтобы не затирать настройки
+// операторов, а роль admin всегда получает все известные системе права,
+// включая появившиеся в новых версиях.
+func (rm *RolesManager) EnsureBuiltIn(ctx context.Context) error {
+	builtIn := []models.Role{
+		{Name: models.RoleUser, Permissions: models.RolePermissions{}},
+		{Name: models.RoleAdmin, Permissions: permissions.Groups()},
+	}
+
+	for i := range builtIn {
+		err := rm.Create(ctx, &builtIn[i])
+		switch {
+		case err == nil:
+		case err == ErrRoleAlreadyExists && builtIn[i].Name == models.RoleAdmin:
+			if err := rm.Update(ctx, &builtIn[i]); err != nil {
+				return err
+			}
+		case err == ErrRoleAlreadyExists:
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateRolePermissions проверяет, что все права роли известны системе.
+func validateRolePermissions(rolePermissions models.RolePermissions) error {
+	for _, group := range rolePermissions {
+		for _, permission := range group {
+			if !permissions.Known(permission) {
+				return fmt.Errorf("%w: %s", ErrUnknownPermission, permission)
+			}
+		}
+	}
+
+	return nil
+}
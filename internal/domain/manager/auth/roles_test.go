@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/JetBrainer/sso/internal/domain/permissions"
+	"github.com/stretchr/testify/assert"
+)
+
+// Проверяет, что роль может содержать только известные системе права.
+func TestValidateRolePermissions(t *testing.T) {
+	// пустой набор прав допустим
+	assert.NoError(t, validateRolePermissions(models.RolePermissions{}))
+
+	// все встроенные права известны
+	assert.NoError(t, validateRolePermissions(permissions.Groups()))
+
+	// неизвестное право отклоняется с указанием его имени
+	err := validateRolePermissions(models.RolePermissions{
+		"users": {permissions.ViewUsers, "users-destroy"},
+	})
+	assert.True(t, errors.Is(err, ErrUnknownPermission))
+	assert.Contains(t, err.Error(), "users-destroy")
+}
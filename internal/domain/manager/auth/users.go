@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	"github.com/JetBrainer/sso/internal/domain/manager/webhooks"
 	"github.com/JetBrainer/sso/internal/domain/models"
 	"github.com/JetBrainer/sso/internal/domain/models/api"
 	"github.com/JetBrainer/sso/utils"
@@ -49,15 +50,23 @@ func (u *Users) Exists(login string) (bool, error) {
 	return user != nil, err
 }
 
+// Create создает пользователя и публикует событие о его регистрации.
 func (u *Users) Create(user *models.User) error {
-	var tdid primitive.ObjectID
+	err := u.db.Transaction(context.Background(), func(ctx context.Context) error {
+		tdid, err := u.db.UserCreate(ctx, user)
+		if err != nil {
+			return err
+		}
+
+		user.ID = tdid
 
-	tdid, err := u.db.UserCreate(context.Background(), user)
+		return u.emit(ctx, models.WebhookEventUserCreated, webhooks.NewUserData(user))
+	})
 	if err != nil {
 		return err
 	}
 
-	user.ID = tdid
+	u.publish(models.UserChangeCreated, user)
 
 	return nil
 }
@@ -74,16 +83,17 @@ func (u *Users) CreateFromSUR(sur *api.SignUPRequest) (*models.User, error) {
 		sur.Lang,
 	)
 
-	if newUser.Password != "" {
-		hash, err := HashPassword(newUser.Password)
-
-		if err != nil {
-			return nil, err
-		}
+	if err := u.ValidatePassword(newUser.Password, newUser); err != nil {
+		return nil, err
+	}
 
-		newUser.Password = string(hash)
+	hash, err := HashPassword(newUser.Password)
+	if err != nil {
+		return nil, err
 	}
 
+	newUser.Password = string(hash)
+
 	newUser.BirthDate = appendBirthDate(sur.BirthDate)
 
 	if sur.IIN != nil {
@@ -94,15 +104,10 @@ func (u *Users) CreateFromSUR(sur *api.SignUPRequest) (*models.User, error) {
 		newUser.Sex = *sur.Sex
 	}
 
-	var tdid primitive.ObjectID
-
-	tdid, err := u.db.UserCreate(context.Background(), newUser)
-	if err != nil {
+	if err := u.Create(newUser); err != nil {
 		return nil, err
 	}
 
-	newUser.ID = tdid
-
 	return newUser, nil
 }
 
@@ -176,11 +181,25 @@ func (u *Users) Delete(login string) error {
 		return ErrUserDoesNotExist
 	}
 
+	user, err := u.ByLogin(login)
+	if err != nil {
+		return err
+	}
+
 	// удаление пользователя
-	if err := u.db.UserDelete(context.Background(), login); err != nil {
+	err = u.db.Transaction(context.Background(), func(ctx context.Context) error {
+		if err := u.db.UserDelete(ctx, login); err != nil {
+			return err
+		}
+
+		return u.emit(ctx, models.WebhookEventUserDeleted, webhooks.NewUserData(user))
+	})
+	if err != nil {
 		return err
 	}
 
+	u.changes.Publish(models.UserChange{Type: models.UserChangeDeleted, TDID: user.ID})
+
 	return nil
 }
 
@@ -220,13 +239,85 @@ func (u *Users) Update(user *models.User) error {
 	user.Updated = time.Now().In(time.UTC)
 
 	// обновление пользователя
-	if err := u.db.UserUpdate(context.Background(), user); err != nil {
+	err = u.db.Transaction(context.Background(), func(ctx context.Context) error {
+		if err := u.db.UserUpdate(ctx, user); err != nil {
+			return err
+		}
+
+		return u.emitUpdate(ctx, oldUser, user)
+	})
+	if err != nil {
 		return err
 	}
 
+	switch {
+	case oldUser.Enabled && !user.Enabled:
+		u.publish(models.UserChangeDisabled, user)
+	case !oldUser.Enabled && user.Enabled:
+		u.publish(models.UserChangeEnabled, user)
+	default:
+		u.publish(models.UserChangeUpdated, user)
+	}
+
 	return nil
 }
 
+// emitUpdate публикует событие изменения пользователя, а также события
+// о включении, выключении и смене email, если они произошли.
+func (u *Users) emitUpdate(ctx context.Context, oldUser, user *models.User) error {
+	data := webhooks.NewUserData(user)
+
+	if oldUser.Enabled != user.Enabled {
+		event := models.WebhookEventUserDisabled
+		if user.Enabled {
+			event = models.WebhookEventUserEnabled
+		}
+
+		if err := u.emit(ctx, event, data); err != nil {
+			return err
+		}
+	}
+
+	if oldUser.Email != user.Email {
+		changed := webhooks.EmailChangedData{UserData: data, PreviousEmail: oldUser.Email}
+		if err := u.emit(ctx, models.WebhookEventEmailChanged, changed); err != nil {
+			return err
+		}
+	}
+
+	return u.emit(ctx, models.WebhookEventUserUpdated, data)
+}
+
+// emit публикует событие пользователя подписчикам webhook.
+func (u *Users) emit(ctx context.Context, event string, data interface{}) error {
+	if u.webhooks == nil {
+		return nil
+	}
+
+	return u.webhooks.Emit(ctx, event, data)
+}
+
+// publish транслирует изменение пользователя подписчикам WatchUsers внутри
+// процесса.
+func (u *Users) publish(changeType string, user *models.User) {
+	changed := *user
+	u.changes.Publish(models.UserChange{Type: changeType, TDID: user.ID, User: &changed})
+}
+
+// Watch вызывает fn для каждого изменения пользователей после позиции
+// resumeToken. Используется поток изменений хранилища, а если хранилище его
+// не поддерживает - изменения, сделанные этим процессом.
+func (u *Users) Watch(ctx context.Context, resumeToken string, fn func(change *models.UserChange) error) error {
+	if watcher, ok := u.db.(drivers.UsersWatcher); ok {
+		err := watcher.WatchUsers(ctx, resumeToken, fn)
+		if err != drivers.ErrWatchNotSupported {
+			return err
+		}
+	}
+
+	return u.changes.WatchUsers(ctx, resumeToken, fn)
+}
+
 // Enable делает пользователя активным.
 func (u *Users) Enable(login string) error {
 	exists, err := u.Exists(login)
@@ -271,6 +362,32 @@ func (u *Users) Disable(login string) error {
 	return err
 }
 
+// Unlock снимает с пользователя задержку входа и блокировку после неудачных
+// попыток входа.
+func (u *Users) Unlock(login string) error {
+	user, err := u.ByLogin(login)
+	if err != nil {
+		return ErrUserDoesNotExist
+	}
+
+	return u.db.LockoutReset(context.Background(), user.ID)
+}
+
+// CheckRolesExist проверяет существование всех перечисленных ролей.
+func (u *Users) CheckRolesExist(ctx context.Context, roles []string) error {
+	for _, name := range roles {
+		_, err := u.db.Roles().RoleByName(ctx, name)
+		if err == drivers.ErrRoleDoesNotExist {
+			return ErrRoleDoesNotExist
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // CheckPasswordByLogin сверяет пароль с хэшем.
 func (u *Users) CheckPasswordByLogin(login, password string) (bool, error) {
 	// получаем пользователя
@@ -295,6 +412,12 @@ func (u *Users) CheckPasswordByTDID(tdid primitive.ObjectID, password string) (b
 	return CheckPasswordHash(password, []byte(user.Password)), nil
 }
 
+// ValidatePassword проверяет новый пароль пользователя user по политике
+// паролей.
+func (u *Users) ValidatePassword(password string, user *models.User) error {
+	return u.passwords.Validate(password, user)
+}
+
 // UpdatePassword валидирует сложность пароля и обновляет хэш в БД.
 func (u *Users) UpdatePassword(tdid primitive.ObjectID, password string) error {
 	// получаем пользователя
@@ -304,9 +427,9 @@ func (u *Users) UpdatePassword(tdid primitive.ObjectID, password string) error {
 	}
 
 	// валидируем сложность пароля
-	/*	if err := validatePassword(password); err != nil {
+	if err := u.ValidatePassword(password, user); err != nil {
 		return err
-	}*/
+	}
 
 	var hash []byte
 
@@ -320,7 +443,17 @@ func (u *Users) UpdatePassword(tdid primitive.ObjectID, password string) error {
 
 	// сохраняем пользователя используя метод UserUpdate() именно от фабрики,
 	// так как u.Update() обновляет все, кроме пароля.
-	return u.db.UserUpdate(context.Background(), user)
+	if err := u.db.UserUpdate(context.Background(), user); err != nil {
+		return err
+	}
+
+	// новый пароль, в том числе после восстановления доступа, снимает
+	// блокировку входа после неудачных попыток
+	if user.Lockout != nil {
+		return u.db.LockoutReset(context.Background(), user.ID)
+	}
+
+	return nil
 }
 
 // ByEmail находит пользователя по его Email.
@@ -330,6 +463,22 @@ func (u *Users) ByEmail(email string) (*models.User, error) {
 	return u.db.UserByEmail(context.Background(), email)
 }
 
+// ListByTDID возвращает пользователей по списку TDID. Отсутствующие
+// пользователи пропускаются.
+func (u *Users) ListByTDID(ctx context.Context, rawTDIDList []string) ([]models.User, error) {
+	tdidList := make([]primitive.ObjectID, 0, len(rawTDIDList))
+
+	for _, id := range rawTDIDList {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, ErrInvalidTdIDList(id)
+		}
+		tdidList = append(tdidList, objectID)
+	}
+
+	return u.db.UsersByTDID(ctx, tdidList)
+}
+
 // FullNamesByTDID возвращает список Имя + Фамилия по списку TDID
 func (u *Users) FullNamesByTDID(ctx context.Context, rawTDIDList []string) (map[string]string, error) {
 	tdidList := make([]primitive.ObjectID, 0, len(rawTDIDList))
@@ -389,6 +538,8 @@ func (u *Users) AddReceiver(tdid primitive.ObjectID, receiver *models.Receiver)
 		return "", err
 	}
 
+	u.publish(models.UserChangeUpdated, user)
+
 	return receiver.ID.Hex(), nil
 }
 
@@ -412,6 +563,8 @@ func (u *Users) DeleteReceiverByID(tdid, receiverID primitive.ObjectID) error {
 		return err
 	}
 
+	u.publish(models.UserChangeUpdated, user)
+
 	return nil
 }
 
@@ -454,6 +607,8 @@ func (u *Users) UpdateReceiver(tdid primitive.ObjectID, updatedReceiver *models.
 		return err
 	}
 
+	u.publish(models.UserChangeUpdated, user)
+
 	return nil
 }
 
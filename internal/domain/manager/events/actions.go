@@ -0,0 +1,85 @@
+package events
+
+import (
+	"context"
+
+	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	errors2 "github.com/JetBrainer/sso/internal/domain/errors"
+	"github.com/JetBrainer/sso/internal/domain/models"
+
+	"github.com/pkg/errors"
+)
+
+// Actions осуществляет примитивы для работы с реестром типов действий
+// (например, "change-phone", "delete-account").
+type Actions struct {
+	db drivers.DataStore
+}
+
+// All возвращает все зарегистрированные действия.
+func (a *Actions) All(ctx context.Context) ([]models.Action, error) {
+	return a.db.Actions().All(ctx)
+}
+
+// ByID возвращает действие по его идентификатору.
+func (a *Actions) ByID(ctx context.Context, id models.PolymorphicID) (*models.Action, error) {
+	return a.db.Actions().ByID(ctx, id)
+}
+
+// ByType возвращает действие по его типу.
+func (a *Actions) ByType(ctx context.Context, actionType string) (*models.Action, error) {
+	return a.db.Actions().ByType(ctx, actionType)
+}
+
+// Registered сообщает, зарегистрирован ли тип действия actionType.
+func (a *Actions) Registered(ctx context.Context, actionType string) (bool, error) {
+	_, err := a.db.Actions().ByType(ctx, actionType)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Cause(err) == errors2.ErrDoesNotExist:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// Create регистрирует новый тип действия. Тип должен быть уникальным.
+func (a *Actions) Create(ctx context.Context, action *models.Action) error {
+	if err := a.ensureTypeIsFree(ctx, action.Type, ""); err != nil {
+		return err
+	}
+
+	return a.db.Actions().Create(ctx, action)
+}
+
+// Update изменяет название и тип действия.
+func (a *Actions) Update(ctx context.Context, action *models.Action) error {
+	if err := a.ensureTypeIsFree(ctx, action.Type, action.ID); err != nil {
+		return err
+	}
+
+	return a.db.Actions().Update(ctx, action)
+}
+
+// DeleteByID удаляет действие по его идентификатору.
+func (a *Actions) DeleteByID(ctx context.Context, id models.PolymorphicID) error {
+	return a.db.Actions().DeleteByID(ctx, id)
+}
+
+// ensureTypeIsFree проверяет, что тип действия не занят другим действием,
+// за исключением действия с идентификатором exceptID.
+func (a *Actions) ensureTypeIsFree(ctx context.Context, actionType string, exceptID models.PolymorphicID) error {
+	existing, err := a.db.Actions().ByType(ctx, actionType)
+	switch {
+	case err == nil:
+		if existing.ID != exceptID {
+			return ErrActionAlreadyExists
+		}
+		return nil
+	case errors.Cause(err) == errors2.ErrDoesNotExist:
+		return nil
+	default:
+		return err
+	}
+}
@@ -0,0 +1,11 @@
+package events
+
+import "errors"
+
+var (
+	ErrActionAlreadyExists     = errors.New("action with this type already exists")
+	ErrPhoneNotSpecified       = errors.New("user primary phone not specified")
+	ErrInvalidOTP              = errors.New("one time password is incorrect")
+	ErrTokenGenerationsExpired = errors.New("token generations expired")
+	ErrActionNotConfirmed      = errors.New("action is not confirmed")
+)
@@ -0,0 +1,269 @@
+package events
+
+import (
+	"context"
+	"crypto/subtle"
+	"strconv"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	"github.com/JetBrainer/sso/internal/domain"
+	errors2 "github.com/JetBrainer/sso/internal/domain/errors"
+	"github.com/JetBrainer/sso/internal/domain/manager/webhooks"
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/JetBrainer/sso/internal/domain/templates"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	EventTTL           = time.Minute * 15 // время жизни события, в течение которого его нужно подтвердить и использовать
+	VerifyTTL          = time.Minute * 5  // время жизни одноразового пароля
+	TriesLimit         = 3                // количество попыток ввода одноразового пароля
+	GenerationLimit    = 5                // количество генераций одноразового пароля за время жизни события
+	DefaultSpamPenalty = time.Second * 20 // срабатывает при повторных попытках отослать SMS
+)
+
+// Events осуществляет подтверждение чувствительных действий пользователя
+// одноразовым паролем (step-up подтверждение).
+//
+// Событие создается на пару пользователь/тип действия. Одноразовый пароль
+// сохраняется вместе с сообщением outbox, которое доставляет демон рассылки.
+type Events struct {
+	db          drivers.DataStore
+	isTesting   bool
+	spamPenalty time.Duration
+	webhooks    *webhooks.Manager
+}
+
+// Start начинает подтверждение действия actionType пользователем tdid и
+// ставит одноразовый пароль в очередь на отправку на основной телефон.
+// При ErrTooManyRequests возвращается и событие, чтобы по NextAttemptAt
+// можно было вычислить пенальти.
+func (e *Events) Start(ctx context.Context, tdid models.PolymorphicID, actionType string) (*models.Event, error) {
+	action, err := e.db.Actions().ByType(ctx, actionType)
+	if err != nil {
+		return nil, err
+	}
+
+	objID, err := tdid.ToObjectID()
+	if err != nil {
+		return nil, errors.WithMessage(errors2.ErrInvalidID, "cannot start event for provided tdid")
+	}
+
+	user, err := e.db.UserByTDID(ctx, objID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.PrimaryPhone == "" {
+		return nil, ErrPhoneNotSpecified
+	}
+
+	isNew := false
+	event, err := e.db.Events().ByUserAction(ctx, tdid, actionType)
+	switch {
+	case err == nil:
+	case errors.Cause(err) == errors2.ErrDoesNotExist:
+		event = models.NewEvent(tdid, actionType)
+		isNew = true
+	default:
+		return nil, err
+	}
+
+	now := time.Now().In(time.UTC)
+
+	// просроченное событие, которое еще не удалено datastore'ом, начинаем заново
+	if event.IsExpired(now) {
+		event.Verify = nil
+	}
+
+	var generation uint8 = 1
+	if event.Verify != nil {
+		if now.Before(event.Verify.NextAttemptAt) {
+			return event, errors2.ErrTooManyRequests
+		}
+
+		if event.Verify.Generation >= GenerationLimit {
+			return nil, ErrTokenGenerationsExpired
+		}
+
+		generation = event.Verify.Generation + 1
+	}
+
+	token, err := domain.NewTokenizer(e.isTesting).NewTokenForPhone()
+	if err != nil {
+		return nil, err
+	}
+
+	event.AddVerify(&models.Verify{
+		Phone:         user.PrimaryPhone,
+		Token:         token,
+		Status:        domain.TokenStatusNew,
+		Expired:       now.Add(VerifyTTL),
+		NextAttemptAt: now.Add(e.spamPenalty),
+		Generation:    generation,
+	})
+	event.AddDeadline(now.Add(EventTTL))
+
+	msg := models.NewOutboxMessage(
+		models.OutboxKey(templates.Confirm, string(tdid), actionType, strconv.FormatInt(event.Verify.Expired.UnixNano(), 10)),
+		templates.Confirm,
+		models.OutboxChannelSMS,
+		user.PrimaryPhone,
+	)
+	msg.TDID = tdid
+	msg.Language = user.Language
+	msg.Name = user.FirstName
+	msg.Code = token
+	msg.ActionType = actionType
+	msg.Action = action.Title
+
+	err = e.db.Transaction(ctx, func(ctx context.Context) error {
+		if isNew {
+			if err := e.db.Events().Create(ctx, event); err != nil {
+				return err
+			}
+		} else {
+			if err := e.db.Events().Update(ctx, event); err != nil {
+				return err
+			}
+		}
+
+		return e.db.Outbox().Enqueue(ctx, msg)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// ByUserAction возвращает текущее событие пользователя по типу действия.
+func (e *Events) ByUserAction(ctx context.Context, tdid models.PolymorphicID, actionType string) (*models.Event, error) {
+	event, err := e.db.Events().ByUserAction(ctx, tdid, actionType)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.IsExpired(time.Now().In(time.UTC)) {
+		return nil, errors.WithMessage(errors2.ErrDoesNotExist, "event has expired")
+	}
+
+	return event, nil
+}
+
+// Verify проверяет одноразовый пароль и в случае успеха помечает событие
+// подтвержденным. Каждая попытка уменьшает количество оставшихся.
+func (e *Events) Verify(ctx context.Context, tdid models.PolymorphicID, actionType, token string) (*models.Event, error) {
+	if token == "" {
+		return nil, errors2.ErrOTPNotSpec
+	}
+
+	event, err := e.db.Events().ByUserAction(ctx, tdid, actionType)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().In(time.UTC)
+	if event.IsExpired(now) {
+		return nil, errors2.ErrTokenHasExpired
+	}
+
+	if event.IsConfirmed(now) {
+		return event, nil
+	}
+
+	if event.Verify == nil || event.Verify.Token == "" {
+		return nil, errors2.ErrTokenDoesNotExist
+	}
+
+	if now.After(event.Verify.Expired) {
+		return nil, errors2.ErrTokenHasExpired
+	}
+
+	event, err = e.db.Events().VerifyAttempt(ctx, event.ID, TriesLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	// пароль мог быть перевыпущен между чтением события и попыткой
+	if event.Verify == nil || subtle.ConstantTimeCompare([]byte(event.Verify.Token), []byte(token)) != 1 {
+		return nil, ErrInvalidOTP
+	}
+
+	event.FinishVerify()
+	event.Verify.Token = ""
+	err = e.db.Transaction(ctx, func(ctx context.Context) error {
+		if err := e.db.Events().Update(ctx, event); err != nil {
+			return err
+		}
+
+		if e.webhooks == nil {
+			return nil
+		}
+
+		return e.webhooks.Emit(ctx, models.WebhookEventActionVerified, webhooks.ActionVerifiedData{
+			TDID:       string(event.TDID),
+			ActionType: event.ActionType,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// Confirmed проверяет, что действие подтверждено пользователем и
+// подтверждение еще не использовано.
+func (e *Events) Confirmed(ctx context.Context, tdid models.PolymorphicID, actionType string) error {
+	_, err := e.confirmed(ctx, tdid, actionType)
+	return err
+}
+
+// Consume проверяет, что действие подтверждено пользователем, и удаляет
+// событие, так что одно подтверждение разрешает ровно одну операцию.
+func (e *Events) Consume(ctx context.Context, tdid models.PolymorphicID, actionType string) error {
+	event, err := e.confirmed(ctx, tdid, actionType)
+	if err != nil {
+		return err
+	}
+
+	if err := e.db.Events().DeleteByID(ctx, event.ID); err != nil {
+		if errors.Cause(err) == errors2.ErrDoesNotExist {
+			// событие уже использовано параллельным запросом
+			return ErrActionNotConfirmed
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (e *Events) confirmed(ctx context.Context, tdid models.PolymorphicID, actionType string) (*models.Event, error) {
+	event, err := e.db.Events().ByUserAction(ctx, tdid, actionType)
+	switch {
+	case err == nil:
+	case errors.Cause(err) == errors2.ErrDoesNotExist:
+		return nil, ErrActionNotConfirmed
+	default:
+		return nil, err
+	}
+
+	if !event.IsConfirmed(time.Now().In(time.UTC)) {
+		return nil, ErrActionNotConfirmed
+	}
+
+	return event, nil
+}
+
+// Cancel отменяет подтверждение действия пользователем.
+func (e *Events) Cancel(ctx context.Context, tdid models.PolymorphicID, actionType string) error {
+	event, err := e.db.Events().ByUserAction(ctx, tdid, actionType)
+	if err != nil {
+		return err
+	}
+
+	return e.db.Events().DeleteByID(ctx, event.ID)
+}
@@ -0,0 +1,105 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	errors2 "github.com/JetBrainer/sso/internal/domain/errors"
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// eventsStore хранит одно событие и расходует попытки так же атомарно, как
+// MongoDB.
+type eventsStore struct {
+	drivers.DataStore
+	drivers.EventsRepository
+
+	mu    sync.Mutex
+	event models.Event
+}
+
+func (s *eventsStore) Events() drivers.EventsRepository {
+	return s
+}
+
+func (s *eventsStore) ByUserAction(context.Context, models.PolymorphicID, string) (*models.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.copy(), nil
+}
+
+func (s *eventsStore) VerifyAttempt(_ context.Context, _ models.PolymorphicID, limit uint8) (*models.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.event.Verify.Tries >= limit {
+		return nil, errors.WithMessage(errors2.ErrTokenTriesExpired, "cannot verify event")
+	}
+	s.event.Verify.Tries++
+
+	return s.copy(), nil
+}
+
+func (s *eventsStore) copy() *models.Event {
+	event := s.event
+	verify := *s.event.Verify
+	event.Verify = &verify
+
+	return &event
+}
+
+func TestEvents_VerifyParallelGuesses(t *testing.T) {
+	expiresAt := time.Now().Add(EventTTL)
+	store := &eventsStore{event: models.Event{
+		ID:         "60a7c0b4f1d3a2b1c0d9e8f7",
+		TDID:       "60a7c0b4f1d3a2b1c0d9e8f6",
+		ActionType: "delete-account",
+		ExpiresAt:  &expiresAt,
+		Verify: &models.Verify{
+			Token:   "1234",
+			Expired: time.Now().Add(VerifyTTL),
+		},
+	}}
+	events := New(store).Events()
+
+	const guesses = TriesLimit * 4
+	results := make(chan error, guesses)
+
+	var wg sync.WaitGroup
+	for i := 0; i < guesses; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := events.Verify(context.Background(), store.event.TDID, store.event.ActionType, "0000")
+			results <- err
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	invalid, exhausted := 0, 0
+	for err := range results {
+		switch errors.Cause(err) {
+		case ErrInvalidOTP:
+			invalid++
+		case errors2.ErrTokenTriesExpired:
+			exhausted++
+		default:
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// паролей проверено не больше, чем дается попыток
+	assert.Equal(t, TriesLimit, invalid)
+	assert.Equal(t, guesses-TriesLimit, exhausted)
+
+	// верный пароль после исчерпания попыток не принимается
+	_, err := events.Verify(context.Background(), store.event.TDID, store.event.ActionType, "1234")
+	assert.Equal(t, errors2.ErrTokenTriesExpired, errors.Cause(err))
+}
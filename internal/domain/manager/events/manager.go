@@ -0,0 +1,57 @@
+package events
+
+import (
+	"time"
+
+	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	"github.com/JetBrainer/sso/internal/domain/manager/webhooks"
+)
+
+// Manager управляет зарегистрированными типами действий и событиями,
+// требующими подтверждения пользователем.
+type Manager struct {
+	db          drivers.DataStore
+	isTesting   bool
+	spamPenalty time.Duration
+	webhooks    *webhooks.Manager
+}
+
+func New(db drivers.DataStore) *Manager {
+	return &Manager{
+		db:          db,
+		spamPenalty: DefaultSpamPenalty,
+	}
+}
+
+func (m *Manager) Testing() {
+	m.isTesting = true
+}
+
+// WithSpamPenalty устанавливает время, через которое пользователь может
+// запросить повторную отправку одноразового пароля.
+func (m *Manager) WithSpamPenalty(t time.Duration) *Manager {
+	m.spamPenalty = t
+	return m
+}
+
+// WithWebhooks устанавливает менеджер webhook, через который подписчикам
+// публикуются подтверждения действий.
+func (m *Manager) WithWebhooks(wm *webhooks.Manager) *Manager {
+	m.webhooks = wm
+	return m
+}
+
+// Actions создает менеджер реестра типов действий.
+func (m *Manager) Actions() *Actions {
+	return &Actions{db: m.db}
+}
+
+// Events создает менеджер событий, подтверждаемых одноразовым паролем.
+func (m *Manager) Events() *Events {
+	return &Events{
+		db:          m.db,
+		isTesting:   m.isTesting,
+		spamPenalty: m.spamPenalty,
+		webhooks:    m.webhooks,
+	}
+}
@@ -1,30 +1,229 @@
 package monitoring
 
 import (
+	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	"github.com/JetBrainer/sso/internal/adapters/notifier"
+	"github.com/JetBrainer/sso/internal/domain/models/api"
 )
 
+const (
+	notificatorPingTimeout = time.Second * 3
+
+	// SlowPingThreshold - время ответа хранилища, после которого оно
+	// считается деградировавшим.
+	SlowPingThreshold = time.Millisecond * 500
+	// StaleDaemonRuns - количество пропущенных проходов демона, после
+	// которого он считается деградировавшим.
+	StaleDaemonRuns = 3
+	// MinKeyLength - длина ключа подписи JWT, рекомендованная для HS256.
+	MinKeyLength = 32
+)
+
+// Названия компонентов в ответе проверки готовности.
+const (
+	ComponentDatastore = "datastore"
+	ComponentNotifier  = "notifier"
+	ComponentKeys      = "keys"
+)
+
+// Daemon - фоновый процесс, время последнего прохода которого проверяется
+// в Health.Ready.
+type Daemon interface {
+	// LastRun возвращает время окончания последнего прохода, нулевое до
+	// первого прохода.
+	LastRun() time.Time
+	PollInterval() time.Duration
+}
+
+// Check результат проверки одного компонента.
+type Check struct {
+	Name    string
+	Status  string // api.HealthStatusOK, api.HealthStatusWarning или api.HealthStatusCritical
+	Output  string
+	Latency time.Duration // время ответа хранилища или нотификатора
+	Age     time.Duration // время с последнего прохода демона
+}
+
+// Report результат проверки готовности. Status - худший из статусов
+// компонентов: warning означает, что сервис работает с ограничениями,
+// critical - что он не может обслуживать запросы.
+type Report struct {
+	Status    string
+	Checks    []Check
+	Notifiers []notifier.ProviderStatus
+}
+
 type Health struct {
-	db drivers.DataStore
+	db       drivers.DataStore
+	notifier notifier.Notifier
+	daemons  map[string]Daemon
+	jwtKey   []byte
+}
+
+// Ready проверяет все компоненты параллельно.
+func (h *Health) Ready(ctx context.Context) *Report {
+	checks := []func(context.Context) Check{h.checkDatastore, h.checkNotifier, h.checkKeys}
+	for name, d := range h.daemons {
+		name, d := name, d
+		checks = append(checks, func(context.Context) Check { return checkDaemon(name, d, time.Now()) })
+	}
+
+	report := &Report{
+		Status:    api.HealthStatusOK,
+		Checks:    make([]Check, len(checks)),
+		Notifiers: h.NotifierStatus(),
+	}
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check func(context.Context) Check) {
+			defer wg.Done()
+			report.Checks[i] = check(ctx)
+		}(i, check)
+	}
+	wg.Wait()
+
+	for _, c := range report.Checks {
+		report.Status = worst(report.Status, c.Status)
+	}
+
+	return report
+}
+
+// Check возвращает ошибку, если сервис не может обслуживать запросы.
+// Деградация компонентов ошибкой не считается.
+func (h *Health) Check() error {
+	report := h.Ready(context.Background())
+	if report.Status != api.HealthStatusCritical {
+		return nil
+	}
+
+	for _, c := range report.Checks {
+		if c.Status == api.HealthStatusCritical {
+			return fmt.Errorf("%s: %s", c.Name, c.Output)
+		}
+	}
+
+	return nil
 }
 
 // CheckDatabase проверка работоспособности БД
 func (h *Health) CheckDatabase() error {
 	if h.db == nil {
-		return fmt.Errorf("[ERROR] Database instance not created")
+		return fmt.Errorf("database instance not created")
 	}
 
 	return h.db.Ping()
 }
 
 // CheckNotificator проверка работоспособности нотификатора
-func (h *Health) CheckNotificator() error {
-	return nil
+func (h *Health) CheckNotificator(ctx context.Context) error {
+	if h.notifier == nil {
+		return fmt.Errorf("notifier instance not created")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, notificatorPingTimeout)
+	defer cancel()
+
+	return h.notifier.Ping(ctx)
 }
 
-// CheckRestoreDirector проверка работоспособности директора
-func (h *Health) CheckRestoreDirector() error {
-	return nil
+// NotifierStatus возвращает состояние провайдеров уведомлений, если
+// нотификатор его сообщает.
+func (h *Health) NotifierStatus() []notifier.ProviderStatus {
+	sr, ok := h.notifier.(notifier.StatusReporter)
+	if !ok {
+		return nil
+	}
+
+	return sr.ProvidersStatus()
+}
+
+func (h *Health) checkDatastore(context.Context) Check {
+	c := Check{Name: ComponentDatastore, Status: api.HealthStatusOK}
+
+	start := time.Now()
+	err := h.CheckDatabase()
+	c.Latency = time.Since(start)
+
+	switch {
+	case err != nil:
+		c.Status, c.Output = api.HealthStatusCritical, err.Error()
+	case c.Latency > SlowPingThreshold:
+		c.Status, c.Output = api.HealthStatusWarning, fmt.Sprintf("slow ping, more than %s", SlowPingThreshold)
+	}
+
+	return c
+}
+
+// checkNotifier считает недоступность уведомлений деградацией: сообщения
+// остаются в outbox и будут отправлены позже.
+func (h *Health) checkNotifier(ctx context.Context) Check {
+	c := Check{Name: ComponentNotifier, Status: api.HealthStatusOK}
+
+	start := time.Now()
+	err := h.CheckNotificator(ctx)
+	c.Latency = time.Since(start)
+
+	if err != nil {
+		c.Status, c.Output = api.HealthStatusWarning, err.Error()
+		return c
+	}
+
+	for _, s := range h.NotifierStatus() {
+		if s.State != notifier.BreakerClosed {
+			c.Status, c.Output = api.HealthStatusWarning, fmt.Sprintf("provider %s is %s", s.Name, s.State)
+			break
+		}
+	}
+
+	return c
+}
+
+func (h *Health) checkKeys(context.Context) Check {
+	c := Check{Name: ComponentKeys, Status: api.HealthStatusOK}
+
+	switch {
+	case len(h.jwtKey) == 0:
+		c.Status, c.Output = api.HealthStatusCritical, "JWT signing key is not loaded"
+	case len(h.jwtKey) < MinKeyLength:
+		c.Status, c.Output = api.HealthStatusWarning, fmt.Sprintf("JWT signing key is shorter than %d bytes", MinKeyLength)
+	}
+
+	return c
+}
+
+// checkDaemon считает демон деградировавшим, если он пропустил
+// StaleDaemonRuns проходов или еще не завершил ни одного.
+func checkDaemon(name string, d Daemon, now time.Time) Check {
+	c := Check{Name: name, Status: api.HealthStatusOK}
+
+	lastRun := d.LastRun()
+	if lastRun.IsZero() {
+		c.Status, c.Output = api.HealthStatusWarning, "has not completed a run yet"
+		return c
+	}
+
+	c.Age = now.Sub(lastRun)
+	if stale := StaleDaemonRuns * d.PollInterval(); c.Age > stale {
+		c.Status, c.Output = api.HealthStatusWarning, fmt.Sprintf("last run more than %s ago", stale)
+	}
+
+	return c
+}
+
+// worst возвращает более тяжелый из двух статусов.
+func worst(a, b string) string {
+	rank := map[string]int{api.HealthStatusOK: 0, api.HealthStatusWarning: 1, api.HealthStatusCritical: 2}
+	if rank[b] > rank[a] {
+		return b
+	}
+
+	return a
 }
@@ -0,0 +1,92 @@
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	"github.com/JetBrainer/sso/internal/adapters/notifier"
+	"github.com/JetBrainer/sso/internal/domain/models/api"
+)
+
+type pingStore struct {
+	drivers.DataStore
+	err error
+}
+
+func (s pingStore) Ping() error { return s.err }
+
+type daemon struct {
+	lastRun time.Time
+}
+
+func (d daemon) LastRun() time.Time          { return d.lastRun }
+func (d daemon) PollInterval() time.Duration { return time.Second }
+
+func TestHealthReady(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	failing := notifier.NewFake()
+	failing.FailWith(errors.New("gateway is down"))
+
+	tests := []struct {
+		name    string
+		manager *Manager
+		status  string
+		failed  map[string]string
+	}{
+		{
+			name:    "passing",
+			manager: New(pingStore{}, nil).WithNotifier(notifier.NewFake()).WithJWTKey(key).WithDaemon("eventsd", daemon{time.Now()}),
+			status:  api.HealthStatusOK,
+		},
+		{
+			name: "degraded",
+			manager: New(pingStore{}, nil).WithNotifier(failing).WithJWTKey(key[:8]).
+				WithDaemon("eventsd", daemon{time.Now().Add(-time.Minute)}),
+			status: api.HealthStatusWarning,
+			failed: map[string]string{
+				ComponentNotifier: api.HealthStatusWarning,
+				ComponentKeys:     api.HealthStatusWarning,
+				"eventsd":         api.HealthStatusWarning,
+			},
+		},
+		{
+			name:    "failed",
+			manager: New(pingStore{err: errors.New("no reachable servers")}, nil).WithNotifier(notifier.NewFake()).WithDaemon("eventsd", daemon{}),
+			status:  api.HealthStatusCritical,
+			failed: map[string]string{
+				ComponentDatastore: api.HealthStatusCritical,
+				ComponentKeys:      api.HealthStatusCritical,
+				"eventsd":          api.HealthStatusWarning,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			health := tt.manager.Health()
+			report := health.Ready(context.Background())
+
+			if report.Status != tt.status {
+				t.Errorf("status %s, want %s", report.Status, tt.status)
+			}
+			if len(report.Checks) != 4 {
+				t.Fatalf("got %d checks, want 4", len(report.Checks))
+			}
+			for _, c := range report.Checks {
+				want, ok := tt.failed[c.Name]
+				if !ok {
+					want = api.HealthStatusOK
+				}
+				if c.Status != want {
+					t.Errorf("%s status %s (%s), want %s", c.Name, c.Status, c.Output, want)
+				}
+			}
+			if err := health.Check(); (err != nil) != (tt.status == api.HealthStatusCritical) {
+				t.Errorf("check error %v with status %s", err, tt.status)
+			}
+		})
+	}
+}
@@ -1,24 +1,71 @@
 package monitoring
 
 import (
+	"os"
+
 	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	"github.com/JetBrainer/sso/internal/adapters/notifier"
 	"github.com/JetBrainer/sso/internal/domain/models"
 )
 
 type Manager struct {
-	db      drivers.DataStore
-	metrics *models.Metrics
+	db       drivers.DataStore
+	notifier notifier.Notifier
+	metrics  *models.Metrics
+	daemons  map[string]Daemon
+	jwtKey   []byte
+	node     string
 }
 
 func New(db drivers.DataStore, metrics *models.Metrics) *Manager {
+	node, err := os.Hostname()
+	if err != nil {
+		node = "unknown"
+	}
+
 	return &Manager{
 		db:      db,
 		metrics: metrics,
+		daemons: make(map[string]Daemon),
+		node:    node,
+	}
+}
+
+// WithNotifier устанавливает провайдер уведомлений, работоспособность
+// которого проверяется в Health.CheckNotificator.
+func (m *Manager) WithNotifier(n notifier.Notifier) *Manager {
+	m.notifier = n
+	return m
+}
+
+// WithDaemon добавляет в проверку готовности время последнего прохода демона.
+func (m *Manager) WithDaemon(name string, d Daemon) *Manager {
+	m.daemons[name] = d
+	return m
+}
+
+// WithJWTKey устанавливает ключ подписи токенов, наличие которого
+// проверяется в проверке готовности.
+func (m *Manager) WithJWTKey(key []byte) *Manager {
+	m.jwtKey = key
+	return m
+}
+
+// WithNode устанавливает имя узла в ответах проверок вместо имени хоста.
+func (m *Manager) WithNode(node string) *Manager {
+	if node != "" {
+		m.node = node
 	}
+	return m
+}
+
+// Node возвращает имя узла, на котором запущен сервис.
+func (m *Manager) Node() string {
+	return m.node
 }
 
 func (m *Manager) Health() *Health {
-	return &Health{db: m.db}
+	return &Health{db: m.db, notifier: m.notifier, daemons: m.daemons, jwtKey: m.jwtKey}
 }
 
 func (m *Manager) Metrics() *models.Metrics {
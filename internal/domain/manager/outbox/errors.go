@@ -0,0 +1,5 @@
+package outbox
+
+import "errors"
+
+var ErrMessageNotDead = errors.New("only dead outbox messages can be replayed")
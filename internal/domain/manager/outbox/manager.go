@@ -0,0 +1,58 @@
+package outbox
+
+import (
+	"context"
+
+	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	"github.com/JetBrainer/sso/internal/domain/models"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 500
+)
+
+// Manager предоставляет просмотр outbox и повторную отправку сообщений,
+// попавших в dead-letter.
+type Manager struct {
+	db drivers.DataStore
+}
+
+func New(db drivers.DataStore) *Manager {
+	return &Manager{db: db}
+}
+
+// List возвращает сообщения outbox, начиная с самых новых.
+func (m *Manager) List(ctx context.Context, filters *models.OutboxFilters) ([]models.OutboxMessage, error) {
+	if filters.Limit <= 0 {
+		filters.Limit = DefaultLimit
+	}
+	if filters.Limit > MaxLimit {
+		filters.Limit = MaxLimit
+	}
+
+	return m.db.Outbox().List(ctx, filters)
+}
+
+// ByID возвращает сообщение outbox по его идентификатору.
+func (m *Manager) ByID(ctx context.Context, id models.PolymorphicID) (*models.OutboxMessage, error) {
+	return m.db.Outbox().ByID(ctx, id)
+}
+
+// Replay возвращает сообщение из dead-letter в очередь на отправку.
+func (m *Manager) Replay(ctx context.Context, id models.PolymorphicID) (*models.OutboxMessage, error) {
+	msg, err := m.db.Outbox().ByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if msg.Status != models.OutboxStatusDead {
+		return nil, ErrMessageNotDead
+	}
+
+	if err := m.db.Outbox().Replay(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return m.db.Outbox().ByID(ctx, id)
+}
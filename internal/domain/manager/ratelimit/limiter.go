@@ -0,0 +1,124 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/JetBrainer/sso/pkg/logger"
+)
+
+// Хранилища корзин.
+const (
+	BackendMemory    = "memory"
+	BackendDatastore = "datastore"
+)
+
+var (
+	ErrRateLimited    = errors.New("rate limit exceeded, try again later")
+	ErrInvalidLimit   = errors.New("rate limit must be in format <requests>/<period>, e.g. 10/1m")
+	ErrUnknownBackend = errors.New("unknown rate limit backend")
+)
+
+// Policy ограничения для одного маршрута: общее, по IP адресу клиента и по
+// логину (email или телефону). Нулевое ограничение не проверяется.
+type Policy struct {
+	Route models.RateLimit
+	IP    models.RateLimit
+	Login models.RateLimit
+}
+
+// Limiter ограничивает частоту запросов к маршрутам. Нулевой указатель
+// допустим и ничего не ограничивает.
+type Limiter struct {
+	store  Store
+	policy Policy
+	now    func() time.Time
+}
+
+func New(store Store, policy Policy) *Limiter {
+	return &Limiter{
+		store:  store,
+		policy: policy,
+		now:    time.Now,
+	}
+}
+
+// Allow забирает токены из корзин маршрута route, адреса ip и логина login.
+// Возвращает время, через которое запрос можно повторить, если хотя бы одна
+// корзина пуста. Ошибки хранилища журналируются, а запрос пропускается,
+// чтобы сбой хранилища не закрывал вход в систему.
+func (l *Limiter) Allow(ctx context.Context, route, ip, login string) time.Duration {
+	if l == nil {
+		return 0
+	}
+
+	now := l.now()
+	buckets := []struct {
+		kind, value string
+		limit       models.RateLimit
+	}{
+		{"route", "", l.policy.Route},
+		{"ip", ip, l.policy.IP},
+		{"login", strings.ToLower(login), l.policy.Login},
+	}
+
+	var retryAfter time.Duration
+	for _, b := range buckets {
+		if !b.limit.Enabled() || (b.kind != "route" && b.value == "") {
+			continue
+		}
+
+		wait, err := l.store.Take(ctx, b.kind+":"+route+":"+b.value, b.limit, now)
+		if err != nil {
+			logger.Printf(ctx, "[WARN] %s rate limit for %s is not checked: %v", b.kind, route, err)
+			continue
+		}
+		if wait > retryAfter {
+			retryAfter = wait
+		}
+	}
+
+	return retryAfter
+}
+
+// ParseLimit разбирает ограничение вида "10/1m": 10 запросов за минуту.
+// Пустая строка и "0" отключают ограничение.
+func ParseLimit(s string) (models.RateLimit, error) {
+	if s == "" || s == "0" {
+		return models.RateLimit{}, nil
+	}
+
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) != 2 {
+		return models.RateLimit{}, ErrInvalidLimit
+	}
+
+	burst, err := strconv.Atoi(parts[0])
+	if err != nil || burst < 0 {
+		return models.RateLimit{}, ErrInvalidLimit
+	}
+
+	period, err := time.ParseDuration(parts[1])
+	if err != nil || period <= 0 {
+		return models.RateLimit{}, ErrInvalidLimit
+	}
+
+	return models.RateLimit{Burst: burst, Period: period}, nil
+}
+
+// NewStore создает хранилище корзин по имени backend.
+func NewStore(backend string, db drivers.DataStore) (Store, error) {
+	switch backend {
+	case BackendMemory, "":
+		return NewMemory(), nil
+	case BackendDatastore:
+		return NewDatastore(db), nil
+	default:
+		return nil, ErrUnknownBackend
+	}
+}
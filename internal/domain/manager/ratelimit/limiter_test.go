@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryTake(t *testing.T) {
+	m := NewMemory()
+	limit := models.RateLimit{Burst: 2, Period: time.Minute}
+	now := time.Now()
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		wait, _ := m.Take(ctx, "k", limit, now)
+		assert.Zero(t, wait)
+	}
+
+	wait, _ := m.Take(ctx, "k", limit, now)
+	assert.Equal(t, 30*time.Second, wait)
+
+	wait, _ = m.Take(ctx, "k", limit, now.Add(15*time.Second))
+	assert.Equal(t, 15*time.Second, wait)
+
+	wait, _ = m.Take(ctx, "k", limit, now.Add(30*time.Second))
+	assert.Zero(t, wait)
+
+	wait, _ = m.Take(ctx, "other", limit, now)
+	assert.Zero(t, wait)
+}
+
+func TestLimiterAllow(t *testing.T) {
+	now := time.Now()
+	l := New(NewMemory(), Policy{
+		IP:    models.RateLimit{Burst: 3, Period: time.Minute},
+		Login: models.RateLimit{Burst: 1, Period: time.Minute},
+	})
+	l.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	assert.Zero(t, l.Allow(ctx, "sign_in", "10.0.0.1", "User@example.com"))
+	assert.Equal(t, time.Minute, l.Allow(ctx, "sign_in", "10.0.0.2", "user@example.com"), "login bucket is shared between IPs")
+	assert.Zero(t, l.Allow(ctx, "sign_up", "10.0.0.1", "user@example.com"), "buckets are per route")
+	assert.Zero(t, l.Allow(ctx, "sign_in", "10.0.0.1", ""))
+	assert.Zero(t, l.Allow(ctx, "sign_in", "10.0.0.1", ""))
+	assert.Equal(t, 20*time.Second, l.Allow(ctx, "sign_in", "10.0.0.1", ""))
+
+	var nilLimiter *Limiter
+	assert.Zero(t, nilLimiter.Allow(ctx, "sign_in", "10.0.0.1", ""))
+}
+
+func TestParseLimit(t *testing.T) {
+	limit, err := ParseLimit("10/1m")
+	assert.NoError(t, err)
+	assert.Equal(t, models.RateLimit{Burst: 10, Period: time.Minute}, limit)
+
+	limit, err = ParseLimit("")
+	assert.NoError(t, err)
+	assert.False(t, limit.Enabled())
+
+	for _, s := range []string{"10", "x/1m", "10/0s", "10/minute", "-1/1m"} {
+		_, err := ParseLimit(s)
+		assert.Equal(t, ErrInvalidLimit, err, s)
+	}
+}
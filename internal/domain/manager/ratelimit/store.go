@@ -0,0 +1,92 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	"github.com/JetBrainer/sso/internal/domain/models"
+)
+
+// Store хранит корзины токенов.
+type Store interface {
+	// Take забирает токен из корзины key. Если корзина пуста, возвращает
+	// время до появления следующего токена.
+	Take(ctx context.Context, key string, limit models.RateLimit, now time.Time) (time.Duration, error)
+}
+
+// sweepEvery - количество обращений, после которого из памяти удаляются
+// заполнившиеся корзины.
+const sweepEvery = 1024
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	period  time.Duration
+}
+
+// Memory хранит корзины в памяти процесса. Каждый экземпляр сервиса
+// считает запросы отдельно.
+type Memory struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	calls   int
+}
+
+func NewMemory() *Memory {
+	return &Memory{buckets: make(map[string]*bucket)}
+}
+
+func (m *Memory) Take(_ context.Context, key string, limit models.RateLimit, now time.Time) (time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls++
+	if m.calls%sweepEvery == 0 {
+		m.sweep(now)
+	}
+
+	burst := float64(limit.Burst)
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst, updated: now}
+		m.buckets[key] = b
+	}
+	b.period = limit.Period
+
+	if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens = math.Min(burst, b.tokens+burst*float64(elapsed)/float64(limit.Period))
+		b.updated = now
+	}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, nil
+	}
+
+	return time.Duration((1 - b.tokens) * float64(limit.Interval())), nil
+}
+
+// sweep удаляет корзины, которые успели заполниться.
+func (m *Memory) sweep(now time.Time) {
+	for key, b := range m.buckets {
+		if now.Sub(b.updated) >= b.period {
+			delete(m.buckets, key)
+		}
+	}
+}
+
+// Datastore хранит корзины в хранилище, общем для всех экземпляров сервиса.
+type Datastore struct {
+	db drivers.DataStore
+}
+
+func NewDatastore(db drivers.DataStore) *Datastore {
+	return &Datastore{db: db}
+}
+
+func (d *Datastore) Take(ctx context.Context, key string, limit models.RateLimit, now time.Time) (time.Duration, error) {
+	return d.db.RateLimits().Take(ctx, key, limit, now)
+}
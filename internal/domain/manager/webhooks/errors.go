@@ -0,0 +1,11 @@
+package webhooks
+
+import "errors"
+
+var (
+	ErrInvalidURL       = errors.New("webhook URL must be an absolute http or https URL")
+	ErrNoEvents         = errors.New("webhook must be subscribed to at least one event")
+	ErrUnknownEvent     = errors.New("unknown webhook event")
+	ErrDeliveryNotDead  = errors.New("only dead webhook deliveries can be redelivered")
+	ErrWebhookNotActive = errors.New("webhook is deleted or disabled")
+)
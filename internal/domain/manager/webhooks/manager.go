@@ -0,0 +1,201 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 500
+)
+
+// Manager управляет подписками внешних систем на события пользователей и
+// записывает отправки событий подписчикам. Отправку выполняет eventsd.
+type Manager struct {
+	db drivers.DataStore
+}
+
+func New(db drivers.DataStore) *Manager {
+	return &Manager{db: db}
+}
+
+// Emit записывает отправку события всем подписанным webhook. Запись
+// выполняется в контексте вызывающего, поэтому внутри транзакции событие
+// сохраняется вместе с изменением, которое его порождает.
+func (m *Manager) Emit(ctx context.Context, event string, data interface{}) error {
+	webhooks, err := m.db.Webhooks().Subscribed(ctx, event)
+	if err != nil {
+		return err
+	}
+
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	eventID := primitive.NewObjectID().Hex()
+	payload, err := json.Marshal(models.WebhookEvent{
+		ID:      eventID,
+		Type:    event,
+		Created: time.Now().In(time.UTC),
+		Data:    data,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, webhook := range webhooks {
+		delivery := models.NewWebhookDelivery(webhook.ID, eventID, event, string(payload))
+		if err := m.db.WebhookDeliveries().Enqueue(ctx, delivery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Create регистрирует webhook. Если ключ подписи не задан, он генерируется.
+func (m *Manager) Create(ctx context.Context, webhook *models.Webhook) error {
+	if err := validate(webhook); err != nil {
+		return err
+	}
+
+	if webhook.Secret == "" {
+		secret, err := newSecret()
+		if err != nil {
+			return err
+		}
+		webhook.Secret = secret
+	}
+
+	now := time.Now().In(time.UTC)
+	webhook.Created = now
+	webhook.Updated = now
+
+	return m.db.Webhooks().Create(ctx, webhook)
+}
+
+func (m *Manager) ByID(ctx context.Context, id models.PolymorphicID) (*models.Webhook, error) {
+	return m.db.Webhooks().ByID(ctx, id)
+}
+
+func (m *Manager) All(ctx context.Context) ([]models.Webhook, error) {
+	return m.db.Webhooks().All(ctx)
+}
+
+// Update изменяет адрес, подписки и состояние webhook. Ключ подписи
+// сохраняется прежним.
+func (m *Manager) Update(ctx context.Context, webhook *models.Webhook) error {
+	if err := validate(webhook); err != nil {
+		return err
+	}
+
+	old, err := m.db.Webhooks().ByID(ctx, webhook.ID)
+	if err != nil {
+		return err
+	}
+
+	webhook.Secret = old.Secret
+	webhook.Created = old.Created
+	webhook.Updated = time.Now().In(time.UTC)
+
+	return m.db.Webhooks().Update(ctx, webhook)
+}
+
+// RotateSecret заменяет ключ подписи webhook новым.
+func (m *Manager) RotateSecret(ctx context.Context, id models.PolymorphicID) (*models.Webhook, error) {
+	webhook, err := m.db.Webhooks().ByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if webhook.Secret, err = newSecret(); err != nil {
+		return nil, err
+	}
+	webhook.Updated = time.Now().In(time.UTC)
+
+	if err := m.db.Webhooks().Update(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+// Delete удаляет webhook. Неотправленные события ему больше не доставляются.
+func (m *Manager) Delete(ctx context.Context, id models.PolymorphicID) error {
+	return m.db.Webhooks().DeleteByID(ctx, id)
+}
+
+// Deliveries возвращает журнал отправок, начиная с самых новых.
+func (m *Manager) Deliveries(ctx context.Context, filters *models.WebhookDeliveryFilters) ([]models.WebhookDelivery, error) {
+	if filters.Limit <= 0 {
+		filters.Limit = DefaultLimit
+	}
+	if filters.Limit > MaxLimit {
+		filters.Limit = MaxLimit
+	}
+
+	return m.db.WebhookDeliveries().List(ctx, filters)
+}
+
+func (m *Manager) Delivery(ctx context.Context, id models.PolymorphicID) (*models.WebhookDelivery, error) {
+	return m.db.WebhookDeliveries().ByID(ctx, id)
+}
+
+// Redeliver возвращает прекращенную отправку в очередь.
+func (m *Manager) Redeliver(ctx context.Context, id models.PolymorphicID) (*models.WebhookDelivery, error) {
+	delivery, err := m.db.WebhookDeliveries().ByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if delivery.Status != models.WebhookDeliveryDead {
+		return nil, ErrDeliveryNotDead
+	}
+
+	if err := m.db.WebhookDeliveries().Replay(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return m.db.WebhookDeliveries().ByID(ctx, id)
+}
+
+// validate проверяет адрес и подписки webhook.
+func validate(webhook *models.Webhook) error {
+	u, err := url.Parse(webhook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidURL
+	}
+
+	if len(webhook.Events) == 0 {
+		return ErrNoEvents
+	}
+
+	for _, event := range webhook.Events {
+		if !knownEvent(event) {
+			return ErrUnknownEvent
+		}
+	}
+
+	return nil
+}
+
+func knownEvent(event string) bool {
+	if event == models.WebhookEventAll {
+		return true
+	}
+
+	for _, e := range models.WebhookEvents() {
+		if e == event {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,57 @@
+package webhooks
+
+import (
+	"time"
+
+	"github.com/JetBrainer/sso/internal/domain/models"
+)
+
+// UserData данные пользователя, передаваемые подписчикам. Пароль, токены и
+// прочие секреты в событие не попадают.
+type UserData struct {
+	TDID       string     `json:"tdid"`
+	Login      string     `json:"login"`
+	Phone      string     `json:"phone"`
+	Phones     []string   `json:"phones"`
+	Email      string     `json:"email"`
+	FirstName  string     `json:"firstname,omitempty"`
+	LastName   string     `json:"lastname,omitempty"`
+	Patronymic string     `json:"patronymic,omitempty"`
+	Language   string     `json:"lang,omitempty"`
+	BirthDate  *time.Time `json:"birthDate,omitempty"`
+	Roles      []string   `json:"roles"`
+	Enabled    bool       `json:"enabled"`
+	Created    time.Time  `json:"created"`
+	Updated    time.Time  `json:"updated"`
+}
+
+// EmailChangedData данные события models.WebhookEventEmailChanged.
+type EmailChangedData struct {
+	UserData
+	PreviousEmail string `json:"previousEmail"`
+}
+
+// ActionVerifiedData данные события models.WebhookEventActionVerified.
+type ActionVerifiedData struct {
+	TDID       string `json:"tdid"`
+	ActionType string `json:"actionType"`
+}
+
+func NewUserData(u *models.User) UserData {
+	return UserData{
+		TDID:       u.ID.Hex(),
+		Login:      u.Login,
+		Phone:      u.PrimaryPhone,
+		Phones:     u.Phones,
+		Email:      u.Email,
+		FirstName:  u.FirstName,
+		LastName:   u.LastName,
+		Patronymic: u.Patronymic,
+		Language:   u.Language,
+		BirthDate:  u.BirthDate,
+		Roles:      u.Roles,
+		Enabled:    u.Enabled,
+		Created:    u.Created,
+		Updated:    u.Updated,
+	}
+}
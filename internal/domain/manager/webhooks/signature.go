@@ -0,0 +1,49 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// Заголовки запроса, отправляемого подписчику.
+const (
+	HeaderEvent     = "X-SSO-Event"
+	HeaderEventID   = "X-SSO-Event-Id"
+	HeaderDelivery  = "X-SSO-Delivery"
+	HeaderTimestamp = "X-SSO-Timestamp"
+	HeaderSignature = "X-SSO-Signature"
+
+	signatureScheme = "sha256="
+	secretLen       = 32
+)
+
+// Sign подписывает тело запроса ключом webhook. Подпись вычисляется как
+// HMAC-SHA256 от строки "<timestamp>.<body>", так что получатель может
+// отбросить запросы с устаревшим временем и повторно отправленные чужие
+// запросы.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return signatureScheme + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify проверяет подпись, полученную в заголовке HeaderSignature.
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// newSecret генерирует ключ подписи webhook.
+func newSecret() (string, error) {
+	b := make([]byte, secretLen)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,46 @@
+package webhooks
+
+import (
+	"testing"
+
+	"github.com/JetBrainer/sso/internal/domain/models"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"id":"1","type":"user.created"}`)
+	signature := Sign("secret", 1700000000, body)
+
+	if !Verify("secret", 1700000000, body, signature) {
+		t.Fatalf("signature must be valid")
+	}
+	if Verify("other", 1700000000, body, signature) {
+		t.Errorf("signature must depend on secret")
+	}
+	if Verify("secret", 1700000001, body, signature) {
+		t.Errorf("signature must depend on timestamp")
+	}
+	if Verify("secret", 1700000000, []byte(`{}`), signature) {
+		t.Errorf("signature must depend on body")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		webhook models.Webhook
+		want    error
+	}{
+		{"valid", models.Webhook{URL: "https://crm.example.com/hooks", Events: []string{models.WebhookEventUserCreated}}, nil},
+		{"all events", models.Webhook{URL: "http://localhost:8080", Events: []string{models.WebhookEventAll}}, nil},
+		{"relative url", models.Webhook{URL: "/hooks", Events: []string{models.WebhookEventUserCreated}}, ErrInvalidURL},
+		{"unsupported scheme", models.Webhook{URL: "ftp://example.com", Events: []string{models.WebhookEventUserCreated}}, ErrInvalidURL},
+		{"no events", models.Webhook{URL: "https://example.com"}, ErrNoEvents},
+		{"unknown event", models.Webhook{URL: "https://example.com", Events: []string{"user.signed_in"}}, ErrUnknownEvent},
+	}
+
+	for _, tt := range tests {
+		if err := validate(&tt.webhook); err != tt.want {
+			t.Errorf("%s: validate() = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
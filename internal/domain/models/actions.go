@@ -1,6 +1,13 @@
 package models
 
 
+// Типы действий, для которых сервис требует подтверждения одноразовым
+// паролем, если оператор зарегистрировал их в реестре действий.
+const (
+	ActionChangePassword = "change-password"
+	ActionUpdateProfile  = "update-profile"
+)
+
 type Action struct {
 	ID    PolymorphicID `bson:"_id" json:"id"`
 	Title string               `bson:"title" json:"title" validate:"required"`
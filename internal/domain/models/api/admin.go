@@ -0,0 +1,50 @@
+package api
+
+import "github.com/JetBrainer/sso/internal/domain/models"
+
+// AdminUserUpdateRequest содержит изменения профиля и ролей пользователя,
+// вносимые администратором.
+type AdminUserUpdateRequest struct {
+	ProfileUpdateRequest
+	Roles *[]string `json:"roles,omitempty" validate:"omitempty,min=1"`
+}
+
+// AdminUserResponse содержит сведения о пользователе для оператора вместе
+// с учетом неудачных попыток входа, который не показывается самому
+// пользователю и клиентам.
+type AdminUserResponse struct {
+	*models.UserShortInfo
+	Lockout *models.Lockout `json:"lockout,omitempty"`
+}
+
+// RoleCreateRequest содержит данные новой роли.
+type RoleCreateRequest struct {
+	Name        string                 `json:"name" validate:"required"`
+	Permissions models.RolePermissions `json:"permissions"`
+}
+
+// RoleUpdateRequest содержит новый набор прав роли.
+type RoleUpdateRequest struct {
+	Permissions models.RolePermissions `json:"permissions" validate:"required"`
+}
+
+// ActionRequest содержит данные регистрируемого или изменяемого действия.
+type ActionRequest struct {
+	Title string `json:"title" validate:"required"`
+	Type  string `json:"type" validate:"required,max=64"`
+}
+
+// WebhookRequest содержит данные регистрируемого или изменяемого webhook.
+type WebhookRequest struct {
+	URL         string   `json:"url" validate:"required,url"`
+	Events      []string `json:"events" validate:"required,min=1"`
+	Description string   `json:"description"`
+	Enabled     *bool    `json:"enabled"` // по умолчанию webhook включен
+}
+
+// WebhookSecretResponse содержит webhook вместе с ключом подписи. Ключ
+// возвращается только при создании webhook и его замене.
+type WebhookSecretResponse struct {
+	models.Webhook
+	Secret string `json:"secret"`
+}
@@ -3,7 +3,7 @@ package api
 type SignUPRequest struct {
 	Email      string  `json:"email" validate:"required,email,unique_email"`
 	Phone      string  `json:"phone" validate:"required,is_phone,unique_phones,unique_phone"`
-	Password   string  `json:"password" validate:"required,gte=6"`
+	Password   string  `json:"password" validate:"required"`
 	FirstName  string  `json:"firstname,omitempty"`
 	LastName   string  `json:"lastname,omitempty"`
 	Patronymic string  `json:"patronymic,omitempty"`
@@ -78,6 +78,19 @@ type SignInByLoginRequest struct {
 	Login    string `json:"login"`
 }
 
+// RecoveryByEmailRequest запрос кода восстановления доступа на email.
+type RecoveryByEmailRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ResetPasswordRequest новый пароль и код восстановления, отправленный на
+// email.
+type ResetPasswordRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Code     string `json:"code" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
 type SignInFastRequest struct {
 	Phone string `json:"phone" validate:"required,is_phone"`
 	Email string `json:"email" validate:"required,email"`
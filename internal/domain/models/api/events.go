@@ -0,0 +1,20 @@
+package api
+
+import "time"
+
+// EventVerifyRequest содержит одноразовый пароль для подтверждения действия.
+type EventVerifyRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// EventResponse описывает состояние подтверждения действия пользователем.
+// Одноразовый пароль наружу не отдается.
+type EventResponse struct {
+	ID            string     `json:"id"`
+	ActionType    string     `json:"actionType"`
+	Status        string     `json:"status"`
+	Phone         string     `json:"phone,omitempty"` // замаскированный номер, на который отправлен пароль
+	TriesLeft     int        `json:"triesLeft"`
+	NextAttemptAt *time.Time `json:"nextAttemptAt,omitempty"`
+	ExpiresAt     *time.Time `json:"expiresAt,omitempty"`
+}
@@ -6,8 +6,12 @@ import (
 	"github.com/go-chi/render"
 )
 
-const HealthStatusOK = "passing"
-const HealthStatusNotOK = "not_passing"
+// Статусы проверок в терминах Consul.
+const (
+	HealthStatusOK       = "passing"
+	HealthStatusWarning  = "warning"  // сервис работает с ограничениями
+	HealthStatusCritical = "critical" // сервис не может обслуживать запросы
+)
 
 // Health - ответ на запрос о здоровье.
 type HealthResponse struct {
@@ -16,13 +20,34 @@ type HealthResponse struct {
 	CheckID string `json:"check_id"`
 	Name    string `json:"name"`
 	Status  string `json:"status"`
+
+	Checks    []HealthCheck    `json:"checks,omitempty"`
+	Notifiers []NotifierStatus `json:"notifiers,omitempty"`
+}
+
+// HealthCheck - результат проверки одного компонента.
+type HealthCheck struct {
+	Name         string  `json:"name"`
+	Status       string  `json:"status"`
+	Output       string  `json:"output,omitempty"`
+	LatencyMS    float64 `json:"latency_ms,omitempty"`      // время ответа хранилища или нотификатора
+	LastRunAgeMS float64 `json:"last_run_age_ms,omitempty"` // время с последнего прохода демона
+}
+
+// NotifierStatus - состояние провайдера уведомлений.
+type NotifierStatus struct {
+	Name      string `json:"name"`
+	State     string `json:"state"`
+	Failures  int    `json:"failures"`
+	LastError string `json:"last_error,omitempty"`
 }
 
-// Render выполняем интерфейс render.Renderer
+// Render выполняем интерфейс render.Renderer. Деградировавший сервис
+// остается готовым принимать запросы.
 func (h *HealthResponse) Render(_ http.ResponseWriter, r *http.Request) error {
 	code := http.StatusOK
-	if HealthStatusOK != h.Status {
-		code = http.StatusInternalServerError
+	if h.Status == HealthStatusCritical {
+		code = http.StatusServiceUnavailable
 	}
 
 	render.Status(r, code)
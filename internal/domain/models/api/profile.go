@@ -36,12 +36,45 @@ type ProfileUpdateRequest struct {
 	BirthDate  *time.Time `bson:"birth_date,omitempty" json:"birth_date,omitempty" validation:"omitempty,datetime=2006-01-02"`
 }
 
+// Merge переносит заполненные поля запроса в профиль пользователя.
+func (req *ProfileUpdateRequest) Merge(user *models.User) *models.User {
+	if req.FirstName != nil {
+		user.FirstName = *req.FirstName
+	}
+
+	if req.LastName != nil {
+		user.LastName = *req.LastName
+	}
+
+	if req.Patronymic != nil {
+		user.Patronymic = *req.Patronymic
+	}
+
+	if req.Email != nil {
+		user.Email = *req.Email
+	}
+
+	if req.Language != nil {
+		user.Language = *req.Language
+	}
+
+	if req.Sex != nil {
+		user.Sex = *req.Sex
+	}
+
+	if req.IIN != nil {
+		user.IIN = *req.IIN
+	}
+
+	return user
+}
+
 type AddPhoneRequest struct {
 	Phone string `bson:"phone" json:"phone"`
 }
 
 type UpdatePasswordRequest struct {
-	Password string `bson:"password" json:"password" validate:"required,gte=6"`
+	Password string `bson:"password" json:"password" validate:"required"`
 }
 
 type ProfileTypeRequest struct {
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// События журнала аудита.
+const (
+	AuditSignIn  = "auth.sign_in"
+	AuditSignUp  = "auth.sign_up"
+	AuditRefresh = "auth.refresh"
+	AuditSignOut = "auth.sign_out"
+
+	AuditRecoveryRequested = "auth.recovery_requested"
+	AuditPasswordRecovered = "auth.password_recovered"
+
+	AuditProfileUpdated  = "profile.updated"
+	AuditPasswordChanged = "profile.password_changed"
+
+	AuditUserUpdated      = "user.updated"
+	AuditUserRolesChanged = "user.roles_changed"
+	AuditUserEnabled      = "user.enabled"
+	AuditUserDisabled     = "user.disabled"
+	AuditUserDeleted      = "user.deleted"
+	AuditUserUnlocked     = "user.unlocked"
+
+	AuditRoleCreated = "role.created"
+	AuditRoleUpdated = "role.updated"
+	AuditRoleDeleted = "role.deleted"
+
+	AuditActionCreated = "action.created"
+	AuditActionUpdated = "action.updated"
+	AuditActionDeleted = "action.deleted"
+
+	AuditWebhookCreated       = "webhook.created"
+	AuditWebhookUpdated       = "webhook.updated"
+	AuditWebhookSecretRotated = "webhook.secret_rotated"
+	AuditWebhookDeleted       = "webhook.deleted"
+	AuditWebhookRedelivered   = "webhook.redelivered"
+
+	AuditOutboxReplayed = "outbox.replayed"
+
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+)
+
+// AuditEntry запись журнала аудита. Записи только добавляются и удаляются
+// хранилищем по истечении срока хранения.
+type AuditEntry struct {
+	ID        PolymorphicID `bson:"_id,omitempty" json:"id"`
+	Type      string        `bson:"type" json:"type"`
+	Outcome   string        `bson:"outcome" json:"outcome"`
+	Reason    string        `bson:"reason,omitempty" json:"reason,omitempty"` // причина неудачи
+	Actor     string        `bson:"actor,omitempty" json:"actor,omitempty"`   // TDID пользователя, выполнившего действие
+	TDID      string        `bson:"tdid,omitempty" json:"tdid,omitempty"`     // TDID пользователя, которого касается событие
+	Login     string        `bson:"login,omitempty" json:"login,omitempty"`   // логин, с которым выполнялся вход
+	Target    string        `bson:"target,omitempty" json:"target,omitempty"` // объект действия: роль, webhook, сообщение outbox
+	Changes   []AuditChange `bson:"changes,omitempty" json:"changes,omitempty"`
+	IP        string        `bson:"ip,omitempty" json:"ip,omitempty"`
+	UserAgent string        `bson:"userAgent,omitempty" json:"userAgent,omitempty"`
+	DeviceID  string        `bson:"deviceId,omitempty" json:"deviceId,omitempty"`
+	RequestID string        `bson:"requestId,omitempty" json:"requestId,omitempty"`
+	Created   time.Time     `bson:"created" json:"created"`
+}
+
+// AuditChange изменение одного поля с его значениями до и после.
+type AuditChange struct {
+	Field  string     `bson:"field" json:"field"`
+	Before AuditValue `bson:"before,omitempty" json:"before,omitempty"`
+	After  AuditValue `bson:"after,omitempty" json:"after,omitempty"`
+}
+
+// AuditSource описывает, откуда выполнено действие.
+type AuditSource struct {
+	IP        string
+	UserAgent string
+	DeviceID  string
+	RequestID string
+}
+
+// AuditFilters ограничивает выборку журнала аудита.
+type AuditFilters struct {
+	TDID    *string
+	Actor   *string
+	Type    *string
+	Outcome *string
+	From    *time.Time
+	To      *time.Time
+	Limit   int64
+	Offset  int64
+}
+
+var errInvalidAuditValue = errors.New("audit value must be a string")
+
+// AuditValue значение поля в JSON. В хранилище записывается строкой, чтобы
+// значения любой структуры читались обратно без потерь.
+type AuditValue []byte
+
+func (v AuditValue) MarshalJSON() ([]byte, error) {
+	if len(v) == 0 {
+		return []byte("null"), nil
+	}
+
+	return v, nil
+}
+
+func (v *AuditValue) UnmarshalJSON(data []byte) error {
+	*v = append((*v)[:0], data...)
+	return nil
+}
+
+func (v AuditValue) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bson.MarshalValue(string(v))
+}
+
+func (v *AuditValue) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	s, ok := bson.RawValue{Type: t, Value: data}.StringValueOK()
+	if !ok {
+		return errInvalidAuditValue
+	}
+
+	*v = AuditValue(s)
+	return nil
+}
@@ -5,6 +5,7 @@ type ErrorResponse struct {
 	HTTPStatusCode int               `json:"-"` // HTTP статус код
 	ErrorMessage   *ErrorDetails     `json:"error"`
 	Validation     map[string]string `json:"validation,omitempty"` // ошибки валидации
+	Violations     []RuleViolation   `json:"violations,omitempty"` // нарушенные правила, например политики паролей
 }
 
 type ErrorDetails struct {
@@ -12,3 +13,10 @@ type ErrorDetails struct {
 	AppCode     int64  `json:"code,omitempty"`    // application-определенный код ошибки
 	MessageText string `json:"message,omitempty"` // application-level сообщение, для дебага
 }
+
+// RuleViolation нарушение одного правила валидации поля.
+type RuleViolation struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
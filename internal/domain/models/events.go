@@ -16,9 +16,9 @@ func NewEvent(tdid PolymorphicID, actionType string) *Event {
 type Event struct {
 	ID         PolymorphicID `bson:"_id" json:"id"`
 	TDID       PolymorphicID `bson:"tdid" json:"tdid"`
-	ActionType string               `bson:"actionType" json:"actionType"`
-	Verify     *Verify              `bson:"verify,omitempty" json:"verify,omitempty"`
-	ExpiresAt  *time.Time           `bson:"expiresAt,omitempty" json:"expiresAt,omitempty"`
+	ActionType string        `bson:"actionType" json:"actionType"`
+	Verify     *Verify       `bson:"verify,omitempty" json:"verify,omitempty"`
+	ExpiresAt  *time.Time    `bson:"expiresAt,omitempty" json:"expiresAt,omitempty"`
 }
 
 func (e *Event) AddVerify(verify *Verify) {
@@ -52,3 +52,14 @@ func (e *Event) FinishVerify() {
 func (e *Event) AddDeadline(deadline time.Time) {
 	e.ExpiresAt = &deadline
 }
+
+// IsExpired сообщает, истек ли срок жизни события на момент now.
+func (e *Event) IsExpired(now time.Time) bool {
+	return e.ExpiresAt != nil && !now.Before(*e.ExpiresAt)
+}
+
+// IsConfirmed сообщает, подтверждено ли событие пользователем и
+// может ли еще быть использовано на момент now.
+func (e *Event) IsConfirmed(now time.Time) bool {
+	return e.Verify != nil && e.Verify.Status == domain.TokenStatusFinish && !e.IsExpired(now)
+}
@@ -6,4 +6,5 @@ type UsersSearchFilters struct {
 	Firstname  *string
 	Lastname   *string
 	Patronymic *string
+	Role       *string
 }
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Lockout учет неудачных попыток входа в учетную запись. Отсутствует у
+// пользователей, которые не ошибались с паролем с последнего успешного входа.
+type Lockout struct {
+	Failures    int       `bson:"failures" json:"failures"`         // неудачные попытки с последней блокировки
+	Locks       int       `bson:"locks" json:"locks"`               // блокировки подряд, от них зависит длительность следующей
+	LastFailure time.Time `bson:"last_failure" json:"last_failure"` // время последней неудачной попытки
+	Until       time.Time `bson:"until" json:"until"`               // до этого времени попытки входа отклоняются
+	Locked      bool      `bson:"locked" json:"locked"`             // Until выставлен блокировкой, а не задержкой между попытками
+}
+
+// Remaining возвращает время, оставшееся до следующей разрешенной попытки
+// входа. Нулевой указатель означает, что вход не ограничен.
+func (l *Lockout) Remaining(now time.Time) time.Duration {
+	if l == nil || !now.Before(l.Until) {
+		return 0
+	}
+
+	return l.Until.Sub(now)
+}
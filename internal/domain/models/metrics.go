@@ -2,24 +2,50 @@ package models
 
 import "github.com/prometheus/client_golang/prometheus"
 
+// Операции в метриках.
+const (
+	OperationSignIn   = "sign_in"
+	OperationSignUp   = "sign_up"
+	OperationRefresh  = "refresh"
+	OperationRecovery = "recovery"
+)
+
+// Результаты операций в метриках.
+const (
+	OutcomeSuccess            = "success"
+	OutcomeInvalidRequest     = "invalid_request"
+	OutcomeInvalidCredentials = "invalid_credentials"
+	OutcomeInvalidToken       = "invalid_token"
+	OutcomeInternalError      = "internal_error"
+	OutcomeRateLimited        = "rate_limited"
+	OutcomeLocked             = "locked"
+)
+
+// Metrics содержит метрики Prometheus по бизнес-операциям.
+// Нулевой указатель допустим: метрики в этом случае не собираются.
 type Metrics struct {
-	SignInByPhoneRequests                     *prometheus.Counter
-	SignInByPhoneInvalidLoginOrPasswordErrors *prometheus.Counter
-	SignInByPhoneInvalidNumberFormatErrors    *prometheus.Counter
-	SignInByPhoneRecoveryRequiredErrors       *prometheus.Counter
-	SignInByPhoneUserNotFoundErrors           *prometheus.Counter
-	SignInByPhoneVerificationRequiredErrors   *prometheus.Counter
-	SignInByPhoneInternalServerErrors         *prometheus.Counter
-	SignInByPhoneSuccessfulOperation          *prometheus.Counter
-	SignInValidationErrors                    *prometheus.Counter
-
-	SignUpByPhoneRequests      *prometheus.Counter
-	SignUpInternalServerErrors *prometheus.Counter
-	SignUpValidationErrors     *prometheus.Counter
-	SignUpSuccessfulOperation  *prometheus.Counter
-
-	RefreshRequests             *prometheus.Counter
-	RefreshInternalServerErrors *prometheus.Counter
-	RefreshSuccessfulOperation  *prometheus.Counter
-	RefreshInvalidTokenErrors   *prometheus.Counter
+	operations *prometheus.CounterVec
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		operations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "sso_operations_total",
+			Help: "Total number of operations by outcome.",
+		}, []string{"operation", "outcome"}),
+	}
+}
+
+// Collectors возвращает метрики для регистрации в Prometheus.
+func (m *Metrics) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{m.operations}
+}
+
+// Observe учитывает завершение операции с результатом outcome.
+func (m *Metrics) Observe(operation, outcome string) {
+	if m == nil {
+		return
+	}
+
+	m.operations.WithLabelValues(operation, outcome).Inc()
 }
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	OutboxStatusPending = "pending" // ожидает отправки
+	OutboxStatusSending = "sending" // взято в отправку
+	OutboxStatusSent    = "sent"    // доставлено провайдеру
+	OutboxStatusDead    = "dead"    // исчерпаны попытки отправки
+
+	OutboxChannelSMS   = "sms"
+	OutboxChannelEmail = "email"
+)
+
+// OutboxMessage уведомление, записанное вместе с изменением состояния,
+// которое его порождает, и ожидающее доставки.
+type OutboxMessage struct {
+	ID             PolymorphicID `bson:"_id,omitempty" json:"id"`
+	IdempotencyKey string        `bson:"idempotencyKey" json:"idempotencyKey"` // ключ, по которому повторная запись и отправка игнорируются
+	Template       string        `bson:"template" json:"template"`
+	Channel        string        `bson:"channel" json:"channel"`
+	Recipient      string        `bson:"recipient" json:"recipient"`
+	Language       string        `bson:"lang" json:"lang"`
+	TDID           PolymorphicID `bson:"tdid" json:"tdid"`
+	ActionType     string        `bson:"actionType,omitempty" json:"actionType,omitempty"`
+	Action         string        `bson:"action,omitempty" json:"-"` // название подтверждаемого действия
+	Name           string        `bson:"name,omitempty" json:"-"`
+	Code           string        `bson:"code" json:"-"`
+	Until          *time.Time    `bson:"until,omitempty" json:"-"` // окончание блокировки учетной записи
+	Status         string        `bson:"status" json:"status"`
+	Attempts       int           `bson:"attempts" json:"attempts"`
+	LastError      string        `bson:"lastError,omitempty" json:"lastError,omitempty"`
+	NextAttemptAt  time.Time     `bson:"nextAttemptAt" json:"nextAttemptAt"`
+	LeaseUntil     *time.Time    `bson:"leaseUntil,omitempty" json:"-"` // до этого времени сообщение принадлежит отправителю
+	Created        time.Time     `bson:"created" json:"created"`
+	Updated        time.Time     `bson:"updated" json:"updated"`
+	SentAt         *time.Time    `bson:"sentAt,omitempty" json:"sentAt,omitempty"`
+}
+
+// OutboxFilters ограничивает выборку сообщений outbox.
+type OutboxFilters struct {
+	Status *string
+	Limit  int64
+	Offset int64
+}
+
+// NewOutboxMessage создает сообщение, готовое к немедленной отправке.
+func NewOutboxMessage(key, template, channel, recipient string) *OutboxMessage {
+	now := time.Now().In(time.UTC)
+
+	return &OutboxMessage{
+		IdempotencyKey: key,
+		Template:       template,
+		Channel:        channel,
+		Recipient:      recipient,
+		Status:         OutboxStatusPending,
+		NextAttemptAt:  now,
+		Created:        now,
+		Updated:        now,
+	}
+}
+
+// OutboxKey собирает ключ идемпотентности из частей, однозначно
+// определяющих изменение состояния.
+func OutboxKey(parts ...string) string {
+	return strings.Join(parts, ":")
+}
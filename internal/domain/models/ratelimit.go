@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// RateLimit ограничение частоты запросов корзиной токенов: в корзине
+// помещается Burst токенов, и она заполняется полностью за Period.
+// Нулевое ограничение отключено.
+type RateLimit struct {
+	Burst  int
+	Period time.Duration
+}
+
+// Enabled сообщает, задано ли ограничение.
+func (l RateLimit) Enabled() bool {
+	return l.Burst > 0 && l.Period > 0
+}
+
+// Interval возвращает время появления одного токена.
+func (l RateLimit) Interval() time.Duration {
+	return l.Period / time.Duration(l.Burst)
+}
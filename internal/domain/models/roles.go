@@ -2,6 +2,12 @@ package models
 
 import "go.mongodb.org/mongo-driver/bson/primitive"
 
+// Встроенные роли, создаваемые при запуске сервиса.
+const (
+	RoleUser  = "user"  // роль, выдаваемая каждому зарегистрированному пользователю
+	RoleAdmin = "admin" // роль с полным набором прав
+)
+
 type RolePermissions map[string][]string
 
 type Role struct {
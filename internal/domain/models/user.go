@@ -35,6 +35,7 @@ type User struct {
 	Devices      []Device           `bson:"devices,omitempty" json:"devices,omitempty"`
 	BankData     *BankData          `bson:"bankData,omitempty" json:"bankData,omitempty"`
 	LastVerified *LastVerified      `bson:"last_verified,omitempty" json:"last_verified,omitempty"`
+	Lockout      *Lockout           `bson:"lockout,omitempty" json:"lockout,omitempty"`
 }
 
 type UserShortInfo struct {
@@ -83,7 +84,7 @@ func NewGeneralUser(email, phone, password, firstName, lastName, patronymic, lan
 		Enabled:      true,
 		Created:      time.Now().In(time.UTC),
 		Updated:      time.Now().In(time.UTC),
-		Roles:        []string{"user"},
+		Roles:        []string{RoleUser},
 		Receivers:    make([]Receiver, 0),
 		Devices:      make([]Device, 0),
 	}
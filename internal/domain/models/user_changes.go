@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Типы изменений пользователя, которые транслируются подписчикам WatchUsers.
+const (
+	UserChangeCreated  = "created"
+	UserChangeUpdated  = "updated"
+	UserChangeEnabled  = "enabled"
+	UserChangeDisabled = "disabled"
+	UserChangeDeleted  = "deleted"
+)
+
+// UserChange изменение пользователя в потоке изменений.
+type UserChange struct {
+	Type        string
+	TDID        primitive.ObjectID
+	User        *User  // состояние после изменения, отсутствует для удаленного пользователя
+	ResumeToken string // позиция в потоке, с которой можно продолжить чтение после этого изменения
+	Time        time.Time
+}
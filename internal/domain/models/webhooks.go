@@ -0,0 +1,109 @@
+package models
+
+import "time"
+
+// События жизненного цикла пользователя, на которые можно подписаться.
+const (
+	WebhookEventAll            = "*" // подписка на все события
+	WebhookEventUserCreated    = "user.created"
+	WebhookEventUserUpdated    = "user.updated"
+	WebhookEventEmailChanged   = "user.email_changed"
+	WebhookEventUserEnabled    = "user.enabled"
+	WebhookEventUserDisabled   = "user.disabled"
+	WebhookEventUserDeleted    = "user.deleted"
+	WebhookEventActionVerified = "user.action_verified" // пользователь подтвердил действие одноразовым паролем
+)
+
+const (
+	WebhookDeliveryPending   = "pending"   // ожидает отправки
+	WebhookDeliverySending   = "sending"   // взята в отправку
+	WebhookDeliveryDelivered = "delivered" // получатель ответил 2xx
+	WebhookDeliveryDead      = "dead"      // исчерпаны попытки отправки
+)
+
+// WebhookEvents возвращает все события, на которые можно подписаться.
+func WebhookEvents() []string {
+	return []string{
+		WebhookEventUserCreated,
+		WebhookEventUserUpdated,
+		WebhookEventEmailChanged,
+		WebhookEventUserEnabled,
+		WebhookEventUserDisabled,
+		WebhookEventUserDeleted,
+		WebhookEventActionVerified,
+	}
+}
+
+// Webhook подписка внешней системы на события пользователей.
+type Webhook struct {
+	ID          PolymorphicID `bson:"_id,omitempty" json:"id"`
+	URL         string        `bson:"url" json:"url"`
+	Secret      string        `bson:"secret" json:"-"` // ключ подписи HMAC-SHA256 тела запроса
+	Events      []string      `bson:"events" json:"events"`
+	Description string        `bson:"description,omitempty" json:"description,omitempty"`
+	Enabled     bool          `bson:"enabled" json:"enabled"`
+	Created     time.Time     `bson:"created" json:"created"`
+	Updated     time.Time     `bson:"updated" json:"updated"`
+}
+
+// Subscribed проверяет, подписан ли webhook на событие event.
+func (w *Webhook) Subscribed(event string) bool {
+	for _, e := range w.Events {
+		if e == event || e == WebhookEventAll {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WebhookEvent тело запроса, отправляемого подписчику.
+type WebhookEvent struct {
+	ID      string      `json:"id"` // одинаков для всех подписчиков и повторных отправок
+	Type    string      `json:"type"`
+	Created time.Time   `json:"created"`
+	Data    interface{} `json:"data"`
+}
+
+// WebhookDelivery отправка события одному подписчику и журнал ее попыток.
+type WebhookDelivery struct {
+	ID             PolymorphicID `bson:"_id,omitempty" json:"id"`
+	WebhookID      PolymorphicID `bson:"webhookId" json:"webhookId"`
+	EventID        string        `bson:"eventId" json:"eventId"`
+	Event          string        `bson:"event" json:"event"`
+	Payload        string        `bson:"payload" json:"payload"` // тело запроса, одинаковое для всех попыток
+	Status         string        `bson:"status" json:"status"`
+	Attempts       int           `bson:"attempts" json:"attempts"`
+	ResponseStatus int           `bson:"responseStatus,omitempty" json:"responseStatus,omitempty"`
+	LastError      string        `bson:"lastError,omitempty" json:"lastError,omitempty"`
+	NextAttemptAt  time.Time     `bson:"nextAttemptAt" json:"nextAttemptAt"`
+	LeaseUntil     *time.Time    `bson:"leaseUntil,omitempty" json:"-"` // до этого времени отправка принадлежит отправителю
+	Created        time.Time     `bson:"created" json:"created"`
+	Updated        time.Time     `bson:"updated" json:"updated"`
+	DeliveredAt    *time.Time    `bson:"deliveredAt,omitempty" json:"deliveredAt,omitempty"`
+}
+
+// WebhookDeliveryFilters ограничивает выборку журнала отправок.
+type WebhookDeliveryFilters struct {
+	WebhookID *PolymorphicID
+	Event     *string
+	Status    *string
+	Limit     int64
+	Offset    int64
+}
+
+// NewWebhookDelivery создает отправку, готовую к немедленному выполнению.
+func NewWebhookDelivery(webhookID PolymorphicID, eventID, event, payload string) *WebhookDelivery {
+	now := time.Now().In(time.UTC)
+
+	return &WebhookDelivery{
+		WebhookID:     webhookID,
+		EventID:       eventID,
+		Event:         event,
+		Payload:       payload,
+		Status:        WebhookDeliveryPending,
+		NextAttemptAt: now,
+		Created:       now,
+		Updated:       now,
+	}
+}
@@ -0,0 +1,7 @@
+package permissions
+
+const (
+	ViewAudit = "audit-view"
+)
+
+var AuditPermissions = []string{ViewAudit}
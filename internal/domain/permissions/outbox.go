@@ -0,0 +1,8 @@
+package permissions
+
+const (
+	ViewOutbox   = "outbox-view"
+	ReplayOutbox = "outbox-replay"
+)
+
+var OutboxPermissions = []string{ViewOutbox, ReplayOutbox}
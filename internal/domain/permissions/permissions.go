@@ -0,0 +1,26 @@
+package permissions
+
+// Groups возвращает все известные права, сгруппированные по ресурсам.
+func Groups() map[string][]string {
+	return map[string][]string{
+		"actions":  ActionsPermissions,
+		"audit":    AuditPermissions,
+		"outbox":   OutboxPermissions,
+		"roles":    RolesPermissions,
+		"users":    UsersPermissions,
+		"webhooks": WebhooksPermissions,
+	}
+}
+
+// Known проверяет, что право permission известно системе.
+func Known(permission string) bool {
+	for _, group := range Groups() {
+		for _, p := range group {
+			if p == permission {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -3,6 +3,7 @@ package permissions
 const (
 	ViewUsers   = "users-view"
 	UpdateUsers = "users-update"
+	DeleteUsers = "users-delete"
 )
 
-var UsersPermissions = []string{ViewUsers, UpdateUsers}
+var UsersPermissions = []string{ViewUsers, UpdateUsers, DeleteUsers}
@@ -0,0 +1,10 @@
+package permissions
+
+const (
+	CreateWebhooks = "webhooks-create"
+	ViewWebhooks   = "webhooks-view"
+	UpdateWebhooks = "webhooks-update"
+	DeleteWebhooks = "webhooks-delete"
+)
+
+var WebhooksPermissions = []string{CreateWebhooks, ViewWebhooks, UpdateWebhooks, DeleteWebhooks}
@@ -0,0 +1,116 @@
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+const (
+	Restore = "restore" // код восстановления доступа
+	Verify  = "verify"  // код подтверждения телефона или email
+	Confirm = "confirm" // код подтверждения действия
+	Locked  = "locked"  // учетная запись заблокирована после неудачных попыток входа
+
+	DefaultLanguage = "ru"
+)
+
+var ErrUnknownTemplate = errors.New("unknown template")
+
+// Languages перечисляет поддерживаемые языки уведомлений.
+var Languages = []string{"ru", "en", "kk"}
+
+//go:embed ru en kk
+var files embed.FS
+
+// Data содержит данные, подставляемые в шаблоны.
+type Data struct {
+	Name   string // имя пользователя
+	Code   string // одноразовый код
+	Action string // название подтверждаемого действия
+	Until  string // окончание блокировки учетной записи
+}
+
+// Message результат отрисовки шаблона. Text используется для SMS и
+// текстовой части письма, HTML для HTML части письма.
+type Message struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Engine отрисовывает уведомления на языке пользователя.
+type Engine struct {
+	text map[string]*texttemplate.Template
+	html map[string]*htmltemplate.Template
+}
+
+// New разбирает встроенные шаблоны всех поддерживаемых языков.
+func New() (*Engine, error) {
+	e := &Engine{
+		text: make(map[string]*texttemplate.Template),
+		html: make(map[string]*htmltemplate.Template),
+	}
+
+	for _, lang := range Languages {
+		for _, name := range []string{Restore, Verify, Confirm, Locked} {
+			text, err := texttemplate.ParseFS(files, fmt.Sprintf("%s/%s.tmpl", lang, name))
+			if err != nil {
+				return nil, err
+			}
+
+			html, err := htmltemplate.ParseFS(files, fmt.Sprintf("%s/%s.html", lang, name))
+			if err != nil {
+				return nil, err
+			}
+
+			e.text[key(lang, name)] = text
+			e.html[key(lang, name)] = html
+		}
+	}
+
+	return e, nil
+}
+
+// Render отрисовывает шаблон name на языке lang. Для неизвестного языка
+// используется DefaultLanguage.
+func (e *Engine) Render(lang, name string, data Data) (Message, error) {
+	var msg Message
+
+	lang = strings.ToLower(lang)
+	text, ok := e.text[key(lang, name)]
+	if !ok {
+		lang = DefaultLanguage
+		if text, ok = e.text[key(lang, name)]; !ok {
+			return msg, fmt.Errorf("%w: %s", ErrUnknownTemplate, name)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := text.ExecuteTemplate(&buf, "subject", data); err != nil {
+		return msg, err
+	}
+	msg.Subject = buf.String()
+
+	buf.Reset()
+	if err := text.ExecuteTemplate(&buf, "text", data); err != nil {
+		return msg, err
+	}
+	msg.Text = buf.String()
+
+	buf.Reset()
+	if err := e.html[key(lang, name)].ExecuteTemplate(&buf, "html", data); err != nil {
+		return msg, err
+	}
+	msg.HTML = buf.String()
+
+	return msg, nil
+}
+
+func key(lang, name string) string {
+	return lang + "/" + name
+}
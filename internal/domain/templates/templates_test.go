@@ -0,0 +1,69 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEngine_Render(t *testing.T) {
+	e, err := New()
+	if err != nil {
+		t.Fatalf("cannot parse templates: %v", err)
+	}
+
+	for _, lang := range Languages {
+		for _, name := range []string{Restore, Verify, Confirm} {
+			msg, err := e.Render(lang, name, Data{Name: "<Айдос>", Code: "1234", Action: "delete-account"})
+			if err != nil {
+				t.Fatalf("%s/%s: %v", lang, name, err)
+			}
+
+			if msg.Subject == "" || !strings.Contains(msg.Text, "1234") || !strings.Contains(msg.HTML, "<b>1234</b>") {
+				t.Errorf("%s/%s: unexpected message %+v", lang, name, msg)
+			}
+
+			if strings.Contains(msg.HTML, "<Айдос>") {
+				t.Errorf("%s/%s: user name is not escaped in html", lang, name)
+			}
+		}
+	}
+}
+
+func TestEngine_RenderLocked(t *testing.T) {
+	e, err := New()
+	if err != nil {
+		t.Fatalf("cannot parse templates: %v", err)
+	}
+
+	for _, lang := range Languages {
+		msg, err := e.Render(lang, Locked, Data{Name: "Айдос", Until: "01.02.2021 10:30 UTC"})
+		if err != nil {
+			t.Fatalf("%s: %v", lang, err)
+		}
+
+		if msg.Subject == "" || !strings.Contains(msg.Text, "01.02.2021 10:30 UTC") || !strings.Contains(msg.HTML, "<b>01.02.2021 10:30 UTC</b>") {
+			t.Errorf("%s: unexpected message %+v", lang, msg)
+		}
+	}
+}
+
+func TestEngine_RenderFallback(t *testing.T) {
+	e, err := New()
+	if err != nil {
+		t.Fatalf("cannot parse templates: %v", err)
+	}
+
+	msg, err := e.Render("de", Restore, Data{Code: "1234"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want, _ := e.Render(DefaultLanguage, Restore, Data{Code: "1234"})
+	if msg != want {
+		t.Errorf("expected fallback to %s, got %+v", DefaultLanguage, msg)
+	}
+
+	if _, err := e.Render("ru", "unknown", Data{}); err == nil {
+		t.Error("expected error for unknown template")
+	}
+}
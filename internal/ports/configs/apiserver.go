@@ -22,8 +22,18 @@ type APIServer struct {
 	FilesDir   string `long:"files-directory" env:"FILES_DIR" description:"Directory where all static files are located" required:"false" default:"/home/jetbrainer/github.com/JetBrainer/sso/api"`
 	CertFile   string `short:"c" long:"cert" env:"CERT_FILE" description:"Location of the SSL/TLS cert file" required:"false" default:""`
 	KeyFile    string `short:"k" long:"key" env:"KEY_FILE" description:"Location of the SSL/TLS key file" required:"false" default:""`
+	NodeName   string `long:"node-name" env:"NODE_NAME" description:"Node name in health check responses, host name if not set" required:"false"`
 
-	GrpcListenAddr          string `long:"grpc-listen" env:"GRPC_LISTEN" description:"Grpc Listen Address (format: :4000|127.0.0.1:4000)" required:"false" default:":4000"`
+	GrpcListenAddr  string   `long:"grpc-listen" env:"GRPC_LISTEN" description:"Grpc Listen Address (format: :4000|127.0.0.1:4000)" required:"false" default:":4000"`
+	GrpcClients     []string `long:"grpc-client" env:"GRPC_CLIENTS" env-delim:"," description:"gRPC service clients credentials (format: id:secret:role1|role2)" required:"false"`
+	GrpcRequireAuth bool     `long:"grpc-require-auth" env:"GRPC_REQUIRE_AUTH" description:"Require credentials for all gRPC methods"`
+
+	GrpcCertFile          string   `long:"grpc-cert" env:"GRPC_CERT_FILE" description:"Location of the gRPC TLS cert file" required:"false"`
+	GrpcKeyFile           string   `long:"grpc-key" env:"GRPC_KEY_FILE" description:"Location of the gRPC TLS key file" required:"false"`
+	GrpcClientCA          string   `long:"grpc-client-ca" env:"GRPC_CLIENT_CA" description:"CA bundle to verify gRPC client certificates" required:"false"`
+	GrpcRequireClientCert bool     `long:"grpc-require-client-cert" env:"GRPC_REQUIRE_CLIENT_CERT" description:"Reject gRPC clients without a valid certificate"`
+	GrpcCertIdentities    []string `long:"grpc-cert-identity" env:"GRPC_CERT_IDENTITIES" env-delim:"," description:"gRPC client certificate identities (format: common-name:role1|role2)" required:"false"`
+	GrpcReflection        bool     `long:"grpc-reflection" env:"GRPC_REFLECTION" description:"Enable gRPC server reflection"`
 
 	JWTKey                string `long:"jwt-key" env:"JWT_KEY" description:"JWT secret key" required:"false" default:"somatic-key"`
 	TokenTTLInMin         int64  `long:"token-ttl" env:"TOKEN_TTL" description:"Auth token lifetime duration (in min)" required:"false" default:"120"`
@@ -34,10 +44,58 @@ type APIServer struct {
 	PromListenAddr string `long:"prom-listen" env:"PROM_LISTEN" description:"Listen Address (format: :9090|127.0.0.1:9090)" required:"false" default:":9090"`
 	Prometheus     bool   `long:"with-prom" env:"WITH_PROM" description:"Enable Prometheus metrics" required:"false"`
 
+	LogLevel  string `long:"log-level" env:"LOG_LEVEL" description:"Minimal log level (format: DEBUG/INFO/WARN/ERROR)" required:"false" default:"INFO"`
+	LogFormat string `long:"log-format" env:"LOG_FORMAT" description:"Log output format (format: json/text)" required:"false" default:"json"`
+
+	TracingExporter    string  `long:"tracing-exporter" env:"TRACING_EXPORTER" description:"OpenTelemetry spans exporter (format: none/otlp/stdout)" required:"false" default:"none"`
+	TracingEndpoint    string  `long:"tracing-endpoint" env:"TRACING_ENDPOINT" description:"OTLP/HTTP collector address (format: http://localhost:4318)" required:"false"`
+	TracingSampleRatio float64 `long:"tracing-sample-ratio" env:"TRACING_SAMPLE_RATIO" description:"Share of traced requests without parent trace context (0..1)" required:"false" default:"1"`
+
 	RecoverySpamPenalty   int64 `env:"RECOVERY_SPAM_PENALTY" description:"recovery spam penalty (in sec)" required:"false"`
 	VerifySpamPenalty     int64 `env:"VERIFY_SPAM_PENALTY" description:"verify spam penalty (in sec)" required:"false"`
 	VerifyLongSpamPenalty int64 `env:"VERIFY_LONG_SPAM_PENALTY" description:"verify long spam penalty (in sec)" required:"false"`
 
+	PermissionsCacheTTL int64 `env:"PERMISSIONS_CACHE_TTL" description:"roles permissions cache lifetime (in sec)" required:"false"`
+
+	RateLimitBackend string `long:"rate-limit-backend" env:"RATE_LIMIT_BACKEND" description:"Rate limit buckets storage (format: memory/datastore)" required:"false" default:"memory"`
+	RateLimitRoute   string `long:"rate-limit-route" env:"RATE_LIMIT_ROUTE" description:"Requests limit per authentication endpoint for all clients (format: 1000/1m), disabled if empty" required:"false"`
+	RateLimitIP      string `long:"rate-limit-ip" env:"RATE_LIMIT_IP" description:"Requests limit per authentication endpoint for client IP (format: 30/1m)" required:"false" default:"30/1m"`
+	RateLimitLogin   string `long:"rate-limit-login" env:"RATE_LIMIT_LOGIN" description:"Requests limit per authentication endpoint for email or phone (format: 10/1m)" required:"false" default:"10/1m"`
+
+	TrustedProxies []string `long:"trusted-proxy" env:"TRUSTED_PROXIES" env-delim:"," description:"Proxies allowed to pass client address in X-Forwarded-For and X-Real-IP (format: 10.0.0.0/8,127.0.0.1)" required:"false"`
+
+	PasswordMinLength     int      `env:"PASSWORD_MIN_LENGTH" description:"minimal password length (in characters), 8 if not set" required:"false"`
+	PasswordRequire       []string `long:"password-require" env:"PASSWORD_REQUIRE" env-delim:"," description:"character classes required in passwords (format: lower,upper,digit,symbol)" required:"false"`
+	PasswordAllowPersonal bool     `long:"password-allow-personal" env:"PASSWORD_ALLOW_PERSONAL" description:"Allow passwords containing user phone, email or name"`
+
+	LockoutFreeAttempts int   `env:"LOCKOUT_FREE_ATTEMPTS" description:"failed sign in attempts before sign in delays, 3 if not set" required:"false"`
+	LockoutThreshold    int   `env:"LOCKOUT_THRESHOLD" description:"failed sign in attempts before account lock, 10 if not set" required:"false"`
+	LockoutDuration     int64 `env:"LOCKOUT_DURATION" description:"first account lock duration (in sec), doubles with every next lock, 900 if not set" required:"false"`
+
+	AuditRetentionDays int64 `env:"AUDIT_RETENTION_DAYS" description:"audit log entries lifetime (in days), 365 if not set" required:"false"`
+
+	NotifyProvider  string   `long:"notify-provider" env:"NOTIFY_PROVIDER" description:"Notifications providers in priority order (format: http,file/stdout/fake)" required:"false" default:"stdout"`
+	NotifyURL       []string `long:"notify-url" env:"NOTIFY_URL" env-delim:"," description:"SMS gateway URLs in priority order" required:"false"`
+	NotifyHealthURL []string `long:"notify-health-url" env:"NOTIFY_HEALTH_URL" env-delim:"," description:"SMS gateways health check URLs in the same order" required:"false"`
+	NotifyPrefix    string   `long:"notify-pref" env:"NOTIFY_PREF" description:"System prefix for SMS gateway" required:"false"`
+	NotifySender    string   `long:"notify-send" env:"NOTIFY_SEND" description:"SMS sender identifier" required:"false"`
+	NotifyFile      string   `long:"notify-file" env:"NOTIFY_FILE" description:"File for notifications of file provider" required:"false" default:"notifications.log"`
+
+	NotifyBreakerThreshold int   `env:"NOTIFY_BREAKER_THRESHOLD" description:"consecutive notifier provider failures before it is switched off" required:"false"`
+	NotifyBreakerCooldown  int64 `env:"NOTIFY_BREAKER_COOLDOWN" description:"notifier provider switch off duration (in sec)" required:"false"`
+
+	SMTPHost     string `long:"smtp-host" env:"SMTP_HOST" description:"SMTP server host, email is sent by notify provider if empty" required:"false"`
+	SMTPPort     int    `long:"smtp-port" env:"SMTP_PORT" description:"SMTP server port" required:"false" default:"587"`
+	SMTPUser     string `long:"smtp-user" env:"SMTP_USER" description:"SMTP username" required:"false"`
+	SMTPPassword string `long:"smtp-password" env:"SMTP_PASSWORD" description:"SMTP password" required:"false"`
+	SMTPFrom     string `long:"smtp-from" env:"SMTP_FROM" description:"Sender email address" required:"false"`
+	SMTPStartTLS bool   `long:"smtp-starttls" env:"SMTP_STARTTLS" description:"Require STARTTLS for SMTP connection"`
+
+	EventsdPollInterval int64 `env:"EVENTSD_POLL_INTERVAL" description:"notifications queues poll interval (in sec)" required:"false"`
+	EventsdStuckTimeout int64 `env:"EVENTSD_STUCK_TIMEOUT" description:"timeout after which unsent notification returns to queue (in sec)" required:"false"`
+	OutboxMaxAttempts   int   `env:"OUTBOX_MAX_ATTEMPTS" description:"delivery attempts before notification is dead-lettered" required:"false"`
+	WebhookTimeout      int64 `env:"WEBHOOK_TIMEOUT" description:"webhook subscriber response timeout (in sec)" required:"false"`
+
 	Dbg       bool `long:"dbg" env:"DEBUG" description:"debug mode"`
 	IsTesting bool `long:"testing" env:"APP_TESTING" description:"testing mode"`
 }
@@ -4,19 +4,53 @@ import (
 	"context"
 	"log"
 	"net"
+	"time"
 
 	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
 	"github.com/JetBrainer/sso/internal/domain/manager/auth"
+	"github.com/JetBrainer/sso/internal/domain/manager/monitoring"
+	"github.com/JetBrainer/sso/internal/domain/permissions"
 	"github.com/JetBrainer/sso/internal/ports/configs"
+	"github.com/JetBrainer/sso/internal/ports/grpc/interceptors"
 	"github.com/JetBrainer/sso/internal/ports/grpc/resources"
-	"github.com/Somatic-KZ/sso-client/protobuf"
+	"github.com/JetBrainer/sso/pkg/protobuf"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
+const (
+	ssoServiceName = "sso.pkg.go.SSO"
+	ssoService     = "/" + ssoServiceName + "/"
+)
+
+// methodPermissions сопоставляет полные имена gRPC методов с правами,
+// необходимыми для их вызова. Методы вне карты доступны без проверки прав.
+var methodPermissions = map[string]string{
+	ssoService + "GetUser":         permissions.ViewUsers,
+	ssoService + "GetUsers":        permissions.ViewUsers,
+	ssoService + "FullNamesByTDID": permissions.ViewUsers,
+	ssoService + "UserDevices":     permissions.ViewUsers,
+	ssoService + "UserReceivers":   permissions.ViewUsers,
+	ssoService + "WatchUsers":      permissions.ViewUsers,
+}
+
 type APIServer struct {
 	Address     string
 	IsTesting   bool
 	authManager *auth.Manager
+	metrics     *interceptors.Metrics
+
+	clients     []string // учетные данные клиентов в формате id:secret:role1|role2
+	certs       []string // роли клиентских сертификатов в формате common-name:role1|role2
+	requireAuth bool
+	tls         *TLSConfig
+	reflection  bool
+
+	monitoringManager *monitoring.Manager
+	health            *health.Server
+	healthInterval    time.Duration
 
 	server    protobuf.SSOServer
 	verifyMan *resources.Verify
@@ -32,10 +66,24 @@ func NewAPIServer(ctx context.Context, config *configs.APIServer, opts ...APISer
 	srv := &APIServer{
 		Address:         config.GrpcListenAddr,
 		IsTesting:       config.IsTesting,
+		clients:         config.GrpcClients,
+		certs:           config.GrpcCertIdentities,
+		requireAuth:     config.GrpcRequireAuth,
+		reflection:      config.GrpcReflection,
+		healthInterval:  DefaultHealthCheckInterval,
 		idleConnsClosed: make(chan struct{}), // способ определить незавершенные соединения
 		masterCtx:       ctx,
 	}
 
+	if config.GrpcCertFile != "" || config.GrpcKeyFile != "" {
+		srv.tls = &TLSConfig{
+			CertFile:          config.GrpcCertFile,
+			KeyFile:           config.GrpcKeyFile,
+			ClientCA:          config.GrpcClientCA,
+			RequireClientCert: config.GrpcRequireClientCert,
+		}
+	}
+
 	for _, opt := range opts {
 		opt(srv)
 	}
@@ -44,13 +92,17 @@ func NewAPIServer(ctx context.Context, config *configs.APIServer, opts ...APISer
 }
 
 func (srv *APIServer) Run() error {
+	opts, err := srv.serverOptions()
+	if err != nil {
+		return err
+	}
+
 	listener, err := net.Listen("tcp", srv.Address)
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 		return err
 	}
 
-	var opts []grpc.ServerOption
 	grpcServer := grpc.NewServer(opts...)
 
 	// Сначала монтируем ресуры
@@ -60,7 +112,11 @@ func (srv *APIServer) Run() error {
 	srv.registerServices(grpcServer)
 
 	go srv.GracefulShutdown(grpcServer)
-	log.Printf("[INFO] serving GRPC on \"%s\"", srv.Address)
+	if srv.tls != nil {
+		log.Printf("[INFO] serving GRPC with TLS on \"%s\"", srv.Address)
+	} else {
+		log.Printf("[INFO] serving GRPC on \"%s\"", srv.Address)
+	}
 	if err := grpcServer.Serve(listener); err != nil {
 		return err
 	}
@@ -68,21 +124,112 @@ func (srv *APIServer) Run() error {
 	return nil
 }
 
+// serverOptions собирает TLS учетные данные и цепочки перехватчиков.
+func (srv *APIServer) serverOptions() ([]grpc.ServerOption, error) {
+	unary, stream, err := srv.interceptors()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	}
+
+	if srv.tls != nil {
+		creds, err := srv.tls.credentials()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	return opts, nil
+}
+
+// interceptors возвращает перехватчики в порядке вызова: трассировка,
+// идентификатор запроса, восстановление после паники, метрики,
+// аутентификация, журналирование и проверка прав.
+func (srv *APIServer) interceptors() ([]grpc.UnaryServerInterceptor, []grpc.StreamServerInterceptor, error) {
+	clients, err := interceptors.ParseClients(srv.clients)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certs, err := interceptors.ParseCertIdentities(srv.certs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	authenticator := interceptors.NewAuthenticator(srv.authManager, clients).WithCertificates(certs)
+	// проверки Kubernetes выполняются без учетных данных
+	perms := interceptors.NewPermissions(srv.authManager.PermissionsManager(), methodPermissions, srv.requireAuth).
+		WithPublic(healthpb.Health_ServiceDesc.ServiceName)
+
+	unary := []grpc.UnaryServerInterceptor{interceptors.TracingUnary(), interceptors.RequestIDUnary(), interceptors.RecoveryUnary()}
+	stream := []grpc.StreamServerInterceptor{interceptors.TracingStream(), interceptors.RequestIDStream(), interceptors.RecoveryStream()}
+
+	if srv.metrics != nil {
+		unary = append(unary, srv.metrics.Unary())
+		stream = append(stream, srv.metrics.Stream())
+	}
+
+	unary = append(unary, authenticator.Unary(), interceptors.LoggingUnary(), perms.Unary())
+	stream = append(stream, authenticator.Stream(), interceptors.LoggingStream(), perms.Stream())
+
+	return unary, stream, nil
+}
+
+// InProcess возвращает реализацию сервиса SSO и цепочку unary перехватчиков
+// сервера для вызова методов внутри процесса, например из REST шлюза.
+// Учетные данные клиента передаются во входящих метаданных контекста.
+func (srv *APIServer) InProcess() (protobuf.SSOServer, grpc.UnaryServerInterceptor, error) {
+	unary, _, err := srv.interceptors()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	srv.setupResources()
+
+	return srv.server, interceptors.ChainUnary(unary...), nil
+}
+
 // setupResources монтирует необходимые grpc-ресурсы для
-// обработки клиентских запросов
+// обработки клиентских запросов. Ресурсы создаются один раз и общие
+// для gRPC сервера и REST шлюза.
 func (srv *APIServer) setupResources() {
+	if srv.server != nil {
+		return
+	}
+
 	srv.verifyMan = resources.NewVerify(srv.db)
+	srv.server = resources.NewSSO(
+		srv.verifyMan,
+		resources.NewTokens(srv.authManager),
+		resources.NewUsers(srv.authManager.Users()),
+		resources.NewWatch(srv.masterCtx, srv.authManager.Users()),
+	)
 }
 
 // registerServices регистрирует все необходимые сервисы для работы grpc сервера
 func (srv *APIServer) registerServices(grpcServer *grpc.Server) {
-	protobuf.RegisterSSOServer(grpcServer, srv.verifyMan)
+	protobuf.RegisterSSOServer(grpcServer, srv.server)
+
+	srv.health = health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, srv.health)
+	go srv.watchHealth(srv.health)
+
+	if srv.reflection {
+		reflection.Register(grpcServer)
+	}
 }
 
 // GracefulShutdown обрабатывает все оставшиеся соединения до остановки
 func (srv *APIServer) GracefulShutdown(grpcServer *grpc.Server) {
 	<-srv.masterCtx.Done()
 	log.Printf("[INFO] shutting down gRPC server")
+	// клиенты health-check узнают об остановке до закрытия соединений
+	srv.health.Shutdown()
 	grpcServer.GracefulStop()
 	close(srv.idleConnsClosed)
 }
@@ -0,0 +1,43 @@
+package grpc
+
+import (
+	"log"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// DefaultHealthCheckInterval период проверки работоспособности для grpc.health.v1.
+const DefaultHealthCheckInterval = time.Second * 10
+
+// watchHealth обновляет статусы grpc.health.v1 по результатам тех же
+// проверок, что и /health, до остановки сервера.
+func (srv *APIServer) watchHealth(hs *health.Server) {
+	ticker := time.NewTicker(srv.healthInterval)
+	defer ticker.Stop()
+
+	for {
+		srv.updateHealth(hs)
+
+		select {
+		case <-srv.masterCtx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (srv *APIServer) updateHealth(hs *health.Server) {
+	status := healthpb.HealthCheckResponse_SERVING
+	if srv.monitoringManager != nil {
+		if err := srv.monitoringManager.Health().Check(); err != nil {
+			log.Printf("[WARN] gRPC health check failed: %v", err)
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+	}
+
+	// пустое имя - состояние сервера в целом
+	hs.SetServingStatus("", status)
+	hs.SetServingStatus(ssoServiceName, status)
+}
@@ -0,0 +1,25 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestAPIServer_UpdateHealth(t *testing.T) {
+	srv := &APIServer{}
+	hs := health.NewServer()
+	srv.updateHealth(hs)
+
+	for _, service := range []string{"", ssoServiceName} {
+		resp, err := hs.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatalf("%q: %v", service, err)
+		}
+		if resp.Status != healthpb.HealthCheckResponse_SERVING {
+			t.Errorf("%q: got %v, want SERVING", service, resp.Status)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package interceptors
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"strings"
+
+	"github.com/JetBrainer/sso/internal/domain/manager/auth"
+	"github.com/JetBrainer/sso/pkg/logger"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	bearerPrefix = "bearer "
+	basicPrefix  = "basic "
+)
+
+var ErrInvalidClientCredentials = errors.New("invalid client credentials")
+
+// Client учетные данные сервиса, вызывающего gRPC методы от своего имени.
+// Права клиента определяются его ролями так же, как права пользователя.
+type Client struct {
+	ID     string
+	Secret string
+	Roles  []string
+}
+
+// ParseClients разбирает описания клиентов в формате "id:secret:role1|role2".
+func ParseClients(specs []string) ([]Client, error) {
+	clients := make([]Client, 0, len(specs))
+	for _, spec := range specs {
+		parts := strings.SplitN(strings.TrimSpace(spec), ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			return nil, errors.Errorf("invalid gRPC client %q, expected id:secret:role1|role2", parts[0])
+		}
+
+		clients = append(clients, Client{
+			ID:     parts[0],
+			Secret: parts[1],
+			Roles:  strings.Split(parts[2], "|"),
+		})
+	}
+
+	return clients, nil
+}
+
+// ParseCertIdentities разбирает сопоставления клиентских сертификатов с
+// ролями в формате "common-name:role1|role2".
+func ParseCertIdentities(specs []string) (map[string][]string, error) {
+	identities := make(map[string][]string, len(specs))
+	for _, spec := range specs {
+		i := strings.LastIndexByte(spec, ':')
+		if i < 1 || i == len(spec)-1 {
+			return nil, errors.Errorf("invalid gRPC certificate identity %q, expected common-name:role1|role2", spec)
+		}
+
+		identities[strings.TrimSpace(spec[:i])] = strings.Split(spec[i+1:], "|")
+	}
+
+	return identities, nil
+}
+
+// Authenticator определяет вызывающего по метаданным "authorization":
+// "Bearer <access token>" пользователя или "Basic base64(id:secret)" клиента,
+// а при их отсутствии - по проверенному клиентскому TLS сертификату.
+// Вызов без учетных данных пропускается анонимным, неверные учетные данные
+// отклоняются с codes.Unauthenticated.
+type Authenticator struct {
+	authManager *auth.Manager
+	clients     map[string]Client
+	certs       map[string][]string // CN сертификата -> роли
+}
+
+func NewAuthenticator(authManager *auth.Manager, clients []Client) *Authenticator {
+	a := &Authenticator{
+		authManager: authManager,
+		clients:     make(map[string]Client, len(clients)),
+	}
+	for _, c := range clients {
+		a.clients[c.ID] = c
+	}
+
+	return a
+}
+
+// WithCertificates задает роли сервисов, предъявляющих клиентский сертификат
+// с указанным CN. Сертификаты с другим CN не дают прав.
+func (a *Authenticator) WithCertificates(identities map[string][]string) *Authenticator {
+	a.certs = identities
+	return a
+}
+
+func (a *Authenticator) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		authCtx, err := a.authenticate(ctx)
+		if err != nil {
+			logger.Printf(ctx, "[WARN] gRPC %s: %v", info.FullMethod, err)
+			return nil, err
+		}
+
+		return handler(authCtx, req)
+	}
+}
+
+func (a *Authenticator) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authenticate(ss.Context())
+		if err != nil {
+			logger.Printf(ss.Context(), "[WARN] gRPC %s: %v", info.FullMethod, err)
+			return err
+		}
+
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticate добавляет в контекст вызывающего, если он предъявил учетные данные.
+func (a *Authenticator) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	for _, value := range md.Get("authorization") {
+		scheme := strings.ToLower(value)
+
+		switch {
+		case strings.HasPrefix(scheme, bearerPrefix):
+			claims, err := a.authManager.ParseAccessToken(value[len(bearerPrefix):])
+			if err != nil {
+				return nil, status.Error(codes.Unauthenticated, err.Error())
+			}
+
+			return withIdentity(ctx, &Identity{Kind: IdentityUser, Subject: claims.TDID, Roles: claims.Roles}), nil
+		case strings.HasPrefix(scheme, basicPrefix):
+			client, err := a.client(value[len(basicPrefix):])
+			if err != nil {
+				return nil, status.Error(codes.Unauthenticated, err.Error())
+			}
+
+			return withIdentity(ctx, &Identity{Kind: IdentityClient, Subject: client.ID, Roles: client.Roles}), nil
+		}
+	}
+
+	if identity := a.certificate(ctx); identity != nil {
+		return withIdentity(ctx, identity), nil
+	}
+
+	return ctx, nil
+}
+
+// certificate возвращает сервис, предъявивший проверенный клиентский
+// сертификат с известным CN.
+func (a *Authenticator) certificate(ctx context.Context) *Identity {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return nil
+	}
+
+	subject := tlsInfo.State.VerifiedChains[0][0].Subject.CommonName
+	roles, ok := a.certs[subject]
+	if !ok {
+		return nil
+	}
+
+	return &Identity{Kind: IdentityCertificate, Subject: subject, Roles: roles}
+}
+
+// client проверяет учетные данные клиента base64(id:secret).
+func (a *Authenticator) client(credentials string) (*Client, error) {
+	decoded, err := base64.StdEncoding.DecodeString(credentials)
+	if err != nil {
+		return nil, ErrInvalidClientCredentials
+	}
+
+	parts := strings.SplitN(string(decoded), ":", 2)
+	if len(parts) != 2 {
+		return nil, ErrInvalidClientCredentials
+	}
+
+	client, ok := a.clients[parts[0]]
+	if !ok || subtle.ConstantTimeCompare([]byte(client.Secret), []byte(parts[1])) != 1 {
+		return nil, ErrInvalidClientCredentials
+	}
+
+	return &client, nil
+}
+
+// serverStream подменяет контекст потока.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,122 @@
+package interceptors
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/domain/manager/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthenticator(t *testing.T) {
+	authManager := auth.New(nil, []byte("key"), time.Hour, time.Hour, time.Hour)
+	clients, err := ParseClients([]string{"orders:secret:service|users-reader"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := NewAuthenticator(authManager, clients)
+
+	claims := &auth.Claims{TDID: "tdid", Roles: []string{"admin"}}
+	claims.ExpiresAt = time.Now().Add(time.Minute).Unix()
+	_, token, err := authManager.TokenAuth().Encode(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	basic := func(credentials string) string {
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		code          codes.Code
+		identity      *Identity
+	}{
+		{"anonymous", "", codes.OK, nil},
+		{"user", "Bearer " + token, codes.OK, &Identity{Kind: IdentityUser, Subject: "tdid"}},
+		{"invalid token", "Bearer token", codes.Unauthenticated, nil},
+		{"client", basic("orders:secret"), codes.OK, &Identity{Kind: IdentityClient, Subject: "orders"}},
+		{"invalid secret", basic("orders:wrong"), codes.Unauthenticated, nil},
+		{"unknown client", basic("billing:secret"), codes.Unauthenticated, nil},
+	}
+
+	for _, tt := range tests {
+		ctx := context.Background()
+		if tt.authorization != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tt.authorization))
+		}
+
+		var got *Identity
+		_, err := a.Unary()(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			got, _ = IdentityFromContext(ctx)
+			return nil, nil
+		})
+
+		if code := status.Code(err); code != tt.code {
+			t.Errorf("%s: got code %v, want %v", tt.name, code, tt.code)
+			continue
+		}
+		if (got == nil) != (tt.identity == nil) {
+			t.Errorf("%s: got identity %v, want %v", tt.name, got, tt.identity)
+			continue
+		}
+		if got != nil && (got.Kind != tt.identity.Kind || got.Subject != tt.identity.Subject) {
+			t.Errorf("%s: got identity %v, want %v", tt.name, got, tt.identity)
+		}
+	}
+}
+
+func TestParseClients(t *testing.T) {
+	if _, err := ParseClients([]string{"orders:secret"}); err == nil {
+		t.Error("expected error for client without roles")
+	}
+}
+
+func TestRecoveryUnary(t *testing.T) {
+	_, err := RecoveryUnary()(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/test"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		panic("boom")
+	})
+
+	if code := status.Code(err); code != codes.Internal {
+		t.Errorf("got code %v, want %v", code, codes.Internal)
+	}
+}
+
+func TestAuthenticator_Certificate(t *testing.T) {
+	certs, err := ParseCertIdentities([]string{"orders.svc:service"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := NewAuthenticator(nil, nil).WithCertificates(certs)
+
+	peerWith := func(cn string) context.Context {
+		cert := &x509.Certificate{Subject: pkix.Name{CommonName: cn}}
+		state := tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+		return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{State: state}})
+	}
+
+	ctx, err := a.authenticate(peerWith("orders.svc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	identity, ok := IdentityFromContext(ctx)
+	if !ok || identity.Kind != IdentityCertificate || identity.Subject != "orders.svc" || identity.Roles[0] != "service" {
+		t.Errorf("unexpected identity: %v", identity)
+	}
+
+	ctx, _ = a.authenticate(peerWith("unknown.svc"))
+	if _, ok := IdentityFromContext(ctx); ok {
+		t.Error("certificate with unknown subject must not be authenticated")
+	}
+}
@@ -0,0 +1,29 @@
+package interceptors
+
+import "context"
+
+// Виды аутентифицированных вызывающих.
+const (
+	IdentityUser        = "user"        // пользователь с access токеном
+	IdentityClient      = "client"      // сервис с учетными данными клиента
+	IdentityCertificate = "certificate" // сервис с клиентским TLS сертификатом
+)
+
+// Identity аутентифицированный вызывающий gRPC метода.
+type Identity struct {
+	Kind    string
+	Subject string // TDID пользователя, идентификатор клиента или CN сертификата
+	Roles   []string
+}
+
+type identityCtxKey struct{}
+
+func withIdentity(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityCtxKey{}, identity)
+}
+
+// IdentityFromContext возвращает вызывающего, если он предъявил учетные данные.
+func IdentityFromContext(ctx context.Context) (*Identity, bool) {
+	identity, ok := ctx.Value(identityCtxKey{}).(*Identity)
+	return identity, ok
+}
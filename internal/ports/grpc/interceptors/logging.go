@@ -0,0 +1,102 @@
+package interceptors
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/JetBrainer/sso/pkg/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// metadataRequestID - ключ метаданных с идентификатором запроса.
+const metadataRequestID = "x-request-id"
+
+// requestIDRegexp ограничивает идентификаторы, принимаемые от клиента.
+var requestIDRegexp = regexp.MustCompile(`^[A-Za-z0-9._:/-]{1,128}$`)
+
+// RequestIDUnary сохраняет идентификатор запроса в контексте для журнала и
+// возвращает его в заголовке ответа x-request-id. Идентификатор берется из
+// контекста (вызов через REST шлюз), метаданных клиента или генерируется.
+func RequestIDUnary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(withRequestID(ctx), req)
+	}
+}
+
+// RequestIDStream сохраняет идентификатор запроса в контексте потока.
+func RequestIDStream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &serverStream{ServerStream: ss, ctx: withRequestID(ss.Context())})
+	}
+}
+
+func withRequestID(ctx context.Context) context.Context {
+	if logger.RequestID(ctx) != "" {
+		return ctx
+	}
+
+	id := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(metadataRequestID); len(values) > 0 && requestIDRegexp.MatchString(values[0]) {
+			id = values[0]
+		}
+	}
+	if id == "" {
+		id = logger.NewRequestID()
+	}
+
+	// вне gRPC транспорта заголовки не отправляются, ошибка не важна
+	_ = grpc.SetHeader(ctx, metadata.Pairs(metadataRequestID, id))
+
+	return logger.WithRequestID(ctx, id)
+}
+
+// LoggingUnary журналирует вызовы методов с кодом ответа и длительностью.
+func LoggingUnary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logCall(ctx, info.FullMethod, err, time.Since(start))
+
+		return resp, err
+	}
+}
+
+// LoggingStream журналирует завершение потоков с кодом ответа и длительностью.
+func LoggingStream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		logCall(ss.Context(), info.FullMethod, err, time.Since(start))
+
+		return err
+	}
+}
+
+func logCall(ctx context.Context, method string, err error, elapsed time.Duration) {
+	code := status.Code(err)
+
+	level := "[INFO]"
+	switch code {
+	case codes.Internal, codes.Unknown, codes.DataLoss:
+		level = "[ERROR]"
+	case codes.PermissionDenied, codes.ResourceExhausted, codes.Unavailable, codes.DeadlineExceeded:
+		level = "[WARN]"
+	}
+
+	caller := "anonymous"
+	if identity, ok := IdentityFromContext(ctx); ok {
+		caller = identity.Kind + ":" + identity.Subject
+	}
+
+	if err != nil {
+		logger.Printf(ctx, "%s gRPC %s %s %s %s: %v", level, method, code, elapsed, caller, status.Convert(err).Message())
+		return
+	}
+
+	logger.Printf(ctx, "%s gRPC %s %s %s %s", level, method, code, elapsed, caller)
+}
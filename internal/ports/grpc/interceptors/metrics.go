@@ -0,0 +1,60 @@
+package interceptors
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Metrics содержит метрики Prometheus по каждому gRPC методу.
+type Metrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "sso_grpc_requests_total",
+			Help: "Total number of gRPC requests by method and status code.",
+		}, []string{"method", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "sso_grpc_request_duration_seconds",
+			Help:    "gRPC request latency by method.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method"}),
+	}
+}
+
+// Collectors возвращает метрики для регистрации в Prometheus.
+func (m *Metrics) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{m.requests, m.duration}
+}
+
+func (m *Metrics) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		m.observe(info.FullMethod, err, time.Since(start))
+
+		return resp, err
+	}
+}
+
+func (m *Metrics) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		m.observe(info.FullMethod, err, time.Since(start))
+
+		return err
+	}
+}
+
+func (m *Metrics) observe(method string, err error, elapsed time.Duration) {
+	m.requests.WithLabelValues(method, status.Code(err).String()).Inc()
+	m.duration.WithLabelValues(method).Observe(elapsed.Seconds())
+}
@@ -0,0 +1,100 @@
+package interceptors
+
+import (
+	"context"
+	"strings"
+
+	"github.com/JetBrainer/sso/internal/domain/manager/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Permissions проверяет права вызывающего, определенного Authenticator.
+// Для методов из карты methods требуется право, указанное в ней, остальные
+// методы доступны анонимно, если не включен режим requireAuth.
+type Permissions struct {
+	permissions *auth.PermissionsManager
+	methods     map[string]string // полное имя метода -> требуемое право
+	requireAuth bool              // все методы требуют учетных данных
+	public      map[string]bool   // сервисы, доступные без учетных данных в режиме requireAuth
+}
+
+func NewPermissions(pm *auth.PermissionsManager, methods map[string]string, requireAuth bool) *Permissions {
+	return &Permissions{
+		permissions: pm,
+		methods:     methods,
+		requireAuth: requireAuth,
+	}
+}
+
+// WithPublic открывает методы сервисов services для анонимных вызовов в
+// режиме requireAuth. Права из карты methods для них по-прежнему проверяются.
+func (p *Permissions) WithPublic(services ...string) *Permissions {
+	if p.public == nil {
+		p.public = make(map[string]bool, len(services))
+	}
+	for _, s := range services {
+		p.public[s] = true
+	}
+
+	return p
+}
+
+func (p *Permissions) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := p.authorize(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func (p *Permissions) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := p.authorize(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+// authorize возвращает статусную ошибку gRPC, если вызов метода запрещен.
+func (p *Permissions) authorize(ctx context.Context, method string) error {
+	permission, restricted := p.methods[method]
+	if !restricted && (!p.requireAuth || p.public[service(method)]) {
+		return nil
+	}
+
+	identity, ok := IdentityFromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "credentials not specified")
+	}
+
+	if !restricted {
+		return nil
+	}
+
+	allowed, err := p.permissions.HasPermission(ctx, identity.Roles, permission)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	if !allowed {
+		return status.Error(codes.PermissionDenied, "permission denied")
+	}
+
+	return nil
+}
+
+// service возвращает имя сервиса из полного имени метода "/service/method".
+func service(method string) string {
+	method = strings.TrimPrefix(method, "/")
+	if i := strings.LastIndexByte(method, '/'); i >= 0 {
+		return method[:i]
+	}
+
+	return method
+}
@@ -0,0 +1,29 @@
+package interceptors
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPermissions_RequireAuth(t *testing.T) {
+	p := NewPermissions(nil, nil, true).WithPublic("grpc.health.v1.Health")
+
+	tests := []struct {
+		method string
+		ctx    context.Context
+		code   codes.Code
+	}{
+		{"/sso.pkg.go.SSO/UserToken", context.Background(), codes.Unauthenticated},
+		{"/sso.pkg.go.SSO/UserToken", withIdentity(context.Background(), &Identity{Kind: IdentityClient}), codes.OK},
+		{"/grpc.health.v1.Health/Check", context.Background(), codes.OK},
+	}
+
+	for _, tt := range tests {
+		if got := status.Code(p.authorize(tt.ctx, tt.method)); got != tt.code {
+			t.Errorf("%s: got %v, want %v", tt.method, got, tt.code)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package interceptors
+
+import (
+	"context"
+	"runtime/debug"
+
+	"github.com/JetBrainer/sso/pkg/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RecoveryUnary перехватывает панику обработчика и возвращает codes.Internal.
+func RecoveryUnary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(ctx, info.FullMethod, r)
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// RecoveryStream перехватывает панику обработчика потока и возвращает codes.Internal.
+func RecoveryStream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(ss.Context(), info.FullMethod, r)
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
+
+func recovered(ctx context.Context, method string, r interface{}) error {
+	logger.Printf(ctx, "[ERROR] gRPC %s panic: %v\n%s", method, r, debug.Stack())
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,105 @@
+package interceptors
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const tracerName = "github.com/JetBrainer/sso/internal/ports/grpc"
+
+// TracingUnary создает серверный спан на каждый вызов. Контекст трассировки
+// вызывающего принимается из метаданных traceparent и tracestate.
+func TracingUnary() grpc.UnaryServerInterceptor {
+	tracer := otel.Tracer(tracerName)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, span := startSpan(ctx, tracer, info.FullMethod)
+		defer span.End()
+
+		resp, err := handler(ctx, req)
+		endSpan(span, err)
+
+		return resp, err
+	}
+}
+
+// TracingStream создает серверный спан на каждый поток.
+func TracingStream() grpc.StreamServerInterceptor {
+	tracer := otel.Tracer(tracerName)
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, span := startSpan(ss.Context(), tracer, info.FullMethod)
+		defer span.End()
+
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		endSpan(span, err)
+
+		return err
+	}
+}
+
+func startSpan(ctx context.Context, tracer trace.Tracer, fullMethod string) (context.Context, trace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+
+	// полное имя метода имеет вид /<сервис>/<метод>
+	service, method := "", strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(method, "/"); i >= 0 {
+		service, method = method[:i], method[i+1:]
+	}
+
+	return tracer.Start(ctx, strings.TrimPrefix(fullMethod, "/"),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.RPCSystemKey.String("grpc"),
+			semconv.RPCServiceKey.String(service),
+			semconv.RPCMethodKey.String(method),
+		),
+	)
+}
+
+func endSpan(span trace.Span, err error) {
+	st := status.Convert(err)
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(st.Code())))
+
+	switch st.Code() {
+	case codes.OK, codes.NotFound, codes.InvalidArgument, codes.AlreadyExists, codes.Unauthenticated,
+		codes.PermissionDenied, codes.FailedPrecondition, codes.OutOfRange, codes.Canceled:
+		// ошибки вызывающего не отмечают спан ошибочным
+	default:
+		span.SetStatus(otelcodes.Error, st.Message())
+	}
+}
+
+// metadataCarrier позволяет пропагатору читать метаданные gRPC.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
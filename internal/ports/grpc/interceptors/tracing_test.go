@@ -0,0 +1,47 @@
+package interceptors
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestTracingUnary(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"traceparent", "00-"+traceID+"-00f067aa0ba902b7-01",
+	))
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/sso.pkg.go.SSO/GetUser"}
+	_, _ = TracingUnary()(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.Internal, "boom")
+	})
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+
+	span := spans[0]
+	if span.Name() != "sso.pkg.go.SSO/GetUser" || span.SpanContext().TraceID().String() != traceID {
+		t.Errorf("span %s in trace %s", span.Name(), span.SpanContext().TraceID())
+	}
+	if span.Parent().SpanID().String() != "00f067aa0ba902b7" || !span.Parent().IsRemote() {
+		t.Errorf("parent %v", span.Parent())
+	}
+	if span.Status().Description != "boom" {
+		t.Errorf("status %+v", span.Status())
+	}
+}
@@ -3,6 +3,8 @@ package grpc
 import (
 	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
 	"github.com/JetBrainer/sso/internal/domain/manager/auth"
+	"github.com/JetBrainer/sso/internal/domain/manager/monitoring"
+	"github.com/JetBrainer/sso/internal/ports/grpc/interceptors"
 	"github.com/JetBrainer/sso/internal/ports/grpc/resources"
 )
 
@@ -14,6 +16,20 @@ func WithAuthManager(authMan *auth.Manager) APIServerOption {
 	}
 }
 
+// WithMetrics включает метрики Prometheus по каждому gRPC методу.
+func WithMetrics(metrics *interceptors.Metrics) APIServerOption {
+	return func(srv *APIServer) {
+		srv.metrics = metrics
+	}
+}
+
+// WithMonitoringManager задает проверки работоспособности для grpc.health.v1.
+func WithMonitoringManager(m *monitoring.Manager) APIServerOption {
+	return func(srv *APIServer) {
+		srv.monitoringManager = m
+	}
+}
+
 func WithVersion(version string) APIServerOption {
 	return func(srv *APIServer) {
 		srv.version = version
@@ -4,7 +4,7 @@ import (
 	"context"
 
 	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
-	"github.com/Somatic-KZ/sso-client/protobuf"
+	"github.com/JetBrainer/sso/pkg/protobuf"
 	"github.com/golang/protobuf/ptypes/empty"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -0,0 +1,22 @@
+package resources
+
+import "github.com/JetBrainer/sso/pkg/protobuf"
+
+// SSO объединяет ресурсы в реализацию сервиса protobuf.SSOServer.
+type SSO struct {
+	*Verify
+	*Tokens
+	*Users
+	*Watch
+}
+
+var _ protobuf.SSOServer = (*SSO)(nil)
+
+func NewSSO(verify *Verify, tokens *Tokens, users *Users, watch *Watch) *SSO {
+	return &SSO{
+		Verify: verify,
+		Tokens: tokens,
+		Users:  users,
+		Watch:  watch,
+	}
+}
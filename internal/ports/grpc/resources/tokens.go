@@ -0,0 +1,82 @@
+package resources
+
+import (
+	"context"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	"github.com/JetBrainer/sso/internal/domain/manager/auth"
+	"github.com/JetBrainer/sso/pkg/protobuf"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Tokens проверяет access токены по запросу других сервисов.
+type Tokens struct {
+	authManager *auth.Manager
+}
+
+func NewTokens(authManager *auth.Manager) *Tokens {
+	return &Tokens{authManager: authManager}
+}
+
+// ValidateAccessToken проверяет подпись и срок действия access токена и
+// возвращает его claims вместе с правами, выданными ролям пользователя.
+// Токены удаленных и выключенных пользователей считаются недействительными.
+func (t *Tokens) ValidateAccessToken(ctx context.Context, req *protobuf.ValidateAccessTokenRequest) (*protobuf.ValidateAccessTokenResponse, error) {
+	if req.Token == "" {
+		return nil, status.Error(codes.InvalidArgument, "token not specified")
+	}
+
+	claims, err := t.authManager.ParseAccessToken(req.Token)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if err := t.checkUser(claims.TDID); err != nil {
+		return nil, err
+	}
+
+	permissions, err := t.authManager.PermissionsManager().ByRoles(ctx, claims.Roles)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &protobuf.ValidateAccessTokenResponse{
+		Tdid:        claims.TDID,
+		Roles:       claims.Roles,
+		Permissions: permissions,
+		IssuedAt:    unixTimestamp(claims.IssuedAt),
+		ExpiresAt:   unixTimestamp(claims.ExpiresAt),
+	}, nil
+}
+
+// checkUser проверяет, что владелец токена существует и включен.
+func (t *Tokens) checkUser(rawTDID string) error {
+	tdid, err := primitive.ObjectIDFromHex(rawTDID)
+	if err != nil {
+		return status.Error(codes.Unauthenticated, "invalid tdid")
+	}
+
+	user, err := t.authManager.Users().ByTDID(tdid)
+	switch {
+	case err == auth.ErrUserDoesNotExist, err == drivers.ErrUserDoesNotExist:
+		return status.Error(codes.Unauthenticated, err.Error())
+	case err != nil:
+		return status.Error(codes.Internal, err.Error())
+	case !user.Enabled:
+		return status.Error(codes.Unauthenticated, auth.ErrUserDisabled.Error())
+	}
+
+	return nil
+}
+
+func unixTimestamp(sec int64) *timestamppb.Timestamp {
+	if sec == 0 {
+		return nil
+	}
+
+	return timestamppb.New(time.Unix(sec, 0))
+}
@@ -0,0 +1,65 @@
+package resources
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	"github.com/JetBrainer/sso/internal/domain/manager/auth"
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/JetBrainer/sso/pkg/protobuf"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// tokenStore хранит пользователей по TDID.
+type tokenStore struct {
+	drivers.DataStore
+	users map[primitive.ObjectID]*models.User
+}
+
+func (s *tokenStore) UserByTDID(_ context.Context, tdid primitive.ObjectID) (*models.User, error) {
+	user, ok := s.users[tdid]
+	if !ok {
+		return nil, drivers.ErrUserDoesNotExist
+	}
+
+	return user, nil
+}
+
+func TestTokens_ValidateAccessToken(t *testing.T) {
+	ctx := context.Background()
+	user := &models.User{ID: primitive.NewObjectID(), Enabled: true}
+	store := &tokenStore{users: map[primitive.ObjectID]*models.User{user.ID: user}}
+	manager := auth.New(store, []byte("secret"), time.Minute, time.Hour, time.Minute)
+	tokens := NewTokens(manager)
+
+	token, err := manager.NewAccessToken(ctx, user.ID.Hex())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resp, err := tokens.ValidateAccessToken(ctx, &protobuf.ValidateAccessTokenRequest{Token: token})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Tdid != user.ID.Hex() {
+		t.Errorf("tdid: got %s, want %s", resp.Tdid, user.ID.Hex())
+	}
+
+	// токен выключенного пользователя недействителен
+	user.Enabled = false
+	_, err = tokens.ValidateAccessToken(ctx, &protobuf.ValidateAccessTokenRequest{Token: token})
+	if got := status.Code(err); got != codes.Unauthenticated {
+		t.Errorf("disabled user: got %v, want %v", got, codes.Unauthenticated)
+	}
+
+	// как и токен удаленного
+	delete(store.users, user.ID)
+	_, err = tokens.ValidateAccessToken(ctx, &protobuf.ValidateAccessTokenRequest{Token: token})
+	if got := status.Code(err); got != codes.Unauthenticated {
+		t.Errorf("deleted user: got %v, want %v", got, codes.Unauthenticated)
+	}
+}
@@ -0,0 +1,208 @@
+package resources
+
+import (
+	"context"
+	"errors"
+
+	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	"github.com/JetBrainer/sso/internal/domain/manager/auth"
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/JetBrainer/sso/pkg/protobuf"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// MaxTDIDList ограничивает количество TDID в одном запросе.
+const MaxTDIDList = 500
+
+// Users отдает данные пользователей другим сервисам.
+type Users struct {
+	users *auth.Users
+}
+
+func NewUsers(users *auth.Users) *Users {
+	return &Users{users: users}
+}
+
+func (u *Users) GetUser(ctx context.Context, req *protobuf.GetUserRequest) (*protobuf.User, error) {
+	user, err := u.byTDID(req.Tdid)
+	if err != nil {
+		return nil, err
+	}
+
+	return userToProto(user.GetShortInfo()), nil
+}
+
+func (u *Users) GetUsers(ctx context.Context, req *protobuf.GetUsersRequest) (*protobuf.GetUsersResponse, error) {
+	if err := validateTDIDList(req.Tdid); err != nil {
+		return nil, err
+	}
+
+	users, err := u.users.ListByTDID(ctx, req.Tdid)
+	if err != nil {
+		return nil, usersError(err)
+	}
+
+	resp := &protobuf.GetUsersResponse{Users: make([]*protobuf.User, 0, len(users))}
+	for _, user := range users {
+		resp.Users = append(resp.Users, userToProto(user.GetShortInfo()))
+	}
+
+	return resp, nil
+}
+
+func (u *Users) FullNamesByTDID(ctx context.Context, req *protobuf.FullNamesByTDIDRequest) (*protobuf.FullNamesByTDIDResponse, error) {
+	if err := validateTDIDList(req.Tdid); err != nil {
+		return nil, err
+	}
+
+	fullNames, err := u.users.FullNamesByTDID(ctx, req.Tdid)
+	if err != nil {
+		return nil, usersError(err)
+	}
+
+	return &protobuf.FullNamesByTDIDResponse{FullNames: fullNames}, nil
+}
+
+func (u *Users) UserDevices(ctx context.Context, req *protobuf.UserDevicesRequest) (*protobuf.UserDevicesResponse, error) {
+	tdid, err := primitive.ObjectIDFromHex(req.Tdid)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid tdid")
+	}
+
+	devices, err := u.users.DevicesByTDID(ctx, tdid)
+	if err != nil {
+		return nil, usersError(err)
+	}
+
+	resp := &protobuf.UserDevicesResponse{Devices: make([]*protobuf.Device, 0, len(devices))}
+	for _, d := range devices {
+		resp.Devices = append(resp.Devices, &protobuf.Device{Id: d.ID, Os: d.OS})
+	}
+
+	return resp, nil
+}
+
+func (u *Users) UserReceivers(ctx context.Context, req *protobuf.UserReceiversRequest) (*protobuf.UserReceiversResponse, error) {
+	user, err := u.byTDID(req.Tdid)
+	if err != nil {
+		return nil, err
+	}
+
+	return &protobuf.UserReceiversResponse{Receivers: receiversToProto(user.Receivers)}, nil
+}
+
+// byTDID находит пользователя и возвращает статусную ошибку gRPC, если это
+// невозможно.
+func (u *Users) byTDID(rawTDID string) (*models.User, error) {
+	tdid, err := primitive.ObjectIDFromHex(rawTDID)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid tdid")
+	}
+
+	user, err := u.users.ByTDID(tdid)
+	if err != nil {
+		return nil, usersError(err)
+	}
+
+	return user, nil
+}
+
+func validateTDIDList(tdidList []string) error {
+	if len(tdidList) == 0 {
+		return status.Error(codes.InvalidArgument, "tdid list is empty")
+	}
+	if len(tdidList) > MaxTDIDList {
+		return status.Errorf(codes.InvalidArgument, "tdid list exceeds %d elements", MaxTDIDList)
+	}
+
+	return nil
+}
+
+// usersError сопоставляет ошибки менеджера пользователей с кодами gRPC.
+func usersError(err error) error {
+	switch {
+	case errors.Is(err, auth.ErrInvalidTdID):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case err == auth.ErrUserDoesNotExist, err == drivers.ErrUserDoesNotExist:
+		return status.Error(codes.NotFound, "user does not exist")
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func userToProto(u *models.UserShortInfo) *protobuf.User {
+	user := &protobuf.User{
+		Id:         u.ID.Hex(),
+		Phone:      u.Phone,
+		Phones:     u.Phones,
+		Email:      u.Email,
+		Firstname:  u.Firstname,
+		Lastname:   u.Lastname,
+		Patronymic: u.Patronymic,
+		Iin:        int64(u.IIN),
+		Sex:        u.Sex,
+		Roles:      u.Roles,
+		Receivers:  receiversToProto(u.Receivers),
+		Created:    timestamppb.New(u.Created),
+		Updated:    timestamppb.New(u.Updated),
+		Enabled:    u.Enabled,
+	}
+	if u.BirthDate != nil {
+		user.BirthDate = timestamppb.New(*u.BirthDate)
+	}
+
+	return user
+}
+
+func receiversToProto(receivers []models.Receiver) []*protobuf.Receiver {
+	result := make([]*protobuf.Receiver, 0, len(receivers))
+	for _, r := range receivers {
+		receiver := &protobuf.Receiver{
+			Id:              r.ID.Hex(),
+			Firstname:       r.FirstName,
+			Lastname:        r.LastName,
+			Email:           r.Email,
+			Phone:           r.PrimaryPhone,
+			AdditionalPhone: r.AdditionalPhone,
+			IsDefault:       r.IsDefault,
+			IsOrganization:  r.IsOrganization,
+		}
+
+		if r.Address != nil {
+			receiver.Address = &protobuf.ReceiverAddress{
+				Region: &protobuf.ReceiverRegion{
+					Code: r.Address.Region.Code,
+					Name: r.Address.Region.Name,
+					Id:   int64(r.Address.Region.ID),
+				},
+				City:      r.Address.City,
+				Street:    r.Address.Street,
+				House:     r.Address.House,
+				Floor:     r.Address.Floor,
+				Apartment: r.Address.Apartment,
+				Zipcode:   r.Address.Zipcode,
+				Geo: &protobuf.AddressGeo{
+					Lat: r.Address.Geo.Lat,
+					Lng: r.Address.Geo.Lng,
+				},
+			}
+		}
+
+		if r.Organization != nil {
+			receiver.Organization = &protobuf.Organization{
+				Name:    r.Organization.Name,
+				Bin:     r.Organization.BIN,
+				Bik:     r.Organization.BIK,
+				Iic:     r.Organization.IIC,
+				Address: r.Organization.Address,
+			}
+		}
+
+		result = append(result, receiver)
+	}
+
+	return result
+}
@@ -0,0 +1,63 @@
+package resources
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/domain/manager/auth"
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUserToProto(t *testing.T) {
+	birthDate := time.Date(1990, 1, 2, 0, 0, 0, 0, time.UTC)
+	user := models.User{
+		ID:        primitive.NewObjectID(),
+		FirstName: "Иван",
+		BirthDate: &birthDate,
+		Receivers: []models.Receiver{{
+			ID:           primitive.NewObjectID(),
+			Address:      &models.ReceiverAddress{City: "Алматы", Region: models.ReceiverRegion{ID: 2}},
+			Organization: &models.Organization{BIN: "123"},
+		}},
+		Enabled: true,
+	}
+
+	got := userToProto(user.GetShortInfo())
+	if got.Id != user.ID.Hex() || got.Firstname != "Иван" || !got.Enabled {
+		t.Fatalf("unexpected user: %v", got)
+	}
+	if !got.BirthDate.AsTime().Equal(birthDate) {
+		t.Errorf("birth date: got %v, want %v", got.BirthDate.AsTime(), birthDate)
+	}
+	if len(got.Receivers) != 1 {
+		t.Fatalf("receivers: got %d, want 1", len(got.Receivers))
+	}
+	r := got.Receivers[0]
+	if r.Address.City != "Алматы" || r.Address.Region.Id != 2 || r.Organization.Bin != "123" {
+		t.Errorf("unexpected receiver: %v", r)
+	}
+}
+
+func TestUsersError(t *testing.T) {
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{auth.ErrInvalidTdIDList("bad"), codes.InvalidArgument},
+		{auth.ErrUserDoesNotExist, codes.NotFound},
+		{auth.ErrInternalError, codes.Internal},
+	}
+
+	for _, tt := range tests {
+		if got := status.Code(usersError(tt.err)); got != tt.code {
+			t.Errorf("%v: got %v, want %v", tt.err, got, tt.code)
+		}
+	}
+
+	if got := status.Code(validateTDIDList(make([]string, MaxTDIDList+1))); got != codes.InvalidArgument {
+		t.Errorf("long list: got %v, want %v", got, codes.InvalidArgument)
+	}
+}
@@ -0,0 +1,75 @@
+package resources
+
+import (
+	"context"
+
+	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	"github.com/JetBrainer/sso/internal/domain/manager/auth"
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/JetBrainer/sso/pkg/protobuf"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Watch транслирует изменения пользователей в gRPC поток.
+type Watch struct {
+	users *auth.Users
+	// shutdown отменяется при остановке сервера, чтобы открытые потоки не
+	// задерживали GracefulStop
+	shutdown context.Context
+}
+
+func NewWatch(shutdown context.Context, users *auth.Users) *Watch {
+	return &Watch{
+		users:    users,
+		shutdown: shutdown,
+	}
+}
+
+func (w *Watch) WatchUsers(req *protobuf.WatchUsersRequest, stream protobuf.SSO_WatchUsersServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-w.shutdown.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	err := w.users.Watch(ctx, req.ResumeToken, func(change *models.UserChange) error {
+		return stream.Send(userChangeToProto(change))
+	})
+
+	switch {
+	case w.shutdown.Err() != nil:
+		return status.Error(codes.Unavailable, "server is shutting down, resume from the last received event")
+	case stream.Context().Err() != nil:
+		return status.Error(codes.Canceled, stream.Context().Err().Error())
+	case err == drivers.ErrInvalidResumeToken:
+		return status.Error(codes.OutOfRange, err.Error())
+	case err == auth.ErrWatcherLagging:
+		return status.Error(codes.ResourceExhausted, err.Error())
+	default:
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func userChangeToProto(change *models.UserChange) *protobuf.UserEvent {
+	event := &protobuf.UserEvent{
+		Type:        change.Type,
+		Tdid:        change.TDID.Hex(),
+		ResumeToken: change.ResumeToken,
+		Time:        timestamppb.New(change.Time),
+	}
+	if change.User != nil {
+		event.User = userToProto(change.User.GetShortInfo())
+	}
+
+	return event
+}
@@ -0,0 +1,56 @@
+package grpc
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/credentials"
+)
+
+// TLSConfig настройки TLS gRPC сервера.
+type TLSConfig struct {
+	CertFile string
+	KeyFile  string
+	// ClientCA - сертификаты центров, которыми проверяются клиентские
+	// сертификаты. Без него клиентские сертификаты не запрашиваются.
+	ClientCA string
+	// RequireClientCert отклоняет соединения без проверенного клиентского сертификата.
+	RequireClientCert bool
+}
+
+// credentials возвращает TLS учетные данные сервера.
+func (c *TLSConfig) credentials() (credentials.TransportCredentials, error) {
+	cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading gRPC TLS key pair")
+	}
+
+	conf := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if c.ClientCA != "" {
+		pem, err := ioutil.ReadFile(c.ClientCA)
+		if err != nil {
+			return nil, errors.Wrap(err, "reading gRPC client CA bundle")
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.Errorf("no certificates found in gRPC client CA bundle %s", c.ClientCA)
+		}
+
+		conf.ClientCAs = pool
+		conf.ClientAuth = tls.VerifyClientCertIfGiven
+		if c.RequireClientCert {
+			conf.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	} else if c.RequireClientCert {
+		return nil, errors.New("gRPC client certificates are required but client CA bundle is not specified")
+	}
+
+	return credentials.NewTLS(conf), nil
+}
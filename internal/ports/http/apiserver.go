@@ -3,14 +3,24 @@ package http
 import (
 	"context"
 	"log"
+	"net"
 	"net/http"
 	"time"
 
+	"github.com/JetBrainer/sso/internal/domain/manager/audit"
 	"github.com/JetBrainer/sso/internal/domain/manager/auth"
+	"github.com/JetBrainer/sso/internal/domain/manager/events"
 	"github.com/JetBrainer/sso/internal/domain/manager/monitoring"
+	"github.com/JetBrainer/sso/internal/domain/manager/outbox"
+	"github.com/JetBrainer/sso/internal/domain/manager/ratelimit"
+	"github.com/JetBrainer/sso/internal/domain/manager/webhooks"
 	"github.com/JetBrainer/sso/internal/ports/configs"
 	"github.com/JetBrainer/sso/internal/ports/http/resources"
+	adminv1 "github.com/JetBrainer/sso/internal/ports/http/resources/admin/v1"
 	v1 "github.com/JetBrainer/sso/internal/ports/http/resources/auth/v1"
+	eventsv1 "github.com/JetBrainer/sso/internal/ports/http/resources/events/v1"
+	gatewayv2 "github.com/JetBrainer/sso/internal/ports/http/resources/gateway/v2"
+	profilev1 "github.com/JetBrainer/sso/internal/ports/http/resources/profile/v1"
 	v12 "github.com/JetBrainer/sso/internal/ports/http/resources/swagger/v1"
 	"github.com/JetBrainer/sso/pkg/validation"
 	"github.com/go-chi/chi"
@@ -26,8 +36,16 @@ type APIServer struct {
 	BasePath          string
 	FilesDir          string
 	authManager       *auth.Manager
+	auditManager      *audit.Manager
+	eventsManager     *events.Manager
+	outboxManager     *outbox.Manager
+	webhooksManager   *webhooks.Manager
+	rateLimiter       *ratelimit.Limiter
+	trustedProxies    []*net.IPNet
 	monitManager      *monitoring.Manager
 	validator         *validation.Validator
+	metrics           *Metrics
+	gateway           *gatewayv2.GatewayResource
 	idleConnsClosed   chan struct{}
 	IsTesting         bool
 	version           string // версия приложения
@@ -64,21 +82,38 @@ func NewAPIServer(ctx context.Context, config *configs.APIServer, opts ...APISer
 func (srv *APIServer) setupRouter() chi.Router {
 	r := chi.NewRouter()
 
+	r.Use(tracing)            // серверный спан OpenTelemetry на каждый запрос
+	r.Use(requestID)          // идентификаторы запроса и трассировки в контексте и заголовках ответа
 	r.Use(middleware.NoCache) // no-cache
-	//r.Use(middleware.RequestID) // вставляет request ID в контекст каждого запроса
-	r.Use(middleware.Logger)    // логирует начало и окончание каждого запроса с указанием времени обработки
-	r.Use(middleware.Recoverer) // управляемо обрабатывает паники и выдает stack trace при их возникновении
-	r.Use(middleware.RealIP)    // устанавливает RemoteAddr для каждого запроса с заголовками X-Forwarded-For или X-Real-IP
+	if srv.metrics != nil {
+		r.Use(srv.metrics.Handler) // время обработки и коды ответов по шаблонам маршрутов
+	}
+	r.Use(realIP(srv.trustedProxies)) // устанавливает RemoteAddr из X-Forwarded-For или X-Real-IP запросов от доверенных прокси
+	r.Use(auditSource)                // адрес, устройство и User-Agent клиента для журнала аудита
+	r.Use(accessLog)                  // логирует окончание каждого запроса с указанием времени обработки
+	r.Use(middleware.Recoverer)       // управляемо обрабатывает паники и выдает stack trace при их возникновении
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   allowedOrigins(srv.IsTesting),
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", HeaderRequestID, HeaderDeviceID},
+		ExposedHeaders:   []string{"Link", "Retry-After", HeaderRequestID, HeaderTraceID},
 		AllowCredentials: false,
 		MaxAge:           300, // Maximum value not ignored by any of major browsers
 	}))
 
-	r.Mount("/api/v1/auth", v1.NewAuth(srv.authManager,srv.monitManager.Metrics(), srv.validator).Routes())
+	r.Mount("/api/v1/auth", v1.NewAuth(srv.authManager, srv.auditManager, srv.rateLimiter, srv.monitManager.Metrics(), srv.validator).Routes())
+	r.Mount("/api/v1/profile", profilev1.NewProfile(srv.authManager, srv.eventsManager, srv.auditManager, srv.validator).Routes())
+	r.Mount("/api/v1/events", eventsv1.NewEvents(srv.authManager, srv.eventsManager, srv.validator).Routes())
+	r.Mount("/api/v1/admin/users", adminv1.NewUsers(srv.authManager, srv.auditManager, srv.validator).Routes())
+	r.Mount("/api/v1/admin/roles", adminv1.NewRoles(srv.authManager, srv.auditManager, srv.validator).Routes())
+	r.Mount("/api/v1/admin/actions", adminv1.NewActions(srv.authManager, srv.eventsManager, srv.auditManager, srv.validator).Routes())
+	r.Mount("/api/v1/admin/outbox", adminv1.NewOutbox(srv.authManager, srv.outboxManager, srv.auditManager).Routes())
+	r.Mount("/api/v1/admin/webhooks", adminv1.NewWebhooks(srv.authManager, srv.webhooksManager, srv.auditManager, srv.validator).Routes())
+	r.Mount("/api/v1/admin/audit", adminv1.NewAudit(srv.authManager, srv.auditManager).Routes())
+
+	if srv.gateway != nil {
+		r.Mount(gatewayv2.BasePath, srv.gateway.Routes())
+	}
 
 	// монтируем дополнительные ресурсы
 	r.Mount("/version", resources.VersionResource{Version: srv.version}.Routes())
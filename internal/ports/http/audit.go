@@ -0,0 +1,34 @@
+package http
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/JetBrainer/sso/internal/domain/manager/audit"
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/JetBrainer/sso/pkg/logger"
+)
+
+// HeaderDeviceID - заголовок, в котором клиент передает идентификатор устройства.
+const HeaderDeviceID = "X-Device-Id"
+
+// auditSource - middleware, сохраняющее в контексте сведения об источнике
+// запроса для журнала аудита. Подключается после realIP, чтобы адрес клиента
+// учитывал доверенные прокси.
+func auditSource(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			ip = r.RemoteAddr
+		}
+
+		ctx := audit.WithSource(r.Context(), models.AuditSource{
+			IP:        ip,
+			UserAgent: r.UserAgent(),
+			DeviceID:  r.Header.Get(HeaderDeviceID),
+			RequestID: logger.RequestID(r.Context()),
+		})
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
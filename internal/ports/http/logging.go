@@ -0,0 +1,60 @@
+package http
+
+import (
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/JetBrainer/sso/pkg/logger"
+	"github.com/go-chi/chi/middleware"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Заголовки с идентификаторами запроса и трассировки.
+const (
+	HeaderRequestID = "X-Request-Id"
+	HeaderTraceID   = "X-Trace-Id"
+)
+
+// requestIDRegexp ограничивает идентификаторы, принимаемые от клиента,
+// чтобы они не искажали журнал.
+var requestIDRegexp = regexp.MustCompile(`^[A-Za-z0-9._:/-]{1,128}$`)
+
+// requestID - middleware, сохраняющее идентификатор запроса в контексте для
+// журнала и возвращающее его вместе с идентификатором трассировки в
+// заголовках ответа. Идентификатор клиента из X-Request-Id сохраняется.
+func requestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(HeaderRequestID)
+		if !requestIDRegexp.MatchString(id) {
+			id = logger.NewRequestID()
+		}
+
+		w.Header().Set(HeaderRequestID, id)
+		if sc := trace.SpanContextFromContext(r.Context()); sc.IsValid() {
+			w.Header().Set(HeaderTraceID, sc.TraceID().String())
+		}
+
+		next.ServeHTTP(w, r.WithContext(logger.WithRequestID(r.Context(), id)))
+	})
+}
+
+// accessLog - middleware, журналирующее запросы с кодом ответа и временем
+// обработки. Ошибки сервера пишутся с уровнем ERROR.
+func accessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r)
+
+		code := responseStatus(ww)
+		level := "[INFO]"
+		if code >= http.StatusInternalServerError {
+			level = "[ERROR]"
+		}
+
+		logger.Printf(r.Context(), "%s HTTP %s %s %d %dB %s %s", level, r.Method, r.URL.RequestURI(), code,
+			ww.BytesWritten(), time.Since(start), r.RemoteAddr)
+	})
+}
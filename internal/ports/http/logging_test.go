@@ -0,0 +1,43 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/JetBrainer/sso/pkg/logger"
+)
+
+func TestRequestID(t *testing.T) {
+	var got string
+	h := requestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = logger.RequestID(r.Context())
+	}))
+
+	tests := []struct {
+		name, header string
+		keep         bool
+	}{
+		{"generated", "", false},
+		{"from client", "gateway-7f3a/000123", true},
+		{"invalid", "bad id\n[ERROR] injected", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/version", nil)
+			if tt.header != "" {
+				req.Header.Set(HeaderRequestID, tt.header)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			if got == "" || w.Header().Get(HeaderRequestID) != got {
+				t.Fatalf("context id %q, header %q", got, w.Header().Get(HeaderRequestID))
+			}
+			if (got == tt.header) != tt.keep {
+				t.Errorf("request id %q, client sent %q", got, tt.header)
+			}
+		})
+	}
+}
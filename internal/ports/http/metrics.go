@@ -0,0 +1,76 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// unmatchedRoute - метка запросов, для которых не нашлось маршрута. Путь
+// запроса в метку не попадает, чтобы не раздувать число временных рядов.
+const unmatchedRoute = "unmatched"
+
+// Metrics содержит метрики Prometheus по HTTP запросам. Запросы
+// группируются по шаблону маршрута chi, а не по фактическому пути.
+type Metrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "sso_http_requests_total",
+			Help: "Total number of HTTP requests by route and status code.",
+		}, []string{"method", "route", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "sso_http_request_duration_seconds",
+			Help:    "HTTP request latency by route.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+	}
+}
+
+// Collectors возвращает метрики для регистрации в Prometheus.
+func (m *Metrics) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{m.requests, m.duration}
+}
+
+// Handler - middleware, учитывающее время обработки и код ответа.
+func (m *Metrics) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r)
+
+		route, code := routePattern(r), responseStatus(ww)
+
+		m.requests.WithLabelValues(r.Method, route, strconv.Itoa(code)).Inc()
+		m.duration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+	})
+}
+
+// routePattern возвращает шаблон маршрута chi, известный только после
+// маршрутизации запроса.
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" {
+			return pattern
+		}
+	}
+
+	return unmatchedRoute
+}
+
+func responseStatus(ww middleware.WrapResponseWriter) int {
+	if code := ww.Status(); code != 0 {
+		return code
+	}
+
+	return http.StatusOK
+}
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestMetrics_Handler(t *testing.T) {
+	m := NewMetrics()
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(m.Collectors()...)
+
+	users := chi.NewRouter()
+	users.Get("/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	r := chi.NewRouter()
+	r.Use(m.Handler)
+	r.Mount("/api/v1/users", users)
+
+	for _, target := range []string{"/api/v1/users/1", "/api/v1/users/2", "/unknown"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]float64)
+	for _, family := range families {
+		if family.GetName() != "sso_http_requests_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			got[labels["route"]+" "+labels["code"]] = metric.GetCounter().GetValue()
+		}
+	}
+
+	if got["/api/v1/users/{id} 204"] != 2 || got[unmatchedRoute+" 404"] != 1 || len(got) != 2 {
+		t.Errorf("unexpected requests: %v", got)
+	}
+}
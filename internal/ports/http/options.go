@@ -1,8 +1,16 @@
 package http
 
 import (
+	"net"
+
+	"github.com/JetBrainer/sso/internal/domain/manager/audit"
 	"github.com/JetBrainer/sso/internal/domain/manager/auth"
+	"github.com/JetBrainer/sso/internal/domain/manager/events"
 	"github.com/JetBrainer/sso/internal/domain/manager/monitoring"
+	"github.com/JetBrainer/sso/internal/domain/manager/outbox"
+	"github.com/JetBrainer/sso/internal/domain/manager/ratelimit"
+	"github.com/JetBrainer/sso/internal/domain/manager/webhooks"
+	gatewayv2 "github.com/JetBrainer/sso/internal/ports/http/resources/gateway/v2"
 	"github.com/JetBrainer/sso/pkg/validation"
 )
 
@@ -14,6 +22,47 @@ func WithAuthManager(authMan *auth.Manager) APIServerOption {
 	}
 }
 
+// WithAuditManager включает запись событий безопасности в журнал аудита.
+func WithAuditManager(auditMan *audit.Manager) APIServerOption {
+	return func(srv *APIServer) {
+		srv.auditManager = auditMan
+	}
+}
+
+func WithEventsManager(eventsMan *events.Manager) APIServerOption {
+	return func(srv *APIServer) {
+		srv.eventsManager = eventsMan
+	}
+}
+
+func WithOutboxManager(outboxMan *outbox.Manager) APIServerOption {
+	return func(srv *APIServer) {
+		srv.outboxManager = outboxMan
+	}
+}
+
+func WithWebhooksManager(webhooksMan *webhooks.Manager) APIServerOption {
+	return func(srv *APIServer) {
+		srv.webhooksManager = webhooksMan
+	}
+}
+
+// WithRateLimiter ограничивает частоту запросов входа, регистрации и
+// обновления токена.
+func WithRateLimiter(limiter *ratelimit.Limiter) APIServerOption {
+	return func(srv *APIServer) {
+		srv.rateLimiter = limiter
+	}
+}
+
+// WithTrustedProxies задает прокси, которым разрешено передавать адрес
+// клиента в X-Forwarded-For и X-Real-IP.
+func WithTrustedProxies(proxies []*net.IPNet) APIServerOption {
+	return func(srv *APIServer) {
+		srv.trustedProxies = proxies
+	}
+}
+
 func WithMonitoringManager(monitMan *monitoring.Manager) APIServerOption {
 	return func(srv *APIServer) {
 		srv.monitManager = monitMan
@@ -21,6 +70,20 @@ func WithMonitoringManager(monitMan *monitoring.Manager) APIServerOption {
 }
 
 
+// WithMetrics включает метрики Prometheus по каждому HTTP маршруту.
+func WithMetrics(metrics *Metrics) APIServerOption {
+	return func(srv *APIServer) {
+		srv.metrics = metrics
+	}
+}
+
+// WithGateway монтирует REST шлюз к gRPC сервису под /api/v2.
+func WithGateway(gateway *gatewayv2.GatewayResource) APIServerOption {
+	return func(srv *APIServer) {
+		srv.gateway = gateway
+	}
+}
+
 func WithValidator(v *validation.Validator) APIServerOption {
 	return func(srv *APIServer) {
 		srv.validator = v
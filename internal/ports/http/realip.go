@@ -0,0 +1,109 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+var ErrInvalidTrustedProxy = errors.New("trusted proxy must be an IP address or CIDR")
+
+// ParseTrustedProxies разбирает список доверенных прокси из конфигурации:
+// IP адреса и подсети в нотации CIDR.
+func ParseTrustedProxies(list []string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, s := range list {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+
+		if !strings.Contains(s, "/") {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return nil, fmt.Errorf("%w: %s", ErrInvalidTrustedProxy, s)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, ipNet, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidTrustedProxy, s)
+		}
+		nets = append(nets, ipNet)
+	}
+
+	return nets, nil
+}
+
+// realIP - middleware, заменяющее RemoteAddr адресом клиента из
+// X-Forwarded-For или X-Real-IP, только если запрос пришел от доверенного
+// прокси. Иначе заголовки задает сам клиент, и адрес соединения остается
+// как есть, чтобы ограничение частоты запросов и журнал аудита нельзя было
+// обойти подменой заголовка.
+func realIP(trusted []*net.IPNet) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ip := clientIP(r, trusted); ip != "" {
+				r.RemoteAddr = ip
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientIP возвращает адрес клиента, переданный цепочкой доверенных прокси,
+// или пустую строку, если адрес соединения не принадлежит доверенному
+// прокси. X-Forwarded-For разбирается справа налево до первого недоверенного
+// адреса: левее него значения может дописать сам клиент. X-Real-IP
+// учитывается, только если X-Forwarded-For нет.
+func clientIP(r *http.Request, trusted []*net.IPNet) string {
+	peer, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		peer = r.RemoteAddr
+	}
+	if !isTrusted(net.ParseIP(peer), trusted) {
+		return ""
+	}
+
+	if xff := r.Header.Values("X-Forwarded-For"); len(xff) > 0 {
+		hops := strings.Split(strings.Join(xff, ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := net.ParseIP(strings.TrimSpace(hops[i]))
+			if ip == nil {
+				return ""
+			}
+			if i == 0 || !isTrusted(ip, trusted) {
+				return ip.String()
+			}
+		}
+	}
+
+	if ip := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); ip != nil {
+		return ip.String()
+	}
+
+	return ""
+}
+
+func isTrusted(ip net.IP, trusted []*net.IPNet) bool {
+	if ip == nil {
+		return false
+	}
+
+	for _, n := range trusted {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
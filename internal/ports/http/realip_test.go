@@ -0,0 +1,64 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/domain/manager/ratelimit"
+	"github.com/JetBrainer/sso/internal/domain/models"
+	v1 "github.com/JetBrainer/sso/internal/ports/http/resources/auth/v1"
+)
+
+func TestRealIP_RateLimit(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.0.2.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	limiter := ratelimit.New(ratelimit.NewMemory(), ratelimit.Policy{
+		IP: models.RateLimit{Burst: 1, Period: time.Minute},
+	})
+	limit := v1.NewRateLimitCtx(limiter, nil).Limit(models.OperationSignIn)
+	h := realIP(proxies)(limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+
+	do := func(remoteAddr, header, value string) int {
+		req := httptest.NewRequest(http.MethodPost, "/signin/email", nil)
+		req.RemoteAddr = remoteAddr
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	tests := []struct {
+		name, remoteAddr, header, value string
+		code                            int
+	}{
+		{"first request", "203.0.113.5:41000", "", "", http.StatusOK},
+		{"spoofed forwarded address", "203.0.113.5:41001", "X-Forwarded-For", "198.51.100.1", http.StatusTooManyRequests},
+		{"spoofed real ip", "203.0.113.5:41002", "X-Real-IP", "198.51.100.2", http.StatusTooManyRequests},
+		{"client behind trusted proxy", "10.1.2.3:5000", "X-Forwarded-For", "198.51.100.3", http.StatusOK},
+		{"same client via proxy chain", "192.0.2.1:5000", "X-Forwarded-For", "198.51.100.3, 10.4.4.4", http.StatusTooManyRequests},
+		{"client prepends fake hop", "10.1.2.3:5001", "X-Forwarded-For", "198.51.100.9, 198.51.100.3", http.StatusTooManyRequests},
+		{"another client via real ip", "10.1.2.3:5002", "X-Real-IP", "198.51.100.4", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		if code := do(tt.remoteAddr, tt.header, tt.value); code != tt.code {
+			t.Errorf("%s: status %d, want %d", tt.name, code, tt.code)
+		}
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	if _, err := ParseTrustedProxies([]string{"10.0.0.0/8", " ::1 ", ""}); err != nil {
+		t.Errorf("valid proxies: %v", err)
+	}
+	if _, err := ParseTrustedProxies([]string{"proxy.local"}); err == nil {
+		t.Error("host name accepted as trusted proxy")
+	}
+}
@@ -0,0 +1,10 @@
+package admin
+
+import "errors"
+
+var (
+	ErrUnknownTDID         = errors.New("unknown user TDID")
+	ErrUnknownOutboxStatus = errors.New("unknown outbox message status")
+
+	ErrUnknownWebhookDeliveryStatus = errors.New("unknown webhook delivery status")
+)
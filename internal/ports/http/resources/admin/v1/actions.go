@@ -0,0 +1,244 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	errors2 "github.com/JetBrainer/sso/internal/domain/errors"
+	"github.com/JetBrainer/sso/internal/domain/manager/audit"
+	"github.com/JetBrainer/sso/internal/domain/manager/auth"
+	"github.com/JetBrainer/sso/internal/domain/manager/events"
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/JetBrainer/sso/internal/domain/models/api"
+	"github.com/JetBrainer/sso/internal/domain/permissions"
+	"github.com/JetBrainer/sso/internal/ports/http/resources"
+	authv1 "github.com/JetBrainer/sso/internal/ports/http/resources/auth/v1"
+	"github.com/JetBrainer/sso/pkg/validation"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/jwtauth"
+	"github.com/go-chi/render"
+	"github.com/pkg/errors"
+)
+
+// ActionsResource предоставляет API реестра типов действий, которые
+// используются событиями и подтверждением операций.
+type ActionsResource struct {
+	authManager   *auth.Manager
+	eventsManager *events.Manager
+	audit         *audit.Manager
+	validate      *validation.Validator
+}
+
+func NewActions(authMan *auth.Manager, eventsMan *events.Manager, auditMan *audit.Manager, validate *validation.Validator) *ActionsResource {
+	return &ActionsResource{
+		authManager:   authMan,
+		eventsManager: eventsMan,
+		audit:         auditMan,
+		validate:      validate,
+	}
+}
+
+func (ar ActionsResource) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Group(func(r chi.Router) {
+		r.Use(jwtauth.Verifier(ar.authManager.TokenAuth()))
+		r.Use(authv1.NewUserAccessCtx(ar.authManager.JWTKey()).ChiMiddleware)
+
+		access := authv1.NewPermissionAccessCtx(ar.authManager.PermissionsManager())
+
+		r.With(access.RequirePermission(permissions.ViewActions)).Get("/", ar.Actions)
+		r.With(access.RequirePermission(permissions.CreateActions)).Post("/", ar.Create)
+		r.With(access.RequirePermission(permissions.ViewActions)).Get("/{id}", ar.Action)
+		r.With(access.RequirePermission(permissions.UpdateActions)).Put("/{id}", ar.Update)
+		r.With(access.RequirePermission(permissions.DeleteActions)).Delete("/{id}", ar.Delete)
+	})
+
+	return r
+}
+
+// @Summary Действия
+// @Description Возвращает все зарегистрированные типы действий
+// @Produce json
+// @Tags admin
+// @Security JWT
+// @Success 200 {array} models.Action
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/actions [get]
+func (ar ActionsResource) Actions(w http.ResponseWriter, r *http.Request) {
+	actions, err := ar.eventsManager.Actions().All(r.Context())
+	if err != nil {
+		_ = render.Render(w, r, resources.Internal(err))
+		return
+	}
+
+	render.JSON(w, r, actions)
+}
+
+// @Summary Действие
+// @Description Возвращает тип действия по его идентификатору
+// @Produce json
+// @Tags admin
+// @Security JWT
+// @Param id path string true "Идентификатор действия"
+// @Success 200 {object} models.Action
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/actions/{id} [get]
+func (ar ActionsResource) Action(w http.ResponseWriter, r *http.Request) {
+	id := models.PolymorphicIDFromString(chi.URLParam(r, "id"))
+
+	action, err := ar.eventsManager.Actions().ByID(r.Context(), id)
+	if err != nil {
+		renderActionError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, action)
+}
+
+// @Summary Регистрация действия
+// @Description Регистрирует новый тип действия
+// @Accept json
+// @Produce json
+// @Tags admin
+// @Security JWT
+// @Param body body api.ActionRequest true "Данные действия"
+// @Success 201 {object} models.Action
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Router /admin/actions [post]
+func (ar ActionsResource) Create(w http.ResponseWriter, r *http.Request) {
+	var request api.ActionRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		_ = render.Render(w, r, resources.BadRequest(err))
+		return
+	}
+
+	if err := ar.validate.Struct(request); err != nil {
+		_ = render.Render(w, r, resources.UnprocessableEntity(err))
+		return
+	}
+
+	action := &models.Action{
+		Title: request.Title,
+		Type:  request.Type,
+	}
+
+	if err := ar.eventsManager.Actions().Create(r.Context(), action); err != nil {
+		renderActionError(w, r, err)
+		return
+	}
+
+	ar.audit.Record(r.Context(), &models.AuditEntry{
+		Type:    models.AuditActionCreated,
+		Actor:   actor(r),
+		Target:  string(action.ID),
+		Changes: audit.Diff(models.Action{}, *action),
+	})
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, action)
+}
+
+// @Summary Изменение действия
+// @Description Изменяет название и тип действия
+// @Accept json
+// @Produce json
+// @Tags admin
+// @Security JWT
+// @Param id path string true "Идентификатор действия"
+// @Param body body api.ActionRequest true "Данные действия"
+// @Success 200
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Router /admin/actions/{id} [put]
+func (ar ActionsResource) Update(w http.ResponseWriter, r *http.Request) {
+	var request api.ActionRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		_ = render.Render(w, r, resources.BadRequest(err))
+		return
+	}
+
+	if err := ar.validate.Struct(request); err != nil {
+		_ = render.Render(w, r, resources.UnprocessableEntity(err))
+		return
+	}
+
+	action := &models.Action{
+		ID:    models.PolymorphicIDFromString(chi.URLParam(r, "id")),
+		Title: request.Title,
+		Type:  request.Type,
+	}
+
+	before, err := ar.eventsManager.Actions().ByID(r.Context(), action.ID)
+	if err != nil {
+		renderActionError(w, r, err)
+		return
+	}
+
+	if err := ar.eventsManager.Actions().Update(r.Context(), action); err != nil {
+		renderActionError(w, r, err)
+		return
+	}
+
+	ar.audit.Record(r.Context(), &models.AuditEntry{
+		Type:    models.AuditActionUpdated,
+		Actor:   actor(r),
+		Target:  string(action.ID),
+		Changes: audit.Diff(*before, *action),
+	})
+
+	render.Status(r, http.StatusOK)
+}
+
+// @Summary Удаление действия
+// @Description Удаляет тип действия из реестра
+// @Produce json
+// @Tags admin
+// @Security JWT
+// @Param id path string true "Идентификатор действия"
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/actions/{id} [delete]
+func (ar ActionsResource) Delete(w http.ResponseWriter, r *http.Request) {
+	id := models.PolymorphicIDFromString(chi.URLParam(r, "id"))
+
+	if err := ar.eventsManager.Actions().DeleteByID(r.Context(), id); err != nil {
+		renderActionError(w, r, err)
+		return
+	}
+
+	ar.audit.Record(r.Context(), &models.AuditEntry{
+		Type:   models.AuditActionDeleted,
+		Actor:  actor(r),
+		Target: string(id),
+	})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// renderActionError сопоставляет ошибки реестра действий с HTTP ответами.
+func renderActionError(w http.ResponseWriter, r *http.Request, err error) {
+	switch errors.Cause(err) {
+	case errors2.ErrInvalidID:
+		_ = render.Render(w, r, resources.BadRequest(err))
+	case errors2.ErrDoesNotExist:
+		_ = render.Render(w, r, resources.ResourceNotFound(err))
+	case events.ErrActionAlreadyExists:
+		_ = render.Render(w, r, resources.Conflict(err))
+	default:
+		_ = render.Render(w, r, resources.Internal(err))
+	}
+}
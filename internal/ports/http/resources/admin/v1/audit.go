@@ -0,0 +1,76 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/JetBrainer/sso/internal/domain/manager/audit"
+	"github.com/JetBrainer/sso/internal/domain/manager/auth"
+	"github.com/JetBrainer/sso/internal/domain/permissions"
+	"github.com/JetBrainer/sso/internal/ports/http/resources"
+	authv1 "github.com/JetBrainer/sso/internal/ports/http/resources/auth/v1"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/jwtauth"
+	"github.com/go-chi/render"
+)
+
+// AuditResource предоставляет операторам просмотр журнала аудита безопасности.
+type AuditResource struct {
+	authManager *auth.Manager
+	audit       *audit.Manager
+}
+
+func NewAudit(authMan *auth.Manager, auditMan *audit.Manager) *AuditResource {
+	return &AuditResource{
+		authManager: authMan,
+		audit:       auditMan,
+	}
+}
+
+func (ar AuditResource) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Group(func(r chi.Router) {
+		r.Use(jwtauth.Verifier(ar.authManager.TokenAuth()))
+		r.Use(authv1.NewUserAccessCtx(ar.authManager.JWTKey()).ChiMiddleware)
+
+		access := authv1.NewPermissionAccessCtx(ar.authManager.PermissionsManager())
+
+		r.With(access.RequirePermission(permissions.ViewAudit)).Get("/", ar.Entries)
+	})
+
+	return r
+}
+
+// @Summary Журнал аудита
+// @Description Возвращает записи журнала аудита, начиная с самых новых
+// @Produce json
+// @Tags admin
+// @Security JWT
+// @Param tdid query string false "TDID пользователя, которого касается событие"
+// @Param actor query string false "TDID пользователя, выполнившего действие"
+// @Param type query string false "Тип события, например auth.sign_in"
+// @Param outcome query string false "Результат" Enums(success, failure)
+// @Param from query string false "Начало периода, RFC 3339"
+// @Param to query string false "Конец периода, RFC 3339"
+// @Param limit query int false "Количество записей (по умолчанию 50)"
+// @Param offset query int false "Смещение"
+// @Success 200 {array} models.AuditEntry
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/audit [get]
+func (ar AuditResource) Entries(w http.ResponseWriter, r *http.Request) {
+	filters, err := resources.AuditFiltersFromQuery(r)
+	if err != nil {
+		_ = render.Render(w, r, resources.BadRequest(err))
+		return
+	}
+
+	entries, err := ar.audit.List(r.Context(), filters)
+	if err != nil {
+		_ = render.Render(w, r, resources.Internal(err))
+		return
+	}
+
+	render.JSON(w, r, entries)
+}
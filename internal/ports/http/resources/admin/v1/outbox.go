@@ -0,0 +1,175 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	errors2 "github.com/JetBrainer/sso/internal/domain/errors"
+	"github.com/JetBrainer/sso/internal/domain/manager/audit"
+	"github.com/JetBrainer/sso/internal/domain/manager/auth"
+	"github.com/JetBrainer/sso/internal/domain/manager/outbox"
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/JetBrainer/sso/internal/domain/permissions"
+	"github.com/JetBrainer/sso/internal/ports/http/resources"
+	"github.com/JetBrainer/sso/internal/ports/http/resources/admin"
+	authv1 "github.com/JetBrainer/sso/internal/ports/http/resources/auth/v1"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/jwtauth"
+	"github.com/go-chi/render"
+	"github.com/pkg/errors"
+)
+
+// OutboxResource предоставляет операторам просмотр очереди уведомлений и
+// повторную отправку сообщений из dead-letter.
+type OutboxResource struct {
+	authManager   *auth.Manager
+	outboxManager *outbox.Manager
+	audit         *audit.Manager
+}
+
+func NewOutbox(authMan *auth.Manager, outboxMan *outbox.Manager, auditMan *audit.Manager) *OutboxResource {
+	return &OutboxResource{
+		authManager:   authMan,
+		outboxManager: outboxMan,
+		audit:         auditMan,
+	}
+}
+
+func (ob OutboxResource) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Group(func(r chi.Router) {
+		r.Use(jwtauth.Verifier(ob.authManager.TokenAuth()))
+		r.Use(authv1.NewUserAccessCtx(ob.authManager.JWTKey()).ChiMiddleware)
+
+		access := authv1.NewPermissionAccessCtx(ob.authManager.PermissionsManager())
+
+		r.With(access.RequirePermission(permissions.ViewOutbox)).Get("/", ob.Messages)
+		r.With(access.RequirePermission(permissions.ViewOutbox)).Get("/{id}", ob.Message)
+		r.With(access.RequirePermission(permissions.ReplayOutbox)).Post("/{id}/replay", ob.Replay)
+	})
+
+	return r
+}
+
+// @Summary Сообщения outbox
+// @Description Возвращает сообщения очереди уведомлений, начиная с самых новых
+// @Produce json
+// @Tags admin
+// @Security JWT
+// @Param status query string false "Статус сообщения" Enums(pending, sending, sent, dead)
+// @Param limit query int false "Количество сообщений (по умолчанию 50)"
+// @Param offset query int false "Смещение"
+// @Success 200 {array} models.OutboxMessage
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/outbox [get]
+func (ob OutboxResource) Messages(w http.ResponseWriter, r *http.Request) {
+	filters, err := outboxFiltersFromQuery(r)
+	if err != nil {
+		_ = render.Render(w, r, resources.BadRequest(err))
+		return
+	}
+
+	messages, err := ob.outboxManager.List(r.Context(), filters)
+	if err != nil {
+		_ = render.Render(w, r, resources.Internal(err))
+		return
+	}
+
+	render.JSON(w, r, messages)
+}
+
+// @Summary Сообщение outbox
+// @Description Возвращает сообщение очереди уведомлений по его идентификатору
+// @Produce json
+// @Tags admin
+// @Security JWT
+// @Param id path string true "Идентификатор сообщения"
+// @Success 200 {object} models.OutboxMessage
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/outbox/{id} [get]
+func (ob OutboxResource) Message(w http.ResponseWriter, r *http.Request) {
+	msg, err := ob.outboxManager.ByID(r.Context(), models.PolymorphicID(chi.URLParam(r, "id")))
+	if err != nil {
+		renderOutboxError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, msg)
+}
+
+// @Summary Повторная отправка
+// @Description Возвращает сообщение из dead-letter в очередь на отправку
+// @Produce json
+// @Tags admin
+// @Security JWT
+// @Param id path string true "Идентификатор сообщения"
+// @Success 200 {object} models.OutboxMessage
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/outbox/{id}/replay [post]
+func (ob OutboxResource) Replay(w http.ResponseWriter, r *http.Request) {
+	msg, err := ob.outboxManager.Replay(r.Context(), models.PolymorphicID(chi.URLParam(r, "id")))
+	if err != nil {
+		renderOutboxError(w, r, err)
+		return
+	}
+
+	ob.audit.Record(r.Context(), &models.AuditEntry{
+		Type:   models.AuditOutboxReplayed,
+		Actor:  actor(r),
+		Target: string(msg.ID),
+	})
+
+	render.JSON(w, r, msg)
+}
+
+// outboxFiltersFromQuery извлекает фильтры выборки сообщений из параметров запроса.
+func outboxFiltersFromQuery(r *http.Request) (*models.OutboxFilters, error) {
+	query := r.URL.Query()
+	filters := new(models.OutboxFilters)
+
+	if status := query.Get("status"); status != "" {
+		switch status {
+		case models.OutboxStatusPending, models.OutboxStatusSending, models.OutboxStatusSent, models.OutboxStatusDead:
+			filters.Status = &status
+		default:
+			return nil, admin.ErrUnknownOutboxStatus
+		}
+	}
+
+	var err error
+	if limit := query.Get("limit"); limit != "" {
+		if filters.Limit, err = strconv.ParseInt(limit, 10, 64); err != nil {
+			return nil, err
+		}
+	}
+
+	if offset := query.Get("offset"); offset != "" {
+		if filters.Offset, err = strconv.ParseInt(offset, 10, 64); err != nil {
+			return nil, err
+		}
+	}
+
+	return filters, nil
+}
+
+// renderOutboxError отображает ошибки outbox в HTTP ответы.
+func renderOutboxError(w http.ResponseWriter, r *http.Request, err error) {
+	switch errors.Cause(err) {
+	case errors2.ErrInvalidID:
+		_ = render.Render(w, r, resources.BadRequest(err))
+	case errors2.ErrDoesNotExist:
+		_ = render.Render(w, r, resources.ResourceNotFound(err))
+	case outbox.ErrMessageNotDead:
+		_ = render.Render(w, r, resources.Conflict(err))
+	default:
+		_ = render.Render(w, r, resources.Internal(err))
+	}
+}
@@ -0,0 +1,67 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/JetBrainer/sso/internal/domain/manager/audit"
+	"github.com/JetBrainer/sso/internal/domain/manager/auth"
+	"github.com/JetBrainer/sso/internal/domain/permissions"
+	"github.com/JetBrainer/sso/internal/ports/http/resources/admin"
+	authv1 "github.com/JetBrainer/sso/internal/ports/http/resources/auth/v1"
+	"github.com/JetBrainer/sso/pkg/validation"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/jwtauth"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UsersResource предоставляет операторам API управления аккаунтами пользователей.
+type UsersResource struct {
+	authManager *auth.Manager
+	audit       *audit.Manager
+	validate    *validation.Validator
+}
+
+func NewUsers(authMan *auth.Manager, auditMan *audit.Manager, validate *validation.Validator) *UsersResource {
+	return &UsersResource{
+		authManager: authMan,
+		audit:       auditMan,
+		validate:    validate,
+	}
+}
+
+func (ur UsersResource) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Group(func(r chi.Router) {
+		r.Use(jwtauth.Verifier(ur.authManager.TokenAuth()))
+		r.Use(authv1.NewUserAccessCtx(ur.authManager.JWTKey()).ChiMiddleware)
+
+		access := authv1.NewPermissionAccessCtx(ur.authManager.PermissionsManager())
+
+		r.With(access.RequirePermission(permissions.ViewUsers)).Get("/{tdid}", ur.User)
+		r.With(access.RequirePermission(permissions.UpdateUsers)).Patch("/{tdid}", ur.Update)
+		r.With(access.RequirePermission(permissions.DeleteUsers)).Delete("/{tdid}", ur.Delete)
+
+		r.With(access.RequirePermission(permissions.UpdateUsers)).Post("/{tdid}/enable", ur.Enable)
+		r.With(access.RequirePermission(permissions.UpdateUsers)).Post("/{tdid}/disable", ur.Disable)
+		r.With(access.RequirePermission(permissions.UpdateUsers)).Post("/{tdid}/unlock", ur.Unlock)
+	})
+
+	return r
+}
+
+// tdidFromURL извлекает TDID пользователя из пути запроса.
+func tdidFromURL(r *http.Request) (primitive.ObjectID, error) {
+	tdid := chi.URLParam(r, "tdid")
+	if tdid == "" {
+		return primitive.NilObjectID, admin.ErrUnknownTDID
+	}
+
+	return primitive.ObjectIDFromHex(tdid)
+}
+
+// actor возвращает TDID оператора, выполняющего запрос.
+func actor(r *http.Request) string {
+	tdid, _ := r.Context().Value("tdid").(string)
+	return tdid
+}
@@ -0,0 +1,236 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/JetBrainer/sso/internal/domain/manager/audit"
+	"github.com/JetBrainer/sso/internal/domain/manager/auth"
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/JetBrainer/sso/internal/domain/models/api"
+	"github.com/JetBrainer/sso/internal/domain/permissions"
+	"github.com/JetBrainer/sso/internal/ports/http/resources"
+	authv1 "github.com/JetBrainer/sso/internal/ports/http/resources/auth/v1"
+	"github.com/JetBrainer/sso/pkg/validation"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/jwtauth"
+	"github.com/go-chi/render"
+)
+
+// RolesResource предоставляет API управления ролями и их правами.
+type RolesResource struct {
+	authManager *auth.Manager
+	audit       *audit.Manager
+	validate    *validation.Validator
+}
+
+func NewRoles(authMan *auth.Manager, auditMan *audit.Manager, validate *validation.Validator) *RolesResource {
+	return &RolesResource{
+		authManager: authMan,
+		audit:       auditMan,
+		validate:    validate,
+	}
+}
+
+func (rr RolesResource) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Group(func(r chi.Router) {
+		r.Use(jwtauth.Verifier(rr.authManager.TokenAuth()))
+		r.Use(authv1.NewUserAccessCtx(rr.authManager.JWTKey()).ChiMiddleware)
+
+		access := authv1.NewPermissionAccessCtx(rr.authManager.PermissionsManager())
+
+		r.With(access.RequirePermission(permissions.ViewRoles)).Get("/", rr.Roles)
+		r.With(access.RequirePermission(permissions.CreateRoles)).Post("/", rr.Create)
+		r.With(access.RequirePermission(permissions.ViewRoles)).Get("/{name}", rr.Role)
+		r.With(access.RequirePermission(permissions.UpdateRoles)).Put("/{name}", rr.Update)
+		r.With(access.RequirePermission(permissions.DeleteRoles)).Delete("/{name}", rr.Delete)
+	})
+
+	return r
+}
+
+// @Summary Роли
+// @Description Возвращает список всех ролей с их правами
+// @Produce json
+// @Tags admin
+// @Security JWT
+// @Success 200 {array} models.Role
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/roles [get]
+func (rr RolesResource) Roles(w http.ResponseWriter, r *http.Request) {
+	roles, err := rr.authManager.RolesManager().Roles(r.Context())
+	if err != nil {
+		_ = render.Render(w, r, resources.Internal(err))
+		return
+	}
+
+	render.JSON(w, r, roles)
+}
+
+// @Summary Роль
+// @Description Возвращает роль по ее имени
+// @Produce json
+// @Tags admin
+// @Security JWT
+// @Param name path string true "Имя роли"
+// @Success 200 {object} models.Role
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/roles/{name} [get]
+func (rr RolesResource) Role(w http.ResponseWriter, r *http.Request) {
+	role, err := rr.authManager.RolesManager().ByName(r.Context(), chi.URLParam(r, "name"))
+	if err != nil {
+		renderRoleError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, role)
+}
+
+// @Summary Создание роли
+// @Description Создает новую роль с указанными правами
+// @Accept json
+// @Produce json
+// @Tags admin
+// @Security JWT
+// @Param body body api.RoleCreateRequest true "Данные новой роли"
+// @Success 201 {object} models.Role
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Router /admin/roles [post]
+func (rr RolesResource) Create(w http.ResponseWriter, r *http.Request) {
+	var request api.RoleCreateRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		_ = render.Render(w, r, resources.BadRequest(err))
+		return
+	}
+
+	if err := rr.validate.Struct(request); err != nil {
+		_ = render.Render(w, r, resources.UnprocessableEntity(err))
+		return
+	}
+
+	role := &models.Role{
+		Name:        request.Name,
+		Permissions: request.Permissions,
+	}
+	if role.Permissions == nil {
+		role.Permissions = models.RolePermissions{}
+	}
+
+	if err := rr.authManager.RolesManager().Create(r.Context(), role); err != nil {
+		renderRoleError(w, r, err)
+		return
+	}
+
+	rr.audit.Record(r.Context(), &models.AuditEntry{
+		Type:    models.AuditRoleCreated,
+		Actor:   actor(r),
+		Target:  role.Name,
+		Changes: audit.Diff(models.Role{}, *role),
+	})
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, role)
+}
+
+// @Summary Изменение роли
+// @Description Заменяет набор прав роли
+// @Accept json
+// @Produce json
+// @Tags admin
+// @Security JWT
+// @Param name path string true "Имя роли"
+// @Param body body api.RoleUpdateRequest true "Новый набор прав"
+// @Success 200
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Router /admin/roles/{name} [put]
+func (rr RolesResource) Update(w http.ResponseWriter, r *http.Request) {
+	var request api.RoleUpdateRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		_ = render.Render(w, r, resources.BadRequest(err))
+		return
+	}
+
+	if err := rr.validate.Struct(request); err != nil {
+		_ = render.Render(w, r, resources.UnprocessableEntity(err))
+		return
+	}
+
+	role := &models.Role{
+		Name:        chi.URLParam(r, "name"),
+		Permissions: request.Permissions,
+	}
+
+	before, err := rr.authManager.RolesManager().ByName(r.Context(), role.Name)
+	if err != nil {
+		renderRoleError(w, r, err)
+		return
+	}
+
+	if err := rr.authManager.RolesManager().Update(r.Context(), role); err != nil {
+		renderRoleError(w, r, err)
+		return
+	}
+
+	rr.audit.Record(r.Context(), &models.AuditEntry{
+		Type:    models.AuditRoleUpdated,
+		Actor:   actor(r),
+		Target:  role.Name,
+		Changes: audit.Diff(*before, *role),
+	})
+
+	render.Status(r, http.StatusOK)
+}
+
+// @Summary Удаление роли
+// @Description Удаляет роль, если она не назначена ни одному пользователю
+// @Produce json
+// @Tags admin
+// @Security JWT
+// @Param name path string true "Имя роли"
+// @Success 204
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/roles/{name} [delete]
+func (rr RolesResource) Delete(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	if err := rr.authManager.RolesManager().Delete(r.Context(), name); err != nil {
+		renderRoleError(w, r, err)
+		return
+	}
+
+	rr.audit.Record(r.Context(), &models.AuditEntry{
+		Type:   models.AuditRoleDeleted,
+		Actor:  actor(r),
+		Target: name,
+	})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// renderRoleError сопоставляет ошибки менеджера ролей с HTTP ответами.
+func renderRoleError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, auth.ErrRoleDoesNotExist):
+		_ = render.Render(w, r, resources.ResourceNotFound(err))
+	case errors.Is(err, auth.ErrRoleAlreadyExists), errors.Is(err, auth.ErrRoleInUse):
+		_ = render.Render(w, r, resources.Conflict(err))
+	case errors.Is(err, auth.ErrUnknownPermission):
+		_ = render.Render(w, r, resources.UnprocessableEntity(err))
+	default:
+		_ = render.Render(w, r, resources.Internal(err))
+	}
+}
@@ -0,0 +1,281 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	"github.com/JetBrainer/sso/internal/domain/manager/audit"
+	"github.com/JetBrainer/sso/internal/domain/manager/auth"
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/JetBrainer/sso/internal/domain/models/api"
+	"github.com/JetBrainer/sso/internal/ports/http/resources"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/pkg/errors"
+)
+
+// @Summary Пользователь
+// @Description Позволяет оператору получить информацию об аккаунте пользователя
+// @Produce json
+// @Tags admin
+// @Security JWT
+// @Param tdid path string true "TDID пользователя"
+// @Success 200 {object} api.AdminUserResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/users/{tdid} [get]
+func (ur UsersResource) User(w http.ResponseWriter, r *http.Request) {
+	id, err := tdidFromURL(r)
+	if err != nil {
+		_ = render.Render(w, r, resources.BadRequest(err))
+		return
+	}
+
+	user, err := ur.authManager.Users().ByTDID(id)
+	if err != nil {
+		renderUserError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, api.AdminUserResponse{
+		UserShortInfo: user.GetShortInfo(),
+		Lockout:       user.Lockout,
+	})
+}
+
+// @Summary Изменение пользователя
+// @Description Позволяет оператору обновить профиль и роли пользователя
+// @Accept json
+// @Produce json
+// @Tags admin
+// @Security JWT
+// @Param tdid path string true "TDID пользователя"
+// @Param body body api.AdminUserUpdateRequest true "Обновленные данные пользователя"
+// @Success 200
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Router /admin/users/{tdid} [patch]
+func (ur UsersResource) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := tdidFromURL(r)
+	if err != nil {
+		_ = render.Render(w, r, resources.BadRequest(err))
+		return
+	}
+
+	var request api.AdminUserUpdateRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		_ = render.Render(w, r, resources.BadRequest(err))
+		return
+	}
+
+	// Сохраняем ошибки валидации в переменной до получения данных по юзеру
+	validationErrors := ur.validate.Struct(request)
+
+	var user *models.User
+
+	users := ur.authManager.Users()
+	user, err = users.ByTDID(id)
+	if err != nil {
+		renderUserError(w, r, err)
+		return
+	}
+
+	if validationErrors != nil {
+		validationErrorsSlice := validationErrors.(validator.ValidationErrors)
+		finalValidationErrors := make(validator.ValidationErrors, 0, len(validationErrorsSlice))
+		for _, valErr := range validationErrorsSlice {
+			// Пропускаем ошибку уникальности имейла, так как он уже принадлежит юзеру
+			if valErr.ActualTag() == "unique_email" && *request.Email == user.Email {
+				continue
+			}
+			finalValidationErrors = append(finalValidationErrors, valErr)
+		}
+
+		if len(finalValidationErrors) > 0 {
+			_ = render.Render(w, r, resources.UnprocessableEntity(finalValidationErrors))
+			return
+		}
+	}
+
+	before := *user
+	if request.Roles != nil {
+		if err := users.CheckRolesExist(r.Context(), *request.Roles); err != nil {
+			if err == auth.ErrRoleDoesNotExist {
+				_ = render.Render(w, r, resources.UnprocessableEntity(err))
+				return
+			}
+
+			_ = render.Render(w, r, resources.Internal(err))
+			return
+		}
+
+		user.Roles = *request.Roles
+	}
+
+	if err := users.Update(request.Merge(user)); err != nil {
+		_ = render.Render(w, r, resources.BadRequest(err))
+		return
+	}
+
+	ur.recordUpdate(r, id.Hex(), audit.Diff(before, *user))
+
+	render.Status(r, http.StatusOK)
+}
+
+// @Summary Включение пользователя
+// @Description Делает аккаунт пользователя активным
+// @Produce json
+// @Tags admin
+// @Security JWT
+// @Param tdid path string true "TDID пользователя"
+// @Success 200
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/users/{tdid}/enable [post]
+func (ur UsersResource) Enable(w http.ResponseWriter, r *http.Request) {
+	ur.switchState(w, r, models.AuditUserEnabled, (*auth.Users).Enable)
+}
+
+// @Summary Выключение пользователя
+// @Description Делает аккаунт пользователя неактивным, вход в него становится невозможен
+// @Produce json
+// @Tags admin
+// @Security JWT
+// @Param tdid path string true "TDID пользователя"
+// @Success 200
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/users/{tdid}/disable [post]
+func (ur UsersResource) Disable(w http.ResponseWriter, r *http.Request) {
+	ur.switchState(w, r, models.AuditUserDisabled, (*auth.Users).Disable)
+}
+
+// @Summary Разблокировка входа пользователя
+// @Description Снимает задержку и блокировку входа после неудачных попыток ввода пароля
+// @Produce json
+// @Tags admin
+// @Security JWT
+// @Param tdid path string true "TDID пользователя"
+// @Success 200
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/users/{tdid}/unlock [post]
+func (ur UsersResource) Unlock(w http.ResponseWriter, r *http.Request) {
+	ur.switchState(w, r, models.AuditUserUnlocked, (*auth.Users).Unlock)
+}
+
+// switchState находит пользователя по TDID и применяет к нему операцию
+// включения, выключения или разблокировки по логину.
+func (ur UsersResource) switchState(w http.ResponseWriter, r *http.Request, eventType string, fn func(*auth.Users, string) error) {
+	id, err := tdidFromURL(r)
+	if err != nil {
+		_ = render.Render(w, r, resources.BadRequest(err))
+		return
+	}
+
+	users := ur.authManager.Users()
+	user, err := users.ByTDID(id)
+	if err != nil {
+		renderUserError(w, r, err)
+		return
+	}
+
+	if err := fn(users, user.Login); err != nil {
+		_ = render.Render(w, r, resources.BadRequest(err))
+		return
+	}
+
+	ur.audit.Record(r.Context(), &models.AuditEntry{
+		Type:  eventType,
+		Actor: actor(r),
+		TDID:  id.Hex(),
+	})
+
+	render.Status(r, http.StatusOK)
+}
+
+// @Summary Удаление пользователя
+// @Description Безвозвратно удаляет аккаунт пользователя
+// @Produce json
+// @Tags admin
+// @Security JWT
+// @Param tdid path string true "TDID пользователя"
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/users/{tdid} [delete]
+func (ur UsersResource) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := tdidFromURL(r)
+	if err != nil {
+		_ = render.Render(w, r, resources.BadRequest(err))
+		return
+	}
+
+	users := ur.authManager.Users()
+	user, err := users.ByTDID(id)
+	if err != nil {
+		renderUserError(w, r, err)
+		return
+	}
+
+	if err := users.Delete(user.Login); err != nil {
+		_ = render.Render(w, r, resources.Internal(err))
+		return
+	}
+
+	ur.audit.Record(r.Context(), &models.AuditEntry{
+		Type:  models.AuditUserDeleted,
+		Actor: actor(r),
+		TDID:  id.Hex(),
+		Login: user.Login,
+	})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// recordUpdate журналирует изменение профиля пользователя оператором. Смена
+// ролей записывается отдельным событием.
+func (ur UsersResource) recordUpdate(r *http.Request, tdid string, changes []models.AuditChange) {
+	profileChanges := make([]models.AuditChange, 0, len(changes))
+	for _, change := range changes {
+		if change.Field == "roles" {
+			ur.audit.Record(r.Context(), &models.AuditEntry{
+				Type:    models.AuditUserRolesChanged,
+				Actor:   actor(r),
+				TDID:    tdid,
+				Changes: []models.AuditChange{change},
+			})
+			continue
+		}
+
+		profileChanges = append(profileChanges, change)
+	}
+
+	if len(profileChanges) > 0 {
+		ur.audit.Record(r.Context(), &models.AuditEntry{
+			Type:    models.AuditUserUpdated,
+			Actor:   actor(r),
+			TDID:    tdid,
+			Changes: profileChanges,
+		})
+	}
+}
+
+// renderUserError отображает ошибки поиска пользователя в HTTP ответы.
+func renderUserError(w http.ResponseWriter, r *http.Request, err error) {
+	switch errors.Cause(err) {
+	case drivers.ErrUserDoesNotExist, auth.ErrUserDoesNotExist:
+		_ = render.Render(w, r, resources.ResourceNotFound(err))
+	default:
+		_ = render.Render(w, r, resources.Internal(err))
+	}
+}
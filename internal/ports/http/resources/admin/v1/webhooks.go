@@ -0,0 +1,398 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	errors2 "github.com/JetBrainer/sso/internal/domain/errors"
+	"github.com/JetBrainer/sso/internal/domain/manager/audit"
+	"github.com/JetBrainer/sso/internal/domain/manager/auth"
+	"github.com/JetBrainer/sso/internal/domain/manager/webhooks"
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/JetBrainer/sso/internal/domain/models/api"
+	"github.com/JetBrainer/sso/internal/domain/permissions"
+	"github.com/JetBrainer/sso/internal/ports/http/resources"
+	"github.com/JetBrainer/sso/internal/ports/http/resources/admin"
+	authv1 "github.com/JetBrainer/sso/internal/ports/http/resources/auth/v1"
+	"github.com/JetBrainer/sso/pkg/validation"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/jwtauth"
+	"github.com/go-chi/render"
+	"github.com/pkg/errors"
+)
+
+// WebhooksResource предоставляет API подписок внешних систем на события
+// пользователей и журнал отправки событий.
+type WebhooksResource struct {
+	authManager     *auth.Manager
+	webhooksManager *webhooks.Manager
+	audit           *audit.Manager
+	validate        *validation.Validator
+}
+
+func NewWebhooks(authMan *auth.Manager, webhooksMan *webhooks.Manager, auditMan *audit.Manager, validate *validation.Validator) *WebhooksResource {
+	return &WebhooksResource{
+		authManager:     authMan,
+		webhooksManager: webhooksMan,
+		audit:           auditMan,
+		validate:        validate,
+	}
+}
+
+func (wr WebhooksResource) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Group(func(r chi.Router) {
+		r.Use(jwtauth.Verifier(wr.authManager.TokenAuth()))
+		r.Use(authv1.NewUserAccessCtx(wr.authManager.JWTKey()).ChiMiddleware)
+
+		access := authv1.NewPermissionAccessCtx(wr.authManager.PermissionsManager())
+
+		r.With(access.RequirePermission(permissions.ViewWebhooks)).Get("/", wr.Webhooks)
+		r.With(access.RequirePermission(permissions.CreateWebhooks)).Post("/", wr.Create)
+
+		r.With(access.RequirePermission(permissions.ViewWebhooks)).Get("/deliveries", wr.Deliveries)
+		r.With(access.RequirePermission(permissions.ViewWebhooks)).Get("/deliveries/{id}", wr.Delivery)
+		r.With(access.RequirePermission(permissions.UpdateWebhooks)).Post("/deliveries/{id}/redeliver", wr.Redeliver)
+
+		r.With(access.RequirePermission(permissions.ViewWebhooks)).Get("/{id}", wr.Webhook)
+		r.With(access.RequirePermission(permissions.UpdateWebhooks)).Put("/{id}", wr.Update)
+		r.With(access.RequirePermission(permissions.UpdateWebhooks)).Post("/{id}/secret", wr.RotateSecret)
+		r.With(access.RequirePermission(permissions.DeleteWebhooks)).Delete("/{id}", wr.Delete)
+	})
+
+	return r
+}
+
+// @Summary Webhook
+// @Description Возвращает все подписки на события пользователей
+// @Produce json
+// @Tags admin
+// @Security JWT
+// @Success 200 {array} models.Webhook
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/webhooks [get]
+func (wr WebhooksResource) Webhooks(w http.ResponseWriter, r *http.Request) {
+	list, err := wr.webhooksManager.All(r.Context())
+	if err != nil {
+		_ = render.Render(w, r, resources.Internal(err))
+		return
+	}
+
+	render.JSON(w, r, list)
+}
+
+// @Summary Webhook
+// @Description Возвращает подписку по ее идентификатору
+// @Produce json
+// @Tags admin
+// @Security JWT
+// @Param id path string true "Идентификатор webhook"
+// @Success 200 {object} models.Webhook
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/webhooks/{id} [get]
+func (wr WebhooksResource) Webhook(w http.ResponseWriter, r *http.Request) {
+	webhook, err := wr.webhooksManager.ByID(r.Context(), models.PolymorphicIDFromString(chi.URLParam(r, "id")))
+	if err != nil {
+		renderWebhookError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, webhook)
+}
+
+// @Summary Регистрация webhook
+// @Description Подписывает внешнюю систему на события пользователей. Ключ подписи запросов возвращается только в этом ответе.
+// @Accept json
+// @Produce json
+// @Tags admin
+// @Security JWT
+// @Param body body api.WebhookRequest true "Данные webhook"
+// @Success 201 {object} api.WebhookSecretResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Router /admin/webhooks [post]
+func (wr WebhooksResource) Create(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := wr.decodeWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	if err := wr.webhooksManager.Create(r.Context(), webhook); err != nil {
+		renderWebhookError(w, r, err)
+		return
+	}
+
+	wr.audit.Record(r.Context(), &models.AuditEntry{
+		Type:    models.AuditWebhookCreated,
+		Actor:   actor(r),
+		Target:  string(webhook.ID),
+		Changes: audit.Diff(models.Webhook{}, *webhook),
+	})
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, api.WebhookSecretResponse{Webhook: *webhook, Secret: webhook.Secret})
+}
+
+// @Summary Изменение webhook
+// @Description Изменяет адрес, события и состояние подписки
+// @Accept json
+// @Produce json
+// @Tags admin
+// @Security JWT
+// @Param id path string true "Идентификатор webhook"
+// @Param body body api.WebhookRequest true "Данные webhook"
+// @Success 200 {object} models.Webhook
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Router /admin/webhooks/{id} [put]
+func (wr WebhooksResource) Update(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := wr.decodeWebhook(w, r)
+	if !ok {
+		return
+	}
+	webhook.ID = models.PolymorphicIDFromString(chi.URLParam(r, "id"))
+
+	before, err := wr.webhooksManager.ByID(r.Context(), webhook.ID)
+	if err != nil {
+		renderWebhookError(w, r, err)
+		return
+	}
+
+	if err := wr.webhooksManager.Update(r.Context(), webhook); err != nil {
+		renderWebhookError(w, r, err)
+		return
+	}
+
+	wr.audit.Record(r.Context(), &models.AuditEntry{
+		Type:    models.AuditWebhookUpdated,
+		Actor:   actor(r),
+		Target:  string(webhook.ID),
+		Changes: audit.Diff(*before, *webhook),
+	})
+
+	render.JSON(w, r, webhook)
+}
+
+// @Summary Замена ключа подписи
+// @Description Генерирует новый ключ подписи запросов webhook
+// @Produce json
+// @Tags admin
+// @Security JWT
+// @Param id path string true "Идентификатор webhook"
+// @Success 200 {object} api.WebhookSecretResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/webhooks/{id}/secret [post]
+func (wr WebhooksResource) RotateSecret(w http.ResponseWriter, r *http.Request) {
+	webhook, err := wr.webhooksManager.RotateSecret(r.Context(), models.PolymorphicIDFromString(chi.URLParam(r, "id")))
+	if err != nil {
+		renderWebhookError(w, r, err)
+		return
+	}
+
+	wr.audit.Record(r.Context(), &models.AuditEntry{
+		Type:   models.AuditWebhookSecretRotated,
+		Actor:  actor(r),
+		Target: string(webhook.ID),
+	})
+
+	render.JSON(w, r, api.WebhookSecretResponse{Webhook: *webhook, Secret: webhook.Secret})
+}
+
+// @Summary Удаление webhook
+// @Description Удаляет подписку. Неотправленные события ей больше не доставляются.
+// @Produce json
+// @Tags admin
+// @Security JWT
+// @Param id path string true "Идентификатор webhook"
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/webhooks/{id} [delete]
+func (wr WebhooksResource) Delete(w http.ResponseWriter, r *http.Request) {
+	id := models.PolymorphicIDFromString(chi.URLParam(r, "id"))
+	if err := wr.webhooksManager.Delete(r.Context(), id); err != nil {
+		renderWebhookError(w, r, err)
+		return
+	}
+
+	wr.audit.Record(r.Context(), &models.AuditEntry{
+		Type:   models.AuditWebhookDeleted,
+		Actor:  actor(r),
+		Target: string(id),
+	})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Журнал отправок
+// @Description Возвращает отправки событий подписчикам, начиная с самых новых
+// @Produce json
+// @Tags admin
+// @Security JWT
+// @Param webhook query string false "Идентификатор webhook"
+// @Param event query string false "Событие"
+// @Param status query string false "Статус отправки" Enums(pending, sending, delivered, dead)
+// @Param limit query int false "Количество отправок (по умолчанию 50)"
+// @Param offset query int false "Смещение"
+// @Success 200 {array} models.WebhookDelivery
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/webhooks/deliveries [get]
+func (wr WebhooksResource) Deliveries(w http.ResponseWriter, r *http.Request) {
+	filters, err := deliveryFiltersFromQuery(r)
+	if err != nil {
+		_ = render.Render(w, r, resources.BadRequest(err))
+		return
+	}
+
+	deliveries, err := wr.webhooksManager.Deliveries(r.Context(), filters)
+	if err != nil {
+		_ = render.Render(w, r, resources.Internal(err))
+		return
+	}
+
+	render.JSON(w, r, deliveries)
+}
+
+// @Summary Отправка события
+// @Description Возвращает отправку события подписчику по ее идентификатору
+// @Produce json
+// @Tags admin
+// @Security JWT
+// @Param id path string true "Идентификатор отправки"
+// @Success 200 {object} models.WebhookDelivery
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/webhooks/deliveries/{id} [get]
+func (wr WebhooksResource) Delivery(w http.ResponseWriter, r *http.Request) {
+	delivery, err := wr.webhooksManager.Delivery(r.Context(), models.PolymorphicIDFromString(chi.URLParam(r, "id")))
+	if err != nil {
+		renderWebhookError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, delivery)
+}
+
+// @Summary Повторная отправка события
+// @Description Возвращает прекращенную отправку события в очередь
+// @Produce json
+// @Tags admin
+// @Security JWT
+// @Param id path string true "Идентификатор отправки"
+// @Success 200 {object} models.WebhookDelivery
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/webhooks/deliveries/{id}/redeliver [post]
+func (wr WebhooksResource) Redeliver(w http.ResponseWriter, r *http.Request) {
+	delivery, err := wr.webhooksManager.Redeliver(r.Context(), models.PolymorphicIDFromString(chi.URLParam(r, "id")))
+	if err != nil {
+		renderWebhookError(w, r, err)
+		return
+	}
+
+	wr.audit.Record(r.Context(), &models.AuditEntry{
+		Type:   models.AuditWebhookRedelivered,
+		Actor:  actor(r),
+		Target: string(delivery.ID),
+	})
+
+	render.JSON(w, r, delivery)
+}
+
+// decodeWebhook разбирает и валидирует тело запроса. При ошибке ответ уже отправлен.
+func (wr WebhooksResource) decodeWebhook(w http.ResponseWriter, r *http.Request) (*models.Webhook, bool) {
+	var request api.WebhookRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		_ = render.Render(w, r, resources.BadRequest(err))
+		return nil, false
+	}
+
+	if err := wr.validate.Struct(request); err != nil {
+		_ = render.Render(w, r, resources.UnprocessableEntity(err))
+		return nil, false
+	}
+
+	webhook := &models.Webhook{
+		URL:         request.URL,
+		Events:      request.Events,
+		Description: request.Description,
+		Enabled:     true,
+	}
+	if request.Enabled != nil {
+		webhook.Enabled = *request.Enabled
+	}
+
+	return webhook, true
+}
+
+// deliveryFiltersFromQuery извлекает фильтры журнала отправок из параметров запроса.
+func deliveryFiltersFromQuery(r *http.Request) (*models.WebhookDeliveryFilters, error) {
+	query := r.URL.Query()
+	filters := new(models.WebhookDeliveryFilters)
+
+	if webhookID := query.Get("webhook"); webhookID != "" {
+		id := models.PolymorphicIDFromString(webhookID)
+		filters.WebhookID = &id
+	}
+
+	if event := query.Get("event"); event != "" {
+		filters.Event = &event
+	}
+
+	if status := query.Get("status"); status != "" {
+		switch status {
+		case models.WebhookDeliveryPending, models.WebhookDeliverySending, models.WebhookDeliveryDelivered, models.WebhookDeliveryDead:
+			filters.Status = &status
+		default:
+			return nil, admin.ErrUnknownWebhookDeliveryStatus
+		}
+	}
+
+	var err error
+	if limit := query.Get("limit"); limit != "" {
+		if filters.Limit, err = strconv.ParseInt(limit, 10, 64); err != nil {
+			return nil, err
+		}
+	}
+
+	if offset := query.Get("offset"); offset != "" {
+		if filters.Offset, err = strconv.ParseInt(offset, 10, 64); err != nil {
+			return nil, err
+		}
+	}
+
+	return filters, nil
+}
+
+// renderWebhookError отображает ошибки webhook в HTTP ответы.
+func renderWebhookError(w http.ResponseWriter, r *http.Request, err error) {
+	switch errors.Cause(err) {
+	case errors2.ErrInvalidID:
+		_ = render.Render(w, r, resources.BadRequest(err))
+	case errors2.ErrDoesNotExist:
+		_ = render.Render(w, r, resources.ResourceNotFound(err))
+	case webhooks.ErrInvalidURL, webhooks.ErrNoEvents, webhooks.ErrUnknownEvent:
+		_ = render.Render(w, r, resources.UnprocessableEntity(err))
+	case webhooks.ErrDeliveryNotDead:
+		_ = render.Render(w, r, resources.Conflict(err))
+	default:
+		_ = render.Render(w, r, resources.Internal(err))
+	}
+}
@@ -0,0 +1,71 @@
+package resources
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/domain/models"
+)
+
+var ErrUnknownAuditOutcome = errors.New("unknown audit entry outcome")
+
+// AuditFiltersFromQuery извлекает фильтры выборки журнала аудита из
+// параметров запроса. Границы периода from и to задаются в RFC 3339.
+func AuditFiltersFromQuery(r *http.Request) (*models.AuditFilters, error) {
+	query := r.URL.Query()
+	filters := new(models.AuditFilters)
+
+	if tdid := query.Get("tdid"); tdid != "" {
+		filters.TDID = &tdid
+	}
+
+	if actor := query.Get("actor"); actor != "" {
+		filters.Actor = &actor
+	}
+
+	if eventType := query.Get("type"); eventType != "" {
+		filters.Type = &eventType
+	}
+
+	if outcome := query.Get("outcome"); outcome != "" {
+		switch outcome {
+		case models.AuditOutcomeSuccess, models.AuditOutcomeFailure:
+			filters.Outcome = &outcome
+		default:
+			return nil, ErrUnknownAuditOutcome
+		}
+	}
+
+	if from := query.Get("from"); from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return nil, err
+		}
+		filters.From = &t
+	}
+
+	if to := query.Get("to"); to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return nil, err
+		}
+		filters.To = &t
+	}
+
+	var err error
+	if limit := query.Get("limit"); limit != "" {
+		if filters.Limit, err = strconv.ParseInt(limit, 10, 64); err != nil {
+			return nil, err
+		}
+	}
+
+	if offset := query.Get("offset"); offset != "" {
+		if filters.Offset, err = strconv.ParseInt(offset, 10, 64); err != nil {
+			return nil, err
+		}
+	}
+
+	return filters, nil
+}
@@ -0,0 +1,46 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/JetBrainer/sso/internal/domain/manager/auth"
+	"github.com/JetBrainer/sso/internal/ports/http/resources"
+	"github.com/go-chi/render"
+)
+
+var ErrPermissionDenied = errors.New("permission denied")
+
+// PermissionAccessCtx проверяет права пользователя, вычисленные по его ролям.
+type PermissionAccessCtx struct {
+	permissions *auth.PermissionsManager
+}
+
+func NewPermissionAccessCtx(permissions *auth.PermissionsManager) *PermissionAccessCtx {
+	return &PermissionAccessCtx{
+		permissions: permissions,
+	}
+}
+
+// RequirePermission пропускает запрос только если роли пользователя дают право permission.
+// Должен подключаться после UserAccessCtx, который кладет роли в контекст.
+func (pa PermissionAccessCtx) RequirePermission(permission string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			roles, _ := r.Context().Value("roles").([]string)
+
+			allowed, err := pa.permissions.HasPermission(r.Context(), roles, permission)
+			if err != nil {
+				_ = render.Render(w, r, resources.Internal(err))
+				return
+			}
+
+			if !allowed {
+				_ = render.Render(w, r, resources.AccessDenied(ErrPermissionDenied))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/domain/manager/ratelimit"
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/JetBrainer/sso/internal/ports/http/resources"
+	"github.com/JetBrainer/sso/utils"
+	"github.com/go-chi/render"
+)
+
+// maxLoginBodySize - размер тела запроса, в котором ищется логин.
+const maxLoginBodySize = 64 << 10
+
+// RateLimitCtx ограничивает частоту запросов к операциям входа, регистрации
+// и обновления токена по IP адресу клиента, логину и операции в целом.
+type RateLimitCtx struct {
+	limiter *ratelimit.Limiter
+	metrics *models.Metrics
+}
+
+func NewRateLimitCtx(limiter *ratelimit.Limiter, metrics *models.Metrics) *RateLimitCtx {
+	return &RateLimitCtx{
+		limiter: limiter,
+		metrics: metrics,
+	}
+}
+
+// Limit пропускает запрос к операции operation, если для него остались токены,
+// иначе отвечает 429 с заголовком Retry-After. Адрес клиента берется из
+// RemoteAddr, который заголовками X-Forwarded-For и X-Real-IP могут изменить
+// только доверенные прокси.
+func (rl RateLimitCtx) Limit(operation string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if rl.limiter == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ip, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				ip = r.RemoteAddr
+			}
+
+			retryAfter := rl.limiter.Allow(r.Context(), operation, ip, loginFromBody(r))
+			if retryAfter > 0 {
+				rl.metrics.Observe(operation, models.OutcomeRateLimited)
+				setRetryAfter(w, retryAfter)
+				_ = render.Render(w, r, resources.TooManyRequests(ratelimit.ErrRateLimited))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// setRetryAfter сообщает клиенту, через сколько секунд повторить запрос.
+func setRetryAfter(w http.ResponseWriter, d time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(d.Seconds()))))
+}
+
+// loginFromBody находит в JSON теле запроса email, телефон или логин, не
+// расходуя тело для обработчика. Телефон нормализуется, чтобы разные записи
+// одного номера попадали в одну корзину.
+func loginFromBody(r *http.Request) string {
+	if r.Body == nil {
+		return ""
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxLoginBodySize))
+	r.Body = ioutil.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+	if err != nil {
+		return ""
+	}
+
+	var creds struct {
+		Email string `json:"email"`
+		Phone string `json:"phone"`
+		Login string `json:"login"`
+	}
+	if err := json.Unmarshal(body, &creds); err != nil {
+		return ""
+	}
+
+	switch {
+	case creds.Email != "":
+		return creds.Email
+	case creds.Phone != "":
+		return utils.NormPhoneNum(creds.Phone)
+	default:
+		return creds.Login
+	}
+}
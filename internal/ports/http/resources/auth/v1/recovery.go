@@ -0,0 +1,121 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/JetBrainer/sso/internal/domain/manager/auth"
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/JetBrainer/sso/internal/domain/models/api"
+	"github.com/JetBrainer/sso/internal/ports/http/resources"
+	"github.com/go-chi/render"
+)
+
+// @Summary Запрос восстановления доступа по email
+// @Description Отправляет на email код восстановления доступа. Ответ не зависит от того, привязан ли email к учетной записи
+// @Accept json
+// @Produce json
+// @Tags recovery
+// @Param body body api.RecoveryByEmailRequest true "Email учетной записи"
+// @Success 202
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /auth/recovery/email [post]
+func (a AuthResource) RecoveryByEmail(w http.ResponseWriter, r *http.Request) {
+	req := new(api.RecoveryByEmailRequest)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		a.metrics.Observe(models.OperationRecovery, models.OutcomeInvalidRequest)
+		_ = render.Render(w, r, resources.BadRequest(err))
+		return
+	}
+
+	if err := a.validate.Struct(req); err != nil {
+		a.metrics.Observe(models.OperationRecovery, models.OutcomeInvalidRequest)
+		_ = render.Render(w, r, resources.UnprocessableEntity(err))
+		return
+	}
+
+	err := a.authManager.RecoveryManager().RecoveryByEmail(r.Context(), req.Email)
+	switch {
+	case errors.Is(err, auth.ErrEmailNotLinkedToAccount):
+		// неизвестный email не отличается от известного, чтобы по ответу
+		// нельзя было перебирать учетные записи
+	case err != nil:
+		a.metrics.Observe(models.OperationRecovery, models.OutcomeInternalError)
+		_ = render.Render(w, r, resources.Internal(err))
+		return
+	default:
+		a.audit.Record(r.Context(), &models.AuditEntry{
+			Type:  models.AuditRecoveryRequested,
+			TDID:  a.tdidByEmail(req.Email),
+			Login: req.Email,
+		})
+	}
+
+	a.metrics.Observe(models.OperationRecovery, models.OutcomeSuccess)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// @Summary Установка нового пароля по коду восстановления
+// @Description Проверяет код восстановления, отправленный на email, устанавливает новый пароль и снимает блокировку входа после неудачных попыток. Пароль проверяется по политике паролей, нарушенные правила перечисляются в violations ответа 422
+// @Accept json
+// @Produce json
+// @Tags recovery
+// @Param body body api.ResetPasswordRequest true "Код восстановления и новый пароль"
+// @Success 200
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /auth/recovery/password [post]
+func (a AuthResource) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	req := new(api.ResetPasswordRequest)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		a.metrics.Observe(models.OperationRecovery, models.OutcomeInvalidRequest)
+		_ = render.Render(w, r, resources.BadRequest(err))
+		return
+	}
+
+	if err := a.validate.Struct(req); err != nil {
+		a.metrics.Observe(models.OperationRecovery, models.OutcomeInvalidRequest)
+		_ = render.Render(w, r, resources.UnprocessableEntity(err))
+		return
+	}
+
+	user, err := a.authManager.RecoveryManager().ResetPassword(r.Context(), req.Email, req.Code, req.Password)
+	if errors.Is(err, auth.ErrInvalidRecoveryCode) || errors.Is(err, auth.ErrRecoveryTriesExceeded) {
+		a.metrics.Observe(models.OperationRecovery, models.OutcomeInvalidToken)
+		a.audit.Record(r.Context(), &models.AuditEntry{
+			Type:    models.AuditPasswordRecovered,
+			Outcome: models.AuditOutcomeFailure,
+			Reason:  err.Error(),
+			TDID:    a.tdidByEmail(req.Email),
+			Login:   req.Email,
+		})
+		_ = render.Render(w, r, resources.BadRequest(err))
+		return
+	}
+	var policyErr *auth.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		a.metrics.Observe(models.OperationRecovery, models.OutcomeInvalidRequest)
+		_ = render.Render(w, r, resources.UnprocessableRules(err, policyErr.Violations))
+		return
+	}
+	if err != nil {
+		a.metrics.Observe(models.OperationRecovery, models.OutcomeInternalError)
+		_ = render.Render(w, r, resources.Internal(err))
+		return
+	}
+
+	a.metrics.Observe(models.OperationRecovery, models.OutcomeSuccess)
+	a.audit.Record(r.Context(), &models.AuditEntry{
+		Type:  models.AuditPasswordRecovered,
+		Actor: user.ID.Hex(),
+		TDID:  user.ID.Hex(),
+		Login: req.Email,
+	})
+	render.Status(r, http.StatusOK)
+}
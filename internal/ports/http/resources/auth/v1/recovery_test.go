@@ -0,0 +1,128 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	"github.com/JetBrainer/sso/internal/domain/manager/auth"
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/JetBrainer/sso/pkg/validation"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// recoveryStore хранит одного пользователя с восстановлением доступа.
+type recoveryStore struct {
+	drivers.DataStore
+	drivers.OutboxRepository
+	user     *models.User
+	messages []*models.OutboxMessage
+}
+
+func (s *recoveryStore) UserByEmail(_ context.Context, email string) (*models.User, error) {
+	if email != s.user.Email {
+		return nil, drivers.ErrUserDoesNotExist
+	}
+
+	u := *s.user
+	if s.user.Restore != nil {
+		r := *s.user.Restore
+		u.Restore = &r
+	}
+
+	return &u, nil
+}
+
+func (s *recoveryStore) RestoreByEmailNew(_ context.Context, _ primitive.ObjectID, email, token string, expiredAt time.Time) error {
+	s.user.Restore = &models.Restore{Token: token, Email: email, Expired: expiredAt}
+	return nil
+}
+
+func (s *recoveryStore) RestoreAttempt(_ context.Context, _ primitive.ObjectID, limit uint8) (*models.Restore, error) {
+	if s.user.Restore == nil || s.user.Restore.Tries >= limit {
+		return nil, drivers.ErrRestoreTriesExceeded
+	}
+	s.user.Restore.Tries++
+
+	r := *s.user.Restore
+	return &r, nil
+}
+
+func (s *recoveryStore) RestoreClean(context.Context, primitive.ObjectID) error {
+	s.user.Restore.Token = ""
+	return nil
+}
+
+func (s *recoveryStore) UserUpdate(_ context.Context, user *models.User) error {
+	s.user.Password = user.Password
+	return nil
+}
+
+func (s *recoveryStore) LockoutReset(context.Context, primitive.ObjectID) error {
+	s.user.Lockout = nil
+	return nil
+}
+
+func (s *recoveryStore) Outbox() drivers.OutboxRepository {
+	return s
+}
+
+func (s *recoveryStore) Enqueue(_ context.Context, msg *models.OutboxMessage) error {
+	s.messages = append(s.messages, msg)
+	return nil
+}
+
+func (s *recoveryStore) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestAuthResource_Recovery(t *testing.T) {
+	store := &recoveryStore{user: &models.User{
+		ID:      primitive.NewObjectID(),
+		Login:   "user@example.com",
+		Email:   "user@example.com",
+		Enabled: true,
+		Lockout: &models.Lockout{Locked: true, Until: time.Now().Add(time.Hour)},
+	}}
+	authMan := auth.New(store, []byte("secret"), time.Minute, time.Hour, time.Minute)
+	authMan.Testing()
+	a := NewAuth(authMan, nil, nil, nil, validation.New())
+
+	do := func(h http.HandlerFunc, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+		return w
+	}
+
+	// ответ на запрос кода не выдает, привязан ли email к учетной записи
+	w := do(a.RecoveryByEmail, `{"email":"nobody@example.com"}`)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Empty(t, store.messages)
+
+	w = do(a.RecoveryByEmail, `{"email":"user@example.com"}`)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	if !assert.Len(t, store.messages, 1) {
+		return
+	}
+	code := store.messages[0].Code
+
+	w = do(a.ResetPassword, `{"email":"user@example.com","code":"0","password":"battery staple"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.NotNil(t, store.user.Lockout)
+
+	// пароль проверяется по политике паролей
+	w = do(a.ResetPassword, `{"email":"user@example.com","code":"`+code+`","password":"short"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "violations")
+
+	// верный код устанавливает пароль и снимает блокировку входа
+	w = do(a.ResetPassword, `{"email":"user@example.com","code":"`+code+`","password":"battery staple"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, store.user.Lockout)
+	assert.True(t, auth.CheckPasswordHash("battery staple", []byte(store.user.Password)))
+}
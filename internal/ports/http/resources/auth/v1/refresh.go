@@ -7,6 +7,7 @@ import (
 	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
 	errors2 "github.com/JetBrainer/sso/internal/domain/errors"
 	"github.com/JetBrainer/sso/internal/domain/manager/auth"
+	"github.com/JetBrainer/sso/internal/domain/models"
 	"github.com/JetBrainer/sso/internal/domain/models/api"
 	"github.com/JetBrainer/sso/internal/ports/http/resources"
 	"github.com/JetBrainer/sso/internal/ports/http/resources/profile"
@@ -23,22 +24,20 @@ import (
 // @Success 200 {object} api.NewJWTTokenResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
+// @Failure 429 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /auth/refresh [post]
 func (a AuthResource) Refresh(w http.ResponseWriter, r *http.Request) {
 	var request api.RefreshRequest
 
-	// метрики: общее количество запросов
-	if a.metrics != nil && a.metrics.RefreshRequests != nil {
-		(*a.metrics.RefreshRequests).Add(1)
-	}
-
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		a.metrics.Observe(models.OperationRefresh, models.OutcomeInvalidRequest)
 		_ = render.Render(w, r, resources.BadRequest(err))
 		return
 	}
 
 	if request.RefreshToken == "" {
+		a.metrics.Observe(models.OperationRefresh, models.OutcomeInvalidRequest)
 		_ = render.Render(w, r, resources.BadRequest(drivers.ErrTokenNotSpec))
 		return
 	}
@@ -51,6 +50,7 @@ func (a AuthResource) Refresh(w http.ResponseWriter, r *http.Request) {
 		return a.authManager.JWTKey(), nil
 	})
 	if err != nil {
+		a.metrics.Observe(models.OperationRefresh, models.OutcomeInvalidToken)
 		if err == jwt.ErrSignatureInvalid {
 			_ = render.Render(w, r, resources.Unauthorized(err))
 			return
@@ -61,42 +61,37 @@ func (a AuthResource) Refresh(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if !tkn.Valid {
-		if a.metrics != nil && a.metrics.RefreshInvalidTokenErrors != nil {
-			(*a.metrics.RefreshInvalidTokenErrors).Add(1)
-		}
+		a.metrics.Observe(models.OperationRefresh, models.OutcomeInvalidToken)
 		_ = render.Render(w, r, resources.Unauthorized(errors2.ErrTokenDoesNotExist))
 		return
 	}
 
 	if claims.TDID == "" {
-		if a.metrics != nil && a.metrics.RefreshInvalidTokenErrors != nil {
-			(*a.metrics.RefreshInvalidTokenErrors).Add(1)
-		}
+		a.metrics.Observe(models.OperationRefresh, models.OutcomeInvalidToken)
 		_ = render.Render(w, r, resources.BadRequest(profile.ErrUnknownTDID))
 		return
 	}
 
 	if !claims.IsRefresh {
-		if a.metrics != nil && a.metrics.RefreshInvalidTokenErrors != nil {
-			(*a.metrics.RefreshInvalidTokenErrors).Add(1)
-		}
+		a.metrics.Observe(models.OperationRefresh, models.OutcomeInvalidToken)
 		_ = render.Render(w, r, resources.Unauthorized(ErrInvalidToken))
 		return
 	}
 
 	var newToken string
-	newToken, err = a.authManager.NewAccessToken(claims.TDID)
+	newToken, err = a.authManager.NewAccessToken(r.Context(), claims.TDID)
 	if err != nil {
-		if a.metrics != nil && a.metrics.RefreshInternalServerErrors != nil {
-			(*a.metrics.RefreshInternalServerErrors).Add(1)
-		}
+		a.metrics.Observe(models.OperationRefresh, models.OutcomeInternalError)
 		_ = render.Render(w, r, resources.Internal(err))
 		return
 	}
 
-	if a.metrics != nil && a.metrics.RefreshSuccessfulOperation != nil {
-		(*a.metrics.RefreshSuccessfulOperation).Add(1)
-	}
+	a.metrics.Observe(models.OperationRefresh, models.OutcomeSuccess)
+	a.audit.Record(r.Context(), &models.AuditEntry{
+		Type:  models.AuditRefresh,
+		Actor: claims.TDID,
+		TDID:  claims.TDID,
+	})
 
 	render.JSON(w, r, api.NewJWTTokenResponse{
 		AccessToken: newToken,
@@ -1,7 +1,9 @@
 package v1
 
 import (
+	"github.com/JetBrainer/sso/internal/domain/manager/audit"
 	"github.com/JetBrainer/sso/internal/domain/manager/auth"
+	"github.com/JetBrainer/sso/internal/domain/manager/ratelimit"
 	"github.com/JetBrainer/sso/internal/domain/models"
 	"github.com/JetBrainer/sso/pkg/validation"
 	"github.com/go-chi/chi"
@@ -11,12 +13,16 @@ import (
 type AuthResource struct {
 	metrics     *models.Metrics
 	authManager *auth.Manager
+	audit       *audit.Manager
+	limit       *RateLimitCtx
 	validate    *validation.Validator
 }
 
-func NewAuth(authMan *auth.Manager, metrics *models.Metrics, validate *validation.Validator) *AuthResource {
+func NewAuth(authMan *auth.Manager, auditMan *audit.Manager, limiter *ratelimit.Limiter, metrics *models.Metrics, validate *validation.Validator) *AuthResource {
 	return &AuthResource{
 		authManager: authMan,
+		audit:       auditMan,
+		limit:       NewRateLimitCtx(limiter, metrics),
 		validate:    validate,
 		metrics:     metrics,
 	}
@@ -36,9 +42,11 @@ func (a AuthResource) Routes() chi.Router {
 	})
 
 	r.Group(func(r chi.Router) {
-		r.Post("/signin/email", a.SignInByEmail)
-		r.Put("/signup", a.SignUP)
-		r.Post("/refresh", a.Refresh)
+		r.With(a.limit.Limit(models.OperationSignIn)).Post("/signin/email", a.SignInByEmail)
+		r.With(a.limit.Limit(models.OperationSignUp)).Put("/signup", a.SignUP)
+		r.With(a.limit.Limit(models.OperationRefresh)).Post("/refresh", a.Refresh)
+		r.With(a.limit.Limit(models.OperationRecovery)).Post("/recovery/email", a.RecoveryByEmail)
+		r.With(a.limit.Limit(models.OperationRecovery)).Post("/recovery/password", a.ResetPassword)
 	})
 
 	return r
@@ -2,15 +2,19 @@ package v1
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
+	"github.com/JetBrainer/sso/internal/domain/manager/auth"
+	"github.com/JetBrainer/sso/internal/domain/models"
 	"github.com/JetBrainer/sso/internal/domain/models/api"
 	"github.com/JetBrainer/sso/internal/ports/http/resources"
 	"github.com/go-chi/render"
 )
 
 // @Summary Вход по email
-// @Description Проверяет пользовательский email и пароль, выписывает JWT токен
+// @Description Проверяет пользовательский email и пароль, выписывает JWT токен.
+// @Description После нескольких неудачных попыток вход откладывается, а затем учетная запись временно блокируется (429 с заголовком Retry-After)
 // @Accept json
 // @Produce json
 // @Tags auth
@@ -18,12 +22,14 @@ import (
 // @Success 200 {object} api.NewJWTTokenResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
+// @Failure 429 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /auth/signin/email [post]
 func (a AuthResource) SignInByEmail(w http.ResponseWriter, r *http.Request) {
 	var creds api.SignInByEmailRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
+		a.metrics.Observe(models.OperationSignIn, models.OutcomeInvalidRequest)
 		_ = render.Render(w, r, resources.BadRequest(err))
 		return
 	}
@@ -32,26 +38,68 @@ func (a AuthResource) SignInByEmail(w http.ResponseWriter, r *http.Request) {
 
 	// проверяем наличие пользователя и его пароль
 	tdid, err := loginManager.SignInByEmail(creds.Email, creds.Password)
+	var lockErr *auth.LockoutError
+	if errors.As(err, &lockErr) {
+		a.metrics.Observe(models.OperationSignIn, models.OutcomeLocked)
+		a.audit.Record(r.Context(), &models.AuditEntry{
+			Type:    models.AuditSignIn,
+			Outcome: models.AuditOutcomeFailure,
+			Reason:  err.Error(),
+			TDID:    a.tdidByEmail(creds.Email),
+			Login:   creds.Email,
+		})
+		setRetryAfter(w, lockErr.RetryAfter)
+		_ = render.Render(w, r, resources.TooManyRequests(err))
+		return
+	}
 	if err != nil {
+		a.metrics.Observe(models.OperationSignIn, models.OutcomeInvalidCredentials)
+		a.audit.Record(r.Context(), &models.AuditEntry{
+			Type:    models.AuditSignIn,
+			Outcome: models.AuditOutcomeFailure,
+			Reason:  err.Error(),
+			TDID:    a.tdidByEmail(creds.Email),
+			Login:   creds.Email,
+		})
 		_ = render.Render(w, r, resources.Unauthorized(err))
 		return
 	}
 
-	token, err := a.authManager.NewAccessToken(tdid)
+	token, err := a.authManager.NewAccessToken(r.Context(), tdid)
 	if err != nil {
+		a.metrics.Observe(models.OperationSignIn, models.OutcomeInternalError)
 		_ = render.Render(w, r, resources.Internal(err))
 		return
 	}
 
-	refreshToken, err := a.authManager.NewRefreshToken(tdid)
+	refreshToken, err := a.authManager.NewRefreshToken(r.Context(), tdid)
 	if err != nil {
+		a.metrics.Observe(models.OperationSignIn, models.OutcomeInternalError)
 		_ = render.Render(w, r, resources.Internal(err))
 		return
 	}
 
+	a.metrics.Observe(models.OperationSignIn, models.OutcomeSuccess)
+	a.audit.Record(r.Context(), &models.AuditEntry{
+		Type:  models.AuditSignIn,
+		Actor: tdid,
+		TDID:  tdid,
+		Login: creds.Email,
+	})
 	render.JSON(w, r, api.NewJWTTokenResponse{
 		AccessToken:  token,
 		RefreshToken: refreshToken,
 		Status:       "SignIn success",
 	})
 }
+
+// tdidByEmail находит владельца учетной записи, чтобы неудачная попытка входа
+// попала в его журнал активности. Для неизвестного email возвращает пустую строку.
+func (a AuthResource) tdidByEmail(email string) string {
+	user, err := a.authManager.Users().ByEmail(email)
+	if err != nil || user == nil {
+		return ""
+	}
+
+	return user.ID.Hex()
+}
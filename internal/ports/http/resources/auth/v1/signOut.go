@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/JetBrainer/sso/internal/domain/models"
 	"github.com/go-chi/render"
 )
 
@@ -26,5 +27,12 @@ func (a AuthResource) SignOut(w http.ResponseWriter, r *http.Request) {
 		Expires:  time.Now().In(time.UTC).Add(-tokenTTL),
 	})
 
+	tdid, _ := r.Context().Value("tdid").(string)
+	a.audit.Record(r.Context(), &models.AuditEntry{
+		Type:  models.AuditSignOut,
+		Actor: tdid,
+		TDID:  tdid,
+	})
+
 	render.Status(r, http.StatusOK)
 }
@@ -2,8 +2,11 @@ package v1
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
+	authman "github.com/JetBrainer/sso/internal/domain/manager/auth"
+	"github.com/JetBrainer/sso/internal/domain/models"
 	"github.com/JetBrainer/sso/internal/domain/models/api"
 	"github.com/JetBrainer/sso/internal/ports/http/resources"
 	"github.com/JetBrainer/sso/internal/ports/http/resources/auth"
@@ -12,7 +15,7 @@ import (
 )
 
 // @Summary Регистрация
-// @Description Создает нового пользователя в SSO
+// @Description Создает нового пользователя в SSO. Пароль проверяется по политике паролей, нарушенные правила перечисляются в violations ответа 422
 // @Accept json
 // @Produce json
 // @Tags auth
@@ -20,25 +23,20 @@ import (
 // @Success 200 {object} api.SignUPResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 422 {object} models.ErrorResponse
+// @Failure 429 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /auth/signup [put]
 func (a AuthResource) SignUP(w http.ResponseWriter, r *http.Request) {
 	var sur api.SignUPRequest
 
-	// метрики: общее количество запросов
-	if a.metrics != nil && a.metrics.SignUpByPhoneRequests != nil {
-		(*a.metrics.SignUpByPhoneRequests).Add(1)
-	}
-
 	if err := json.NewDecoder(r.Body).Decode(&sur); err != nil {
+		a.metrics.Observe(models.OperationSignUp, models.OutcomeInvalidRequest)
 		_ = render.Render(w, r, resources.BadRequest(err))
 		return
 	}
 
 	//if err := a.validate.Struct(sur); err != nil {
-	//	if a.metrics != nil && a.metrics.SignUpValidationErrors != nil {
-	//		(*a.metrics.SignUpValidationErrors).Add(1)
-	//	}
+	//	a.metrics.Observe(models.OperationSignUp, models.OutcomeInvalidRequest)
 	//
 	////	uniqueRules := []string{"unique_email", "unique_phones", "unique_phone"}
 	////	for _, rule := range uniqueRules {
@@ -54,23 +52,32 @@ func (a AuthResource) SignUP(w http.ResponseWriter, r *http.Request) {
 
 	normPhone := utils.NormPhoneNum(sur.Phone)
 	if normPhone == "" {
+		a.metrics.Observe(models.OperationSignUp, models.OutcomeInvalidRequest)
 		_ = render.Render(w, r, resources.UnprocessableEntity(auth.ErrInvalidNumberFormat))
 		return
 	}
 
 	user, err := a.authManager.Users().CreateFromSUR(&sur)
+	var policyErr *authman.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		a.metrics.Observe(models.OperationSignUp, models.OutcomeInvalidRequest)
+		_ = render.Render(w, r, resources.UnprocessableRules(err, policyErr.Violations))
+		return
+	}
 	if err != nil {
-		if a.metrics != nil && a.metrics.SignUpInternalServerErrors != nil {
-			(*a.metrics.SignUpInternalServerErrors).Add(1)
-		}
+		a.metrics.Observe(models.OperationSignUp, models.OutcomeInternalError)
 		_ = render.Render(w, r, resources.Internal(auth.ErrServerProblem))
 		return
 	}
 
+	a.metrics.Observe(models.OperationSignUp, models.OutcomeSuccess)
+	a.audit.Record(r.Context(), &models.AuditEntry{
+		Type:  models.AuditSignUp,
+		Actor: user.ID.Hex(),
+		TDID:  user.ID.Hex(),
+		Login: sur.Email,
+	})
 	render.Status(r, http.StatusCreated)
-	if a.metrics != nil && a.metrics.SignUpSuccessfulOperation != nil {
-		(*a.metrics.SignUpSuccessfulOperation).Add(1)
-	}
 
 	// все ок, возвращаем созданный ID пользователя и ждем верификации по этому TDID
 	render.JSON(w, r, api.SignUPResponse{
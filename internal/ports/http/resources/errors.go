@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/JetBrainer/sso/internal/domain/models"
 	"github.com/go-chi/render"
 )
 
@@ -12,10 +13,11 @@ var (
 )
 
 type Response struct {
-	Err            error             `json:"-" ` // низкоуровневая ошибка исполнения
-	HTTPStatusCode int               `json:"-"`  // HTTP статус код
-	ErrorMessage   *Details          `json:"error"`
-	Validation     map[string]string `json:"validation,omitempty"` // ошибки валидации
+	Err            error                  `json:"-" ` // низкоуровневая ошибка исполнения
+	HTTPStatusCode int                    `json:"-"`  // HTTP статус код
+	ErrorMessage   *Details               `json:"error"`
+	Validation     map[string]string      `json:"validation,omitempty"` // ошибки валидации
+	Violations     []models.RuleViolation `json:"violations,omitempty"` // нарушенные правила, например политики паролей
 }
 
 type Details struct {
@@ -54,6 +56,20 @@ func UnprocessableEntity(err error) render.Renderer {
 	}
 }
 
+// UnprocessableRules отвечает 422 со списком нарушенных правил валидации.
+func UnprocessableRules(err error, violations []models.RuleViolation) render.Renderer {
+	return &Response{
+		Err:            err,
+		HTTPStatusCode: http.StatusUnprocessableEntity,
+		ErrorMessage: &Details{
+			AppCode:     http.StatusUnprocessableEntity,
+			StatusText:  http.StatusText(http.StatusUnprocessableEntity),
+			MessageText: err.Error(),
+		},
+		Violations: violations,
+	}
+}
+
 // Неправильный запрос.
 // Возникает тогда, когда к запросу переданы неверные параметры.
 func InvalidRequest(err error) render.Renderer {
@@ -81,6 +97,19 @@ func ResourceNotFound(err error) render.Renderer {
 	}
 }
 
+// Конфликт с текущим состоянием ресурса.
+func Conflict(err error) render.Renderer {
+	return &Response{
+		Err:            err,
+		HTTPStatusCode: http.StatusConflict,
+		ErrorMessage: &Details{
+			AppCode:     http.StatusConflict,
+			StatusText:  http.StatusText(http.StatusConflict),
+			MessageText: err.Error(),
+		},
+	}
+}
+
 func TooManyRequests(err error) render.Renderer {
 	return &Response{
 		Err:            err,
@@ -154,4 +183,4 @@ func BadRequest(err error) render.Renderer {
 			MessageText: err.Error(),
 		},
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,8 @@
+package events
+
+import "errors"
+
+var (
+	ErrUnknownTDID       = errors.New("unknown user TDID")
+	ErrUnknownActionType = errors.New("unknown action type")
+)
@@ -0,0 +1,189 @@
+package v1
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	errors2 "github.com/JetBrainer/sso/internal/domain/errors"
+	"github.com/JetBrainer/sso/internal/domain/manager/auth"
+	"github.com/JetBrainer/sso/internal/domain/manager/events"
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/JetBrainer/sso/internal/domain/models/api"
+	"github.com/JetBrainer/sso/internal/ports/http/resources"
+	"github.com/JetBrainer/sso/utils"
+	"github.com/go-chi/render"
+	"github.com/pkg/errors"
+)
+
+// @Summary Состояние подтверждения действия
+// @Description Возвращает текущее состояние подтверждения действия пользователем
+// @Produce json
+// @Tags events
+// @Security JWT
+// @Param actionType path string true "Тип действия"
+// @Success 200 {object} api.EventResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /events/{actionType} [get]
+func (er EventsResource) Event(w http.ResponseWriter, r *http.Request) {
+	tdid, actionType, err := userAction(r)
+	if err != nil {
+		_ = render.Render(w, r, resources.BadRequest(err))
+		return
+	}
+
+	event, err := er.eventsManager.Events().ByUserAction(r.Context(), tdid, actionType)
+	if err != nil {
+		renderEventError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, eventResponse(event))
+}
+
+// @Summary Начать подтверждение действия
+// @Description Создает событие подтверждения и отправляет одноразовый пароль на основной телефон пользователя
+// @Produce json
+// @Tags events
+// @Security JWT
+// @Param actionType path string true "Тип действия"
+// @Success 201 {object} api.EventResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Failure 429 {object} auth.Response
+// @Failure 500 {object} models.ErrorResponse
+// @Router /events/{actionType} [post]
+func (er EventsResource) Start(w http.ResponseWriter, r *http.Request) {
+	tdid, actionType, err := userAction(r)
+	if err != nil {
+		_ = render.Render(w, r, resources.BadRequest(err))
+		return
+	}
+
+	event, err := er.eventsManager.Events().Start(r.Context(), tdid, actionType)
+	if err != nil {
+		if errors.Cause(err) == errors2.ErrTooManyRequests && event != nil && event.Verify != nil {
+			penalty := int(math.Ceil(time.Until(event.Verify.NextAttemptAt).Seconds()))
+			_ = render.Render(w, r, auth.TooManyRequests(err, penalty))
+			return
+		}
+
+		renderEventError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, eventResponse(event))
+}
+
+// @Summary Подтвердить действие
+// @Description Проверяет одноразовый пароль и помечает действие подтвержденным
+// @Accept json
+// @Produce json
+// @Tags events
+// @Security JWT
+// @Param actionType path string true "Тип действия"
+// @Param body body api.EventVerifyRequest true "Одноразовый пароль"
+// @Success 200 {object} api.EventResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /events/{actionType}/verify [post]
+func (er EventsResource) Verify(w http.ResponseWriter, r *http.Request) {
+	tdid, actionType, err := userAction(r)
+	if err != nil {
+		_ = render.Render(w, r, resources.BadRequest(err))
+		return
+	}
+
+	req := new(api.EventVerifyRequest)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		_ = render.Render(w, r, resources.BadRequest(err))
+		return
+	}
+
+	if err := er.validate.Struct(req); err != nil {
+		_ = render.Render(w, r, resources.UnprocessableEntity(err))
+		return
+	}
+
+	event, err := er.eventsManager.Events().Verify(r.Context(), tdid, actionType, req.Token)
+	if err != nil {
+		renderEventError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, eventResponse(event))
+}
+
+// @Summary Отменить подтверждение действия
+// @Description Удаляет событие подтверждения действия
+// @Tags events
+// @Security JWT
+// @Param actionType path string true "Тип действия"
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /events/{actionType} [delete]
+func (er EventsResource) Cancel(w http.ResponseWriter, r *http.Request) {
+	tdid, actionType, err := userAction(r)
+	if err != nil {
+		_ = render.Render(w, r, resources.BadRequest(err))
+		return
+	}
+
+	if err := er.eventsManager.Events().Cancel(r.Context(), tdid, actionType); err != nil {
+		renderEventError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// renderEventError отображает ошибки подтверждения действий в HTTP ответы.
+func renderEventError(w http.ResponseWriter, r *http.Request, err error) {
+	switch errors.Cause(err) {
+	case errors2.ErrInvalidID, errors2.ErrOTPNotSpec:
+		_ = render.Render(w, r, resources.BadRequest(err))
+	case errors2.ErrDoesNotExist, errors2.ErrTokenDoesNotExist, drivers.ErrUserDoesNotExist:
+		_ = render.Render(w, r, resources.ResourceNotFound(err))
+	case events.ErrInvalidOTP, errors2.ErrTokenHasExpired, errors2.ErrTokenTriesExpired:
+		_ = render.Render(w, r, resources.AccessDenied(err))
+	case events.ErrPhoneNotSpecified:
+		_ = render.Render(w, r, resources.UnprocessableEntity(err))
+	case events.ErrTokenGenerationsExpired, errors2.ErrTooManyRequests:
+		_ = render.Render(w, r, resources.TooManyRequests(err))
+	default:
+		_ = render.Render(w, r, resources.Internal(err))
+	}
+}
+
+func eventResponse(event *models.Event) api.EventResponse {
+	resp := api.EventResponse{
+		ID:         string(event.ID),
+		ActionType: event.ActionType,
+		ExpiresAt:  event.ExpiresAt,
+	}
+
+	if event.Verify != nil {
+		resp.Status = event.Verify.Status
+		resp.Phone = utils.MaskPhoneNum(event.Verify.Phone)
+		if event.Verify.Tries < events.TriesLimit {
+			resp.TriesLeft = events.TriesLimit - int(event.Verify.Tries)
+		}
+		if !event.Verify.NextAttemptAt.IsZero() {
+			nextAttemptAt := event.Verify.NextAttemptAt
+			resp.NextAttemptAt = &nextAttemptAt
+		}
+	}
+
+	return resp
+}
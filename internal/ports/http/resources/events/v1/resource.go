@@ -0,0 +1,119 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/JetBrainer/sso/internal/domain/manager/auth"
+	"github.com/JetBrainer/sso/internal/domain/manager/events"
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/JetBrainer/sso/internal/ports/http/resources"
+	authv1 "github.com/JetBrainer/sso/internal/ports/http/resources/auth/v1"
+	events2 "github.com/JetBrainer/sso/internal/ports/http/resources/events"
+	"github.com/JetBrainer/sso/pkg/logger"
+	"github.com/JetBrainer/sso/pkg/validation"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/jwtauth"
+	"github.com/go-chi/render"
+)
+
+// EventsResource позволяет пользователю подтверждать чувствительные действия
+// одноразовым паролем.
+type EventsResource struct {
+	authManager   *auth.Manager
+	eventsManager *events.Manager
+	validate      *validation.Validator
+}
+
+func NewEvents(authMan *auth.Manager, eventsMan *events.Manager, validate *validation.Validator) *EventsResource {
+	return &EventsResource{
+		authManager:   authMan,
+		eventsManager: eventsMan,
+		validate:      validate,
+	}
+}
+
+func (er EventsResource) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Group(func(r chi.Router) {
+		r.Use(jwtauth.Verifier(er.authManager.TokenAuth()))
+		r.Use(authv1.NewUserAccessCtx(er.authManager.JWTKey()).ChiMiddleware)
+
+		r.Get("/{actionType}", er.Event)
+		r.Post("/{actionType}", er.Start)
+		r.Post("/{actionType}/verify", er.Verify)
+		r.Delete("/{actionType}", er.Cancel)
+	})
+
+	return r
+}
+
+// RequireConfirmation пропускает запрос только если пользователь подтвердил
+// действие actionType одноразовым паролем. Подтверждение расходуется, только
+// если операция выполнена успешно (ответ 2xx), так что ошибка в запросе не
+// требует нового подтверждения. Пока тип действия не зарегистрирован оператором,
+// подтверждение не требуется. Должен подключаться после UserAccessCtx.
+func RequireConfirmation(eventsMan *events.Manager, actionType string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if eventsMan == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tdid, _ := r.Context().Value("tdid").(string)
+			if tdid == "" {
+				_ = render.Render(w, r, resources.BadRequest(events2.ErrUnknownTDID))
+				return
+			}
+
+			required, err := eventsMan.Actions().Registered(r.Context(), actionType)
+			if err != nil {
+				_ = render.Render(w, r, resources.Internal(err))
+				return
+			}
+			if !required {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			err = eventsMan.Events().Confirmed(r.Context(), models.PolymorphicID(tdid), actionType)
+			switch {
+			case err == nil:
+			case err == events.ErrActionNotConfirmed:
+				_ = render.Render(w, r, resources.AccessDenied(err))
+				return
+			default:
+				_ = render.Render(w, r, resources.Internal(err))
+				return
+			}
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			// без явного статуса ответ отправляется с кодом 200
+			if status := ww.Status(); status != 0 && (status < 200 || status > 299) {
+				return
+			}
+
+			if err := eventsMan.Events().Consume(r.Context(), models.PolymorphicID(tdid), actionType); err != nil {
+				logger.Printf(r.Context(), "[WARN] cannot consume %s confirmation of %s: %v", actionType, tdid, err)
+			}
+		})
+	}
+}
+
+// userAction извлекает TDID пользователя из контекста и тип действия из пути запроса.
+func userAction(r *http.Request) (models.PolymorphicID, string, error) {
+	tdid, _ := r.Context().Value("tdid").(string)
+	if tdid == "" {
+		return "", "", events2.ErrUnknownTDID
+	}
+
+	actionType := chi.URLParam(r, "actionType")
+	if actionType == "" {
+		return "", "", events2.ErrUnknownActionType
+	}
+
+	return models.PolymorphicID(tdid), actionType, nil
+}
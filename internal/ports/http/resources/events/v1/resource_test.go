@@ -0,0 +1,84 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/JetBrainer/sso/internal/adapters/database/drivers"
+	"github.com/JetBrainer/sso/internal/domain"
+	errors2 "github.com/JetBrainer/sso/internal/domain/errors"
+	"github.com/JetBrainer/sso/internal/domain/manager/events"
+	"github.com/JetBrainer/sso/internal/domain/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// confirmStore хранит одно подтвержденное действие пользователя. Все типы
+// действий считаются зарегистрированными.
+type confirmStore struct {
+	drivers.DataStore
+	drivers.EventsRepository
+
+	event *models.Event
+}
+
+func (s *confirmStore) Actions() drivers.ActionsRepository { return registeredActions{} }
+func (s *confirmStore) Events() drivers.EventsRepository   { return s }
+
+type registeredActions struct {
+	drivers.ActionsRepository
+}
+
+func (registeredActions) ByType(_ context.Context, actionType string) (*models.Action, error) {
+	return &models.Action{Type: actionType}, nil
+}
+
+func (s *confirmStore) ByUserAction(context.Context, models.PolymorphicID, string) (*models.Event, error) {
+	if s.event == nil {
+		return nil, errors2.ErrDoesNotExist
+	}
+
+	return s.event, nil
+}
+
+func (s *confirmStore) DeleteByID(context.Context, models.PolymorphicID) error {
+	s.event = nil
+	return nil
+}
+
+func TestRequireConfirmation(t *testing.T) {
+	expiresAt := time.Now().Add(time.Minute)
+	store := &confirmStore{event: &models.Event{
+		ID:        "60a7c0b4f1d3a2b1c0d9e8f7",
+		ExpiresAt: &expiresAt,
+		Verify:    &models.Verify{Status: domain.TokenStatusFinish},
+	}}
+
+	status := http.StatusUnprocessableEntity
+	handler := RequireConfirmation(events.New(store), models.ActionChangePassword)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}),
+	)
+
+	serve := func() int {
+		r := httptest.NewRequest(http.MethodPut, "/password", nil)
+		r = r.WithContext(context.WithValue(r.Context(), "tdid", "60a7c0b4f1d3a2b1c0d9e8f6"))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// ошибка операции не расходует подтверждение
+	assert.Equal(t, http.StatusUnprocessableEntity, serve())
+	assert.NotNil(t, store.event)
+
+	// успешная операция расходует
+	status = http.StatusOK
+	assert.Equal(t, http.StatusOK, serve())
+	assert.Nil(t, store.event)
+
+	assert.Equal(t, http.StatusForbidden, serve())
+}
@@ -0,0 +1,300 @@
+package v2
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/JetBrainer/sso/internal/ports/http/resources"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"github.com/pkg/errors"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+const (
+	// BasePath - префикс путей в аннотациях, под которым монтируется шлюз.
+	BasePath = "/api/v2"
+
+	// maxBodySize ограничивает размер тела запроса к шлюзу.
+	maxBodySize = 1 << 20
+)
+
+// GatewayResource - REST шлюз к gRPC сервису. Маршруты строятся по
+// аннотациям google.api.http методов сервиса, вызовы выполняются внутри
+// процесса через те же перехватчики, что и у gRPC сервера, поэтому
+// аутентификация, права и ошибки у обоих транспортов совпадают.
+type GatewayResource struct {
+	service     protoreflect.ServiceDescriptor
+	server      interface{}
+	interceptor grpc.UnaryServerInterceptor
+
+	marshal   protojson.MarshalOptions
+	unmarshal protojson.UnmarshalOptions
+}
+
+// NewGateway создает шлюз к сервису с полным именем service, реализованному
+// server. Методы с путями вне BasePath и потоковые методы не публикуются.
+func NewGateway(service protoreflect.FullName, server interface{}, interceptor grpc.UnaryServerInterceptor) (*GatewayResource, error) {
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(service)
+	if err != nil {
+		return nil, errors.Wrapf(err, "gateway service %s", service)
+	}
+
+	sd, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, errors.Errorf("%s is not a service", service)
+	}
+
+	return &GatewayResource{
+		service:     sd,
+		server:      server,
+		interceptor: interceptor,
+		marshal:     protojson.MarshalOptions{UseProtoNames: true},
+		unmarshal:   protojson.UnmarshalOptions{DiscardUnknown: true},
+	}, nil
+}
+
+func (gr *GatewayResource) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	methods := gr.service.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		md := methods.Get(i)
+		rule, ok := proto.GetExtension(md.Options(), annotations.E_Http).(*annotations.HttpRule)
+		if !ok || rule == nil || md.IsStreamingClient() || md.IsStreamingServer() {
+			continue
+		}
+
+		verb, path := httpPattern(rule)
+		if verb == "" || !strings.HasPrefix(path, BasePath+"/") {
+			continue
+		}
+
+		r.Method(verb, strings.TrimPrefix(path, BasePath), gr.handler(md, rule.Body))
+	}
+
+	return r
+}
+
+// handler собирает запрос из тела, параметров пути и строки запроса и
+// вызывает метод md.
+func (gr *GatewayResource) handler(md protoreflect.MethodDescriptor, body string) http.HandlerFunc {
+	fullMethod := "/" + string(gr.service.FullName()) + "/" + string(md.Name())
+	method := reflect.ValueOf(gr.server).MethodByName(string(md.Name()))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		mt, err := protoregistry.GlobalTypes.FindMessageByName(md.Input().FullName())
+		if err != nil {
+			_ = render.Render(w, r, resources.Internal(err))
+			return
+		}
+		req := mt.New().Interface()
+
+		if body == "*" {
+			data, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+			if err != nil {
+				_ = render.Render(w, r, resources.BadRequest(err))
+				return
+			}
+			if len(data) > 0 {
+				if err := gr.unmarshal.Unmarshal(data, req); err != nil {
+					_ = render.Render(w, r, resources.BadRequest(err))
+					return
+				}
+			}
+		} else if err := populateQuery(req.ProtoReflect(), r); err != nil {
+			_ = render.Render(w, r, resources.BadRequest(err))
+			return
+		}
+
+		if err := populatePath(req.ProtoReflect(), chi.RouteContext(r.Context())); err != nil {
+			_ = render.Render(w, r, resources.BadRequest(err))
+			return
+		}
+
+		resp, err := gr.interceptor(incomingContext(r), req, &grpc.UnaryServerInfo{Server: gr.server, FullMethod: fullMethod},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				out := method.Call([]reflect.Value{reflect.ValueOf(ctx), reflect.ValueOf(req)})
+				if err, _ := out[1].Interface().(error); err != nil {
+					return nil, err
+				}
+				return out[0].Interface(), nil
+			})
+		if err != nil {
+			_ = render.Render(w, r, statusError(err))
+			return
+		}
+
+		data, err := gr.marshal.Marshal(resp.(proto.Message))
+		if err != nil {
+			_ = render.Render(w, r, resources.Internal(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(data)
+	}
+}
+
+func httpPattern(rule *annotations.HttpRule) (string, string) {
+	switch p := rule.Pattern.(type) {
+	case *annotations.HttpRule_Get:
+		return http.MethodGet, p.Get
+	case *annotations.HttpRule_Post:
+		return http.MethodPost, p.Post
+	case *annotations.HttpRule_Put:
+		return http.MethodPut, p.Put
+	case *annotations.HttpRule_Delete:
+		return http.MethodDelete, p.Delete
+	case *annotations.HttpRule_Patch:
+		return http.MethodPatch, p.Patch
+	default:
+		return "", ""
+	}
+}
+
+// incomingContext передает заголовок Authorization в метаданные gRPC.
+func incomingContext(r *http.Request) context.Context {
+	md := metadata.MD{}
+	if v := r.Header.Get("Authorization"); v != "" {
+		md.Set("authorization", v)
+	}
+
+	return metadata.NewIncomingContext(r.Context(), md)
+}
+
+// populatePath заполняет поля запроса параметрами пути.
+func populatePath(msg protoreflect.Message, rctx *chi.Context) error {
+	if rctx == nil {
+		return nil
+	}
+
+	for i, key := range rctx.URLParams.Keys {
+		if err := setField(msg, key, []string{rctx.URLParams.Values[i]}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// populateQuery заполняет поля запроса параметрами строки запроса.
+// Неизвестные параметры игнорируются.
+func populateQuery(msg protoreflect.Message, r *http.Request) error {
+	for key, values := range r.URL.Query() {
+		if err := setField(msg, key, values); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setField записывает значения в скалярное или повторяющееся поле верхнего
+// уровня с именем name (в proto или JSON написании).
+func setField(msg protoreflect.Message, name string, values []string) error {
+	fields := msg.Descriptor().Fields()
+	fd := fields.ByName(protoreflect.Name(name))
+	if fd == nil {
+		fd = fields.ByJSONName(name)
+	}
+	if fd == nil || fd.IsMap() || len(values) == 0 {
+		return nil
+	}
+
+	if fd.IsList() {
+		list := msg.Mutable(fd).List()
+		for _, raw := range values {
+			v, err := scalar(fd, raw)
+			if err != nil {
+				return err
+			}
+			list.Append(v)
+		}
+		return nil
+	}
+
+	v, err := scalar(fd, values[len(values)-1])
+	if err != nil {
+		return err
+	}
+	msg.Set(fd, v)
+
+	return nil
+}
+
+func scalar(fd protoreflect.FieldDescriptor, raw string) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(raw), nil
+	case protoreflect.BoolKind:
+		v, err := strconv.ParseBool(raw)
+		return protoreflect.ValueOfBool(v), errors.Wrapf(err, "parameter %s", fd.Name())
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		v, err := strconv.ParseInt(raw, 10, 32)
+		return protoreflect.ValueOfInt32(int32(v)), errors.Wrapf(err, "parameter %s", fd.Name())
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		v, err := strconv.ParseInt(raw, 10, 64)
+		return protoreflect.ValueOfInt64(v), errors.Wrapf(err, "parameter %s", fd.Name())
+	default:
+		return protoreflect.Value{}, errors.Errorf("parameter %s of type %s is not supported", fd.Name(), fd.Kind())
+	}
+}
+
+// statusError переводит статус gRPC в ответ с тем же форматом ошибки, что и в /api/v1.
+func statusError(err error) render.Renderer {
+	st := status.Convert(err)
+	code := httpStatus(st.Code())
+
+	return &resources.Response{
+		Err:            err,
+		HTTPStatusCode: code,
+		ErrorMessage: &resources.Details{
+			AppCode:     int64(code),
+			StatusText:  http.StatusText(code),
+			MessageText: st.Message(),
+		},
+	}
+}
+
+// httpStatus сопоставляет коды gRPC с HTTP статусами.
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499 // клиент закрыл соединение
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
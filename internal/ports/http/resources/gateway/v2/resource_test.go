@@ -0,0 +1,124 @@
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JetBrainer/sso/pkg/protobuf"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type ssoStub struct {
+	protobuf.UnimplementedSSOServer
+}
+
+func (ssoStub) GetUser(_ context.Context, req *protobuf.GetUserRequest) (*protobuf.User, error) {
+	if req.Tdid == "missing" {
+		return nil, status.Error(codes.NotFound, "user does not exist")
+	}
+	return &protobuf.User{Id: req.Tdid, Firstname: "Ivan"}, nil
+}
+
+func (ssoStub) GetUsers(_ context.Context, req *protobuf.GetUsersRequest) (*protobuf.GetUsersResponse, error) {
+	resp := new(protobuf.GetUsersResponse)
+	for _, id := range req.Tdid {
+		resp.Users = append(resp.Users, &protobuf.User{Id: id})
+	}
+	return resp, nil
+}
+
+func (ssoStub) FullNamesByTDID(_ context.Context, req *protobuf.FullNamesByTDIDRequest) (*protobuf.FullNamesByTDIDResponse, error) {
+	return &protobuf.FullNamesByTDIDResponse{FullNames: map[string]string{"a": "Ivanov"}}, nil
+}
+
+func (ssoStub) ValidateAccessToken(_ context.Context, req *protobuf.ValidateAccessTokenRequest) (*protobuf.ValidateAccessTokenResponse, error) {
+	return &protobuf.ValidateAccessTokenResponse{Tdid: req.Token}, nil
+}
+
+func newTestGateway(t *testing.T) (http.Handler, *[]string) {
+	var calls []string
+	interceptor := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if auth := md.Get("authorization"); len(auth) > 0 && auth[0] != "Bearer good" {
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+		calls = append(calls, info.FullMethod)
+		return handler(ctx, req)
+	}
+
+	gw, err := NewGateway("sso.pkg.go.SSO", &ssoStub{}, interceptor)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return gw.Routes(), &calls
+}
+
+func TestGateway_Routes(t *testing.T) {
+	routes, calls := newTestGateway(t)
+
+	tests := []struct {
+		method, target, body string
+		code                 int
+		want                 string
+	}{
+		{http.MethodGet, "/users/abc", "", http.StatusOK, `"id":"abc"`},
+		{http.MethodGet, "/users?tdid=a&tdid=b", "", http.StatusOK, `"users":[{"id":"a"},{"id":"b"}]`},
+		{http.MethodGet, "/users/full-names?tdid=a", "", http.StatusOK, `"full_names":{"a":"Ivanov"}`},
+		{http.MethodPost, "/tokens/validate", `{"token":"xyz"}`, http.StatusOK, `"tdid":"xyz"`},
+		{http.MethodGet, "/users/missing", "", http.StatusNotFound, `"code":404`},
+		{http.MethodGet, "/users/abc/devices", "", http.StatusNotImplemented, `"code":501`},
+		{http.MethodPost, "/tokens/validate", `{"token":`, http.StatusBadRequest, `"code":400`},
+		{http.MethodGet, "/tokens/xyz", "", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		routes.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+
+		if rec.Code != tt.code {
+			t.Errorf("%s %s: code %d, want %d (%s)", tt.method, tt.target, rec.Code, tt.code, rec.Body)
+		}
+		// protojson намеренно вставляет случайные пробелы в вывод
+		if body := strings.ReplaceAll(rec.Body.String(), " ", ""); !strings.Contains(body, tt.want) {
+			t.Errorf("%s %s: body %s does not contain %s", tt.method, tt.target, body, tt.want)
+		}
+	}
+
+	if len(*calls) == 0 || (*calls)[0] != "/sso.pkg.go.SSO/GetUser" {
+		t.Errorf("interceptor calls %v", *calls)
+	}
+}
+
+func TestGateway_Authorization(t *testing.T) {
+	routes, _ := newTestGateway(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/abc", nil)
+	req.Header.Set("Authorization", "Bearer bad")
+	rec := httptest.NewRecorder()
+	routes.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("code %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+
+	var resp struct {
+		Error struct {
+			Status string `json:"status"`
+			Code   int    `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Error.Code != http.StatusUnauthorized || resp.Error.Status != http.StatusText(http.StatusUnauthorized) {
+		t.Errorf("error %+v", resp.Error)
+	}
+}
@@ -1,13 +1,16 @@
 package resources
 
 import (
+	"context"
 	"crypto/rand"
 	"fmt"
-	"log"
 	"net/http"
+	"time"
 
+	"github.com/JetBrainer/sso/internal/adapters/notifier"
 	"github.com/JetBrainer/sso/internal/domain/manager/monitoring"
 	"github.com/JetBrainer/sso/internal/domain/models/api"
+	"github.com/JetBrainer/sso/pkg/logger"
 	"github.com/go-chi/chi"
 	"github.com/go-chi/render"
 )
@@ -25,55 +28,80 @@ func NewHealth(manager *monitoring.Manager) *MonitoringResource {
 func (mr MonitoringResource) Routes() chi.Router {
 	r := chi.NewRouter()
 	r.Get("/", mr.HealthCheck)
+	r.Get("/live", mr.Live)
+	r.Get("/ready", mr.HealthCheck)
 
 	return r
 }
 
-// HealthCheck проверка работоспособности контейнера:
-// Проверку жизни БД, нотификатора, Restore директора.
-func (mr *MonitoringResource) HealthCheck(w http.ResponseWriter, r *http.Request) {
-	var err error
-	health := mr.manager.Health()
-
-	err = health.CheckDatabase()
-	if err != nil {
-		_ = render.Render(w, r, NewHealthResponse(api.HealthStatusNotOK))
-		return
-	}
-
-	err = health.CheckNotificator()
-	if err != nil {
-		_ = render.Render(w, r, NewHealthResponse(api.HealthStatusNotOK))
-		return
-	}
+// Live проверка жизни процесса: отвечает, пока сервер обрабатывает
+// запросы, и не зависит от внешних компонентов.
+func (mr *MonitoringResource) Live(w http.ResponseWriter, r *http.Request) {
+	resp := NewHealthResponse(r.Context(), mr.manager.Node(), api.HealthStatusOK)
+	resp.CheckID, resp.Name = "serfLive", "Serf Liveness Status"
+	_ = render.Render(w, r, resp)
+}
 
-	err = health.CheckRestoreDirector()
-	if err != nil {
-		_ = render.Render(w, r, NewHealthResponse(api.HealthStatusNotOK))
-		return
-	}
+// HealthCheck проверка готовности обслуживать запросы: хранилища,
+// нотификатора, ключей подписи и фоновых демонов.
+func (mr *MonitoringResource) HealthCheck(w http.ResponseWriter, r *http.Request) {
+	report := mr.manager.Health().Ready(r.Context())
 
-	_ = render.Render(w, r, NewHealthResponse(api.HealthStatusOK))
+	resp := NewHealthResponse(r.Context(), mr.manager.Node(), report.Status)
+	resp.Checks = healthChecks(report.Checks)
+	resp.Notifiers = notifierStatuses(report.Notifiers)
+	_ = render.Render(w, r, resp)
 }
 
 // NewHealthResponse создает новые ответ
-func NewHealthResponse(status string) *api.HealthResponse {
+func NewHealthResponse(ctx context.Context, node, status string) *api.HealthResponse {
 	return &api.HealthResponse{
-		ID:      generateUID(),
-		Node:    "foobar",
+		ID:      generateUID(ctx),
+		Node:    node,
 		Name:    "Serf Health Status",
 		CheckID: "serfHealth",
 		Status:  status,
 	}
 }
 
+// healthChecks переводит результаты проверок компонентов в ответ.
+func healthChecks(checks []monitoring.Check) []api.HealthCheck {
+	resp := make([]api.HealthCheck, 0, len(checks))
+	for _, c := range checks {
+		resp = append(resp, api.HealthCheck{
+			Name:         c.Name,
+			Status:       c.Status,
+			Output:       c.Output,
+			LatencyMS:    float64(c.Latency) / float64(time.Millisecond),
+			LastRunAgeMS: float64(c.Age) / float64(time.Millisecond),
+		})
+	}
+
+	return resp
+}
+
+// notifierStatuses переводит состояние провайдеров уведомлений в ответ.
+func notifierStatuses(statuses []notifier.ProviderStatus) []api.NotifierStatus {
+	resp := make([]api.NotifierStatus, 0, len(statuses))
+	for _, s := range statuses {
+		resp = append(resp, api.NotifierStatus{
+			Name:      s.Name,
+			State:     s.State,
+			Failures:  s.Failures,
+			LastError: s.LastError,
+		})
+	}
+
+	return resp
+}
+
 // generateUID генерирует уникальный id
-func generateUID() string {
+func generateUID(ctx context.Context) string {
 	b := make([]byte, 16)
 	_, err := rand.Read(b)
 
 	if err != nil {
-		log.Printf("[Error] " + err.Error())
+		logger.Printf(ctx, "[ERROR] cannot generate uid: %v", err)
 	}
 
 	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
@@ -0,0 +1,46 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/JetBrainer/sso/internal/ports/http/resources"
+	"github.com/JetBrainer/sso/internal/ports/http/resources/profile"
+	"github.com/go-chi/render"
+)
+
+// @Summary Активность
+// @Description Возвращает события журнала аудита по своему аккаунту, начиная с самых новых: входы, в том числе неудачные, и изменения профиля
+// @Produce json
+// @Tags profile
+// @Security JWT
+// @Param type query string false "Тип события, например auth.sign_in"
+// @Param outcome query string false "Результат" Enums(success, failure)
+// @Param from query string false "Начало периода, RFC 3339"
+// @Param to query string false "Конец периода, RFC 3339"
+// @Param limit query int false "Количество записей (по умолчанию 50)"
+// @Param offset query int false "Смещение"
+// @Success 200 {array} models.AuditEntry
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /profile/activity [get]
+func (p ProfileResource) Activity(w http.ResponseWriter, r *http.Request) {
+	tdid, _ := r.Context().Value("tdid").(string)
+	if tdid == "" {
+		_ = render.Render(w, r, resources.BadRequest(profile.ErrUnknownTDID))
+		return
+	}
+
+	filters, err := resources.AuditFiltersFromQuery(r)
+	if err != nil {
+		_ = render.Render(w, r, resources.BadRequest(err))
+		return
+	}
+
+	entries, err := p.audit.Activity(r.Context(), tdid, filters)
+	if err != nil {
+		_ = render.Render(w, r, resources.Internal(err))
+		return
+	}
+
+	render.JSON(w, r, entries)
+}
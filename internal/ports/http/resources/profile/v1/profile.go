@@ -43,12 +43,19 @@ func (p ProfileResource) Profile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	permissions, err := p.authManager.PermissionsManager().ByRoles(r.Context(), user.Roles)
+	if err != nil {
+		_ = render.Render(w, r, resources.Internal(err))
+		return
+	}
+
 	strID, _ := tdid.(string)
 	render.JSON(w, r, api.ProfileResponse{
 		Created:      user.Created,
 		Updated:      user.Updated,
 		BirthDate:    user.BirthDate,
 		Roles:        user.Roles,
+		Permissions:  permissions,
 		Receivers:    user.Receivers,
 		FirstName:    user.FirstName,
 		LastName:     user.LastName,
@@ -1,24 +1,30 @@
 package v1
 
 import (
+	"github.com/JetBrainer/sso/internal/domain/manager/audit"
 	"github.com/JetBrainer/sso/internal/domain/manager/auth"
+	"github.com/JetBrainer/sso/internal/domain/manager/events"
 	"github.com/JetBrainer/sso/internal/domain/models"
-	"github.com/JetBrainer/sso/internal/domain/models/api"
 	"github.com/JetBrainer/sso/internal/ports/http/resources/auth/v1"
+	eventsv1 "github.com/JetBrainer/sso/internal/ports/http/resources/events/v1"
 	"github.com/JetBrainer/sso/pkg/validation"
 	"github.com/go-chi/chi"
 	"github.com/go-chi/jwtauth"
 )
 
 type ProfileResource struct {
-	authManager  *auth.Manager
-	validate     *validation.Validator
+	authManager   *auth.Manager
+	eventsManager *events.Manager
+	audit         *audit.Manager
+	validate      *validation.Validator
 }
 
-func NewProfile(authMan *auth.Manager, validate *validation.Validator) *ProfileResource {
+func NewProfile(authMan *auth.Manager, eventsMan *events.Manager, auditMan *audit.Manager, validate *validation.Validator) *ProfileResource {
 	return &ProfileResource{
-		authManager:  authMan,
-		validate:     validate,
+		authManager:   authMan,
+		eventsManager: eventsMan,
+		audit:         auditMan,
+		validate:      validate,
 	}
 }
 
@@ -30,9 +36,11 @@ func (p ProfileResource) Routes() chi.Router {
 		r.Use(v1.NewUserAccessCtx(p.authManager.JWTKey()).ChiMiddleware)
 
 		r.Get("/", p.Profile)
-		r.Put("/", p.ProfileUpdate)
+		r.With(eventsv1.RequireConfirmation(p.eventsManager, models.ActionUpdateProfile)).Put("/", p.ProfileUpdate)
 
-		r.Put("/password", p.UpdatePassword)
+		r.With(eventsv1.RequireConfirmation(p.eventsManager, models.ActionChangePassword)).Put("/password", p.UpdatePassword)
+
+		r.Get("/activity", p.Activity)
 
 		r.Get("/receivers", p.Receivers)
 		r.Get("/addresses", p.ReceiversAddress)
@@ -41,37 +49,6 @@ func (p ProfileResource) Routes() chi.Router {
 	return r
 }
 
-func userProfileRequestMerge(request *api.ProfileUpdateRequest, user *models.User) *models.User {
-	if request.FirstName != nil {
-		user.FirstName = *request.FirstName
-	}
-
-	if request.LastName != nil {
-		user.LastName = *request.LastName
-	}
-
-	if request.Patronymic != nil {
-		user.Patronymic = *request.Patronymic
-	}
-
-	if request.Email != nil {
-		user.Email = *request.Email
-	}
-
-	if request.Language != nil {
-		user.Language = *request.Language
-	}
-
-	if request.Sex != nil {
-		user.Sex = *request.Sex
-	}
-
-	if request.IIN != nil {
-		user.IIN = *request.IIN
-	}
-	return user
-}
-
 func profileKindByUser(user models.User) string {
 	if user.Password == "" {
 		return "fast"
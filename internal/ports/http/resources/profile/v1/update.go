@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/JetBrainer/sso/internal/domain/manager/audit"
 	"github.com/JetBrainer/sso/internal/domain/models"
 	"github.com/JetBrainer/sso/internal/domain/models/api"
 	"github.com/JetBrainer/sso/internal/ports/http/resources"
@@ -78,10 +79,20 @@ func (p ProfileResource) ProfileUpdate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// детектим изменения, обновляем структуру user и сохраняем
-	if err := users.Update(userProfileRequestMerge(&request, user)); err != nil {
+	before := *user
+	if err := users.Update(request.Merge(user)); err != nil {
 		_ = render.Render(w, r, resources.BadRequest(err))
 		return
 	}
 
+	if changes := audit.Diff(before, *user); len(changes) > 0 {
+		p.audit.Record(r.Context(), &models.AuditEntry{
+			Type:    models.AuditProfileUpdated,
+			Actor:   id.Hex(),
+			TDID:    id.Hex(),
+			Changes: changes,
+		})
+	}
+
 	render.Status(r, http.StatusOK)
 }
@@ -2,8 +2,11 @@ package v1
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
+	"github.com/JetBrainer/sso/internal/domain/manager/auth"
+	"github.com/JetBrainer/sso/internal/domain/models"
 	"github.com/JetBrainer/sso/internal/domain/models/api"
 	"github.com/JetBrainer/sso/internal/ports/http/resources"
 	"github.com/JetBrainer/sso/internal/ports/http/resources/profile"
@@ -12,7 +15,7 @@ import (
 )
 
 // @Summary Смена пароля
-// @Description Позволяет обновить пользователю пароль
+// @Description Позволяет обновить пользователю пароль. Пароль проверяется по политике паролей, нарушенные правила перечисляются в violations ответа 422
 // @Accept json
 // @Produce json
 // @Tags profile
@@ -51,11 +54,22 @@ func (p ProfileResource) UpdatePassword(w http.ResponseWriter, r *http.Request)
 
 	users := p.authManager.Users()
 
-	if err = users.UpdatePassword(id, request.Password); err != nil {
+	err = users.UpdatePassword(id, request.Password)
+	var policyErr *auth.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		_ = render.Render(w, r, resources.UnprocessableRules(err, policyErr.Violations))
+		return
+	}
+	if err != nil {
 		_ = render.Render(w, r, resources.ResourceNotFound(err))
 		return
 	}
 
+	p.audit.Record(r.Context(), &models.AuditEntry{
+		Type:  models.AuditPasswordChanged,
+		Actor: id.Hex(),
+		TDID:  id.Hex(),
+	})
 
 	render.Status(r, http.StatusOK)
 }
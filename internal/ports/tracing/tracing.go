@@ -0,0 +1,113 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+)
+
+// Экспортеры трассировки.
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+
+	ServiceName = "sso"
+
+	// DefaultEndpoint - адрес OTLP/HTTP приемника локального коллектора.
+	DefaultEndpoint = "http://localhost:4318"
+)
+
+var (
+	ErrUnknownExporter = errors.New("unknown tracing exporter")
+	ErrInvalidEndpoint = errors.New("tracing endpoint must be an http or https URL")
+)
+
+// Config содержит настройки трассировки.
+type Config struct {
+	Exporter    string  // none, otlp или stdout
+	Endpoint    string  // адрес OTLP/HTTP коллектора
+	SampleRatio float64 // доля трассируемых запросов без родительского контекста, от 0 до 1
+	Version     string  // версия приложения
+}
+
+// Setup настраивает глобальные TracerProvider и W3C trace-context
+// пропагатор. Пропагатор устанавливается всегда, чтобы контекст входящих
+// запросов передавался дальше даже с выключенным экспортом. Возвращаемая
+// функция отправляет накопленные спаны и останавливает экспорт.
+func Setup(conf Config) (func(ctx context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+	switch strings.TrimSpace(conf.Exporter) {
+	case ExporterNone, "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		exporter, err = newOTLPExporter(conf.Endpoint)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New()
+	default:
+		return nil, ErrUnknownExporter
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceNameKey.String(ServiceName),
+		semconv.ServiceVersionKey.String(conf.Version),
+	)
+
+	ratio := conf.SampleRatio
+	if ratio <= 0 || ratio > 1 {
+		ratio = 1
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	log.Printf("[INFO] tracing spans are exported to %s", conf.Exporter)
+
+	return provider.Shutdown, nil
+}
+
+// newOTLPExporter создает экспортер спанов по OTLP/HTTP на
+// <endpoint>/v1/traces.
+func newOTLPExporter(endpoint string) (sdktrace.SpanExporter, error) {
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidEndpoint, endpoint)
+	}
+
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(u.Host),
+		otlptracehttp.WithURLPath(strings.TrimRight(u.Path, "/") + "/v1/traces"),
+	}
+	if u.Scheme == "http" {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+
+	return otlptracehttp.New(context.Background(), opts...)
+}
@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ctxKeyRequestID struct{}
+
+// WithRequestID сохраняет идентификатор запроса в контексте.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKeyRequestID{}, id)
+}
+
+// RequestID возвращает идентификатор запроса из контекста или пустую строку.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKeyRequestID{}).(string)
+	return id
+}
+
+// NewRequestID генерирует случайный идентификатор запроса.
+func NewRequestID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+// Printf пишет запись журнала, дополняя ее идентификаторами запроса и
+// трассировки из ctx. Уровень задается меткой, как и для стандартного журнала.
+func Printf(ctx context.Context, format string, v ...interface{}) {
+	level, msg := splitLevel(fmt.Sprintf(format, v...))
+	_ = output().write(level, msg, contextFields(ctx))
+}
+
+func contextFields(ctx context.Context) []Field {
+	var fields []Field
+	if id := RequestID(ctx); id != "" {
+		fields = append(fields, Field{Key: "request_id", Value: id})
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		fields = append(fields, Field{Key: "trace_id", Value: sc.TraceID().String()})
+	}
+
+	return fields
+}
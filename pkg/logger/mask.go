@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/JetBrainer/sso/utils"
+)
+
+const masked = "***"
+
+var (
+	// значения полей с паролями, токенами и секретами в JSON, query и key=value
+	secretRegexp = regexp.MustCompile(`(?i)(\b(?:password|new_password|old_password|passwd|token|access_token|refresh_token|secret|authorization)"?\s*[:=]\s*"?)(?:bearer\s+)?[^\s"',&;}]+`)
+	// одноразовые коды
+	otpRegexp    = regexp.MustCompile(`(?i)(\b(?:otp|code|verify_code|sms_code)"?\s*[:=]\s*"?)\d{4,8}\b`)
+	bearerRegexp = regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]+=*`)
+	jwtRegexp    = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	phoneRegexp  = regexp.MustCompile(`\+?\b[78]\d{10}\b`)
+)
+
+// Mask скрывает в сообщении номера телефонов, пароли, одноразовые коды и токены.
+func Mask(msg string) string {
+	msg = secretRegexp.ReplaceAllString(msg, "${1}"+masked)
+	msg = otpRegexp.ReplaceAllString(msg, "${1}"+masked)
+	msg = bearerRegexp.ReplaceAllString(msg, "Bearer "+masked)
+	msg = jwtRegexp.ReplaceAllString(msg, masked)
+
+	return phoneRegexp.ReplaceAllStringFunc(msg, maskPhone)
+}
+
+func maskPhone(phone string) string {
+	if m := utils.MaskPhoneNum(phone); strings.Contains(m, "*") {
+		return m
+	}
+
+	// номер не распознан как казахстанский, оставляем код страны и последние цифры
+	return phone[:len(phone)-8] + strings.Repeat("*", 6) + phone[len(phone)-2:]
+}
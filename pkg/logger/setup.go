@@ -1,25 +1,43 @@
 package logger
 
 import (
+	"errors"
 	stdlog "log"
 	"os"
+	"strings"
+)
+
+// Форматы журнала.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
 
-	"github.com/hashicorp/logutils"
+var (
+	ErrUnknownLevel  = errors.New("unknown log level")
+	ErrUnknownFormat = errors.New("unknown log format")
 )
 
-func Setup(mode logutils.LogLevel) {
-	filter := &logutils.LevelFilter{
-		Levels:   []logutils.LogLevel{"DEBUG", "WARN", "ERROR"},
-		MinLevel: mode,
-		Writer:   os.Stdout,
+// Setup направляет стандартный журнал в Writer с уровнем level и форматом
+// format. Уровень строки определяется меткой вида [ERROR] в начале
+// сообщения, строки без метки считаются INFO.
+func Setup(level, format string) error {
+	lvl, ok := parseLevel(strings.ToUpper(strings.TrimSpace(level)))
+	if !ok {
+		return ErrUnknownLevel
 	}
 
-	switch mode {
-	case "DEBUG":
-		stdlog.SetFlags(stdlog.Ldate | stdlog.Ltime | stdlog.Lmicroseconds | stdlog.Lshortfile)
-	default:
-		stdlog.SetFlags(stdlog.Ldate | stdlog.Ltime)
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != FormatText && format != FormatJSON {
+		return ErrUnknownFormat
 	}
 
-	stdlog.SetOutput(filter)
+	w := NewWriter(os.Stdout, lvl, format)
+	std.Store(w)
+
+	// время и уровень пишет Writer
+	stdlog.SetFlags(0)
+	stdlog.SetOutput(w)
+
+	return nil
 }
@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Level уровень важности записи журнала.
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = [...]string{"DEBUG", "INFO", "WARN", "ERROR"}
+
+func (l Level) String() string {
+	return levelNames[l]
+}
+
+func parseLevel(s string) (Level, bool) {
+	for i, name := range levelNames {
+		if s == name {
+			return Level(i), true
+		}
+	}
+
+	return LevelInfo, false
+}
+
+// std - Writer, в который пишут стандартный журнал и Printf.
+// До вызова Setup записи выводятся в stderr в текстовом виде.
+var std atomic.Value
+
+func init() {
+	std.Store(NewWriter(os.Stderr, LevelDebug, FormatText))
+}
+
+func output() *Writer {
+	return std.Load().(*Writer)
+}
+
+// Field дополнительное поле записи журнала.
+type Field struct {
+	Key   string
+	Value string
+}
+
+// Writer пишет записи журнала уровня не ниже min в текстовом виде или
+// по одному объекту JSON на строку. Секреты в сообщениях маскируются.
+type Writer struct {
+	mu     sync.Mutex
+	w      io.Writer
+	min    Level
+	format string
+	now    func() time.Time
+}
+
+func NewWriter(w io.Writer, min Level, format string) *Writer {
+	return &Writer{w: w, min: min, format: format, now: time.Now}
+}
+
+// Write принимает строки стандартного журнала.
+func (w *Writer) Write(p []byte) (int, error) {
+	level, msg := splitLevel(strings.TrimRight(string(p), "\n"))
+	if err := w.write(level, msg, nil); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+func (w *Writer) write(level Level, msg string, fields []Field) error {
+	if level < w.min {
+		return nil
+	}
+
+	msg = Mask(msg)
+	ts := w.now()
+
+	var buf bytes.Buffer
+	if w.format == FormatJSON {
+		entry := make(map[string]string, len(fields)+3)
+		for _, f := range fields {
+			entry[f.Key] = f.Value
+		}
+		entry["time"] = ts.UTC().Format(time.RFC3339Nano)
+		entry["level"] = level.String()
+		entry["msg"] = msg
+
+		if err := json.NewEncoder(&buf).Encode(entry); err != nil {
+			return err
+		}
+	} else {
+		buf.WriteString(ts.Format("2006/01/02 15:04:05.000000"))
+		buf.WriteString(" [" + level.String() + "] ")
+		buf.WriteString(msg)
+		for _, f := range fields {
+			buf.WriteString(" " + f.Key + "=" + f.Value)
+		}
+		buf.WriteByte('\n')
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, err := w.w.Write(buf.Bytes())
+	return err
+}
+
+// splitLevel отделяет метку уровня от сообщения.
+func splitLevel(line string) (Level, string) {
+	if !strings.HasPrefix(line, "[") {
+		return LevelInfo, line
+	}
+
+	end := strings.IndexByte(line, ']')
+	if end < 0 {
+		return LevelInfo, line
+	}
+
+	level, ok := parseLevel(strings.ToUpper(line[1:end]))
+	if !ok {
+		return LevelInfo, line
+	}
+
+	return level, strings.TrimLeft(line[end+1:], " ")
+}